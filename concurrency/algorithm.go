@@ -0,0 +1,112 @@
+package concurrency
+
+import (
+	"math"
+	"time"
+)
+
+// AIMD additive-increase/multiplicative-decrease limit algorithm.
+// The limit is increased by one after each successful request if the limiter is
+// at least half used, and multiplied by `Backoff` when a request is dropped or
+// when its latency exceeds `Latency`.
+type AIMD struct {
+	// Latency the latency above which the limit is decreased. 0 disables the latency check.
+	Latency time.Duration
+
+	// Backoff the factor applied to the limit when decreasing it. Defaults to 0.9.
+	Backoff float64
+
+	// Min the minimum limit. Defaults to 1.
+	Min int
+
+	// Max the maximum limit. 0 means no maximum.
+	Max int
+}
+
+// Update returns the new limit after the given sample was observed.
+func (a *AIMD) Update(sample Sample) int {
+	limit := sample.Limit
+	if sample.Dropped || (a.Latency > 0 && sample.RTT > a.Latency) {
+		backoff := a.Backoff
+		if backoff <= 0 || backoff >= 1 {
+			backoff = 0.9
+		}
+		limit = int(float64(limit) * backoff)
+	} else if sample.InFlight*2 >= sample.Limit {
+		limit++
+	}
+	return clampLimit(limit, a.Min, a.Max)
+}
+
+// Gradient limit algorithm adjusting the limit from the ratio between the long-term
+// average latency and the latency of each request. When the latency increases (the
+// service is queuing work), the limit decreases proportionally. A queue allowance
+// of the square root of the limit lets the limit grow when the latency is stable.
+type Gradient struct {
+	// Tolerance the latency increase ratio tolerated before the limit is decreased.
+	// Defaults to 1.5.
+	Tolerance float64
+
+	// Smoothing the weight of each new limit in the estimated limit, between 0 and 1.
+	// Defaults to 0.2.
+	Smoothing float64
+
+	// Min the minimum limit. Defaults to 1.
+	Min int
+
+	// Max the maximum limit. 0 means no maximum.
+	Max int
+
+	longRTT  float64
+	estimate float64
+}
+
+// Update returns the new limit after the given sample was observed.
+func (g *Gradient) Update(sample Sample) int {
+	rtt := float64(sample.RTT)
+	if rtt <= 0 {
+		rtt = 1
+	}
+	if g.longRTT == 0 {
+		g.longRTT = rtt
+	} else {
+		g.longRTT = g.longRTT*0.95 + rtt*0.05
+	}
+	if g.estimate == 0 {
+		g.estimate = float64(sample.Limit)
+	}
+
+	tolerance := g.Tolerance
+	if tolerance < 1 {
+		tolerance = 1.5
+	}
+	smoothing := g.Smoothing
+	if smoothing <= 0 || smoothing > 1 {
+		smoothing = 0.2
+	}
+
+	gradient := 0.5
+	if !sample.Dropped {
+		gradient = math.Max(0.5, math.Min(1, tolerance*g.longRTT/rtt))
+	}
+	newLimit := g.estimate*gradient + math.Sqrt(g.estimate)
+	if float64(sample.InFlight) < g.estimate/2 {
+		// The application doesn't use the current limit: don't grow it.
+		newLimit = math.Min(newLimit, g.estimate)
+	}
+	g.estimate = g.estimate*(1-smoothing) + newLimit*smoothing
+
+	limit := clampLimit(int(g.estimate), g.Min, g.Max)
+	if float64(limit) > g.estimate || (g.Max > 0 && g.estimate > float64(g.Max)) {
+		g.estimate = float64(limit)
+	}
+	return limit
+}
+
+func clampLimit(limit, minLimit, maxLimit int) int {
+	limit = max(limit, minLimit, 1)
+	if maxLimit > 0 {
+		limit = min(limit, maxLimit)
+	}
+	return limit
+}
@@ -0,0 +1,60 @@
+package concurrency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAIMD(t *testing.T) {
+	a := &AIMD{Latency: 100 * time.Millisecond, Min: 2, Max: 11}
+
+	assert.Equal(t, 11, a.Update(Sample{Limit: 10, InFlight: 5, RTT: time.Millisecond}))
+	assert.Equal(t, 11, a.Update(Sample{Limit: 11, InFlight: 11, RTT: time.Millisecond})) // Max
+	assert.Equal(t, 10, a.Update(Sample{Limit: 10, InFlight: 4, RTT: time.Millisecond}))  // Under-used
+	assert.Equal(t, 9, a.Update(Sample{Limit: 10, InFlight: 10, RTT: time.Second}))
+	assert.Equal(t, 9, a.Update(Sample{Limit: 10, InFlight: 10, Dropped: true}))
+	assert.Equal(t, 2, a.Update(Sample{Limit: 2, InFlight: 2, Dropped: true})) // Min
+
+	a = &AIMD{Backoff: 0.5}
+	assert.Equal(t, 5, a.Update(Sample{Limit: 10, Dropped: true}))
+	assert.Equal(t, 101, a.Update(Sample{Limit: 100, InFlight: 100, RTT: time.Hour})) // No latency check, no max
+}
+
+func TestGradient(t *testing.T) {
+	t.Run("grow", func(t *testing.T) {
+		g := &Gradient{Max: 50}
+		limit := 10
+		for i := 0; i < 100; i++ {
+			limit = g.Update(Sample{Limit: limit, InFlight: limit, RTT: 10 * time.Millisecond})
+		}
+		assert.Equal(t, 50, limit)
+	})
+
+	t.Run("app_limited", func(t *testing.T) {
+		g := &Gradient{}
+		limit := 10
+		for i := 0; i < 100; i++ {
+			limit = g.Update(Sample{Limit: limit, InFlight: 1, RTT: 10 * time.Millisecond})
+		}
+		assert.Equal(t, 10, limit)
+	})
+
+	t.Run("shrink", func(t *testing.T) {
+		g := &Gradient{Min: 5}
+		limit := 100
+		for i := 0; i < 10; i++ {
+			limit = g.Update(Sample{Limit: limit, InFlight: limit, RTT: 10 * time.Millisecond})
+		}
+		grown := limit
+		for i := 0; i < 20; i++ {
+			limit = g.Update(Sample{Limit: limit, InFlight: limit, RTT: time.Second})
+		}
+		assert.Less(t, limit, grown)
+		for i := 0; i < 100; i++ {
+			limit = g.Update(Sample{Limit: limit, InFlight: limit, Dropped: true})
+		}
+		assert.Equal(t, 5, limit)
+	})
+}
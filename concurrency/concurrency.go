@@ -0,0 +1,77 @@
+package concurrency
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"goyave.dev/goyave/v5"
+)
+
+// MetaPriority the meta key used to set the `Priority` of the requests to a router or a route.
+// Requests to routes without this meta have the `PriorityNormal` priority.
+const MetaPriority = "goyave.concurrency.priority"
+
+// DefaultRetryAfter the default value of the "Retry-After" header sent when a request is rejected.
+const DefaultRetryAfter = time.Second
+
+// Middleware limits the number of requests processed concurrently using its `Limiter`.
+// Register it globally to cap the number of in-flight requests of the whole application,
+// or on a router to cap the requests of a route group. Each middleware instance counts
+// the requests separately, so both can be combined.
+//
+// When the limit is reached, requests wait in the limiter's queue. The priority of the
+// request is defined by the `MetaPriority` meta. If the request cannot be admitted (full
+// queue, queue timeout or canceled request), the "Retry-After" header is set and the
+// middleware responds with "503 Service Unavailable", executing the status handler.
+//
+// Requests that are canceled or answered with "503 Service Unavailable" or "504 Gateway Timeout"
+// (for example by the `goyave.TimeoutMiddleware`) are reported as dropped to the limiter's
+// adaptive algorithm.
+type Middleware struct {
+	goyave.Component
+
+	// Limiter the limiter counting the in-flight requests.
+	Limiter *Limiter
+
+	// RetryAfter the value of the "Retry-After" header sent when a request is rejected.
+	// Defaults to `DefaultRetryAfter`.
+	RetryAfter time.Duration
+}
+
+// New create a new concurrency limiting middleware using the given limiter.
+func New(limiter *Limiter) *Middleware {
+	return &Middleware{Limiter: limiter}
+}
+
+// Handle limits the number of in-flight requests.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		priority := PriorityNormal
+		if p, ok := request.Route.LookupMeta(MetaPriority); ok {
+			priority, _ = p.(Priority)
+		}
+
+		release, err := m.Limiter.Acquire(request.Context(), priority)
+		if err != nil {
+			response.Header().Set("Retry-After", strconv.Itoa(m.retryAfter()))
+			response.Status(http.StatusServiceUnavailable)
+			return
+		}
+		defer func() {
+			status := response.GetStatus()
+			release(request.Context().Err() != nil || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout)
+		}()
+
+		next(response, request)
+	}
+}
+
+func (m *Middleware) retryAfter() int {
+	retryAfter := m.RetryAfter
+	if retryAfter <= 0 {
+		retryAfter = DefaultRetryAfter
+	}
+	return max(1, int(math.Ceil(retryAfter.Seconds())))
+}
@@ -0,0 +1,72 @@
+package concurrency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func TestMiddleware(t *testing.T) {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+	limiter := NewLimiter(1, 1)
+	limiter.Algorithm = &AIMD{Min: 1, Max: 1}
+	middleware := New(limiter)
+	middleware.RetryAfter = 1500 * time.Millisecond
+	server.Router().GlobalMiddleware(middleware)
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	var priority Priority
+	server.Router().Get("/block", func(response *goyave.Response, _ *goyave.Request) {
+		close(started)
+		<-unblock
+		response.Status(http.StatusOK)
+	})
+	server.Router().Get("/high", func(response *goyave.Response, _ *goyave.Request) {
+		priority = PriorityHigh
+		response.Status(http.StatusOK)
+	}).SetMeta(MetaPriority, PriorityHigh)
+	server.Router().Get("/normal", func(response *goyave.Response, _ *goyave.Request) {
+		response.Status(http.StatusOK)
+	})
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		resp := server.TestRequest(httptest.NewRequest(http.MethodGet, "/block", nil))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}()
+	<-started
+
+	go func() {
+		defer wg.Done()
+		resp := server.TestRequest(httptest.NewRequest(http.MethodGet, "/high", nil))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}()
+	require.Eventually(t, func() bool { return limiter.Queued() == 1 }, time.Second, time.Millisecond)
+
+	// Queue full
+	resp := server.TestRequest(httptest.NewRequest(http.MethodGet, "/normal", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+	require.NoError(t, resp.Body.Close())
+
+	close(unblock)
+	wg.Wait()
+	assert.Equal(t, PriorityHigh, priority)
+	assert.Equal(t, 0, limiter.InFlight())
+	assert.Equal(t, 0, limiter.Queued())
+
+	resp = server.TestRequest(httptest.NewRequest(http.MethodGet, "/normal", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+}
@@ -0,0 +1,227 @@
+package concurrency
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQueueFull returned by `Limiter.Acquire` if the limit is reached and the wait queue is full,
+	// or if the request was evicted from the queue by a request with a higher priority.
+	ErrQueueFull = errors.New("concurrency limit reached and wait queue full")
+
+	// ErrQueueTimeout returned by `Limiter.Acquire` if the request waited in the queue for too long.
+	ErrQueueTimeout = errors.New("concurrency limit wait queue timeout")
+)
+
+// Priority the priority class of a request. When the limit is reached, queued requests with
+// the highest priority are admitted first, and requests with a lower priority are evicted
+// from a full queue to make room for requests with a higher priority.
+type Priority int
+
+// Priority classes.
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+
+	// PriorityCritical requests are always admitted immediately,
+	// even if the limit is reached. They are still counted as in-flight.
+	PriorityCritical
+)
+
+// Sample the observation of a completed request, used by adaptive algorithms.
+type Sample struct {
+	// RTT the time spent processing the request, excluding the time spent in the queue.
+	RTT time.Duration
+
+	// Limit the current limit.
+	Limit int
+
+	// InFlight the number of in-flight requests when the request completed, including itself.
+	InFlight int
+
+	// Dropped true if the request was canceled or timed out, which indicates an overload.
+	Dropped bool
+}
+
+// Algorithm adapts the concurrency limit from the observed requests.
+// `Update` is called with the limiter lock held, so implementations
+// don't need to be safe for concurrent use.
+type Algorithm interface {
+	// Update returns the new limit after the given sample was observed.
+	Update(sample Sample) int
+}
+
+type waiter struct {
+	ready    chan bool
+	elem     *list.Element
+	priority Priority
+}
+
+// Limiter caps the number of requests processed concurrently. Requests exceeding the limit
+// wait in a bounded queue ordered by priority. If `Algorithm` is not nil, the limit is
+// adapted from the observed latency after each request.
+type Limiter struct {
+	queues [PriorityCritical]*list.List
+
+	// Algorithm if not nil, adapts the limit after each completed request.
+	Algorithm Algorithm
+
+	// MaxQueue the maximum number of requests waiting for a slot. 0 disables the queue.
+	MaxQueue int
+
+	// QueueTimeout the maximum duration a request can wait in the queue. 0 means no timeout
+	// (the request waits until a slot is available or until its context is canceled).
+	QueueTimeout time.Duration
+
+	limit    int
+	inFlight int
+	queued   int
+	mu       sync.Mutex
+}
+
+// NewLimiter create a new `Limiter` with the given initial limit and maximum queue size.
+// The limit must be at least 1.
+func NewLimiter(limit, maxQueue int) *Limiter {
+	l := &Limiter{
+		limit:    max(limit, 1),
+		MaxQueue: maxQueue,
+	}
+	for i := range l.queues {
+		l.queues[i] = list.New()
+	}
+	return l
+}
+
+// Limit returns the current limit.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// InFlight returns the number of requests currently being processed.
+func (l *Limiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight
+}
+
+// Queued returns the number of requests currently waiting in the queue.
+func (l *Limiter) Queued() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.queued
+}
+
+// Acquire a slot for a request with the given priority, waiting in the queue if the
+// limit is reached. On success, returns a function that must be called when the request
+// completes to release the slot. The `dropped` parameter indicates if the request was
+// canceled or timed out.
+//
+// Returns `ErrQueueFull`, `ErrQueueTimeout` or the context's error if no slot could be acquired.
+func (l *Limiter) Acquire(ctx context.Context, priority Priority) (func(dropped bool), error) {
+	l.mu.Lock()
+	if priority >= PriorityCritical || l.inFlight < l.limit {
+		l.inFlight++
+		l.mu.Unlock()
+		return l.releaseFunc(time.Now()), nil
+	}
+	priority = max(priority, PriorityLow)
+
+	if l.queued >= l.MaxQueue && !l.evict(priority) {
+		l.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+	w := &waiter{ready: make(chan bool, 1), priority: priority}
+	w.elem = l.queues[priority].PushBack(w)
+	l.queued++
+	l.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if l.QueueTimeout > 0 {
+		timer := time.NewTimer(l.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case admitted := <-w.ready:
+		if !admitted {
+			return nil, ErrQueueFull
+		}
+		return l.releaseFunc(time.Now()), nil
+	case <-timeout:
+		err = ErrQueueTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	l.mu.Lock()
+	if w.elem != nil {
+		l.queues[w.priority].Remove(w.elem)
+		w.elem = nil
+		l.queued--
+		l.mu.Unlock()
+		return nil, err
+	}
+	l.mu.Unlock()
+	// The waiter was admitted or evicted concurrently.
+	if admitted := <-w.ready; admitted {
+		return l.releaseFunc(time.Now()), nil
+	}
+	return nil, ErrQueueFull
+}
+
+// evict the most recently queued request with the lowest priority, if
+// its priority is lower than the given one. Returns true if a request was evicted.
+func (l *Limiter) evict(priority Priority) bool {
+	for p := PriorityLow; p < priority; p++ {
+		if elem := l.queues[p].Back(); elem != nil {
+			w := l.queues[p].Remove(elem).(*waiter)
+			w.elem = nil
+			l.queued--
+			w.ready <- false
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Limiter) releaseFunc(start time.Time) func(dropped bool) {
+	var once sync.Once
+	return func(dropped bool) {
+		once.Do(func() {
+			l.release(Sample{RTT: time.Since(start), Dropped: dropped})
+		})
+	}
+}
+
+func (l *Limiter) release(sample Sample) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.Algorithm != nil {
+		sample.Limit = l.limit
+		sample.InFlight = l.inFlight
+		l.limit = max(l.Algorithm.Update(sample), 1)
+	}
+	l.inFlight--
+
+	for l.inFlight < l.limit && l.queued > 0 {
+		for p := PriorityHigh; p >= PriorityLow; p-- {
+			if elem := l.queues[p].Front(); elem != nil {
+				w := l.queues[p].Remove(elem).(*waiter)
+				w.elem = nil
+				l.queued--
+				l.inFlight++
+				w.ready <- true
+				break
+			}
+		}
+	}
+}
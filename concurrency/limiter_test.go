@@ -0,0 +1,149 @@
+package concurrency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type acquireResult struct {
+	release func(bool)
+	err     error
+}
+
+func acquireAsync(l *Limiter, ctx context.Context, priority Priority) <-chan acquireResult {
+	c := make(chan acquireResult, 1)
+	go func() {
+		release, err := l.Acquire(ctx, priority)
+		c <- acquireResult{release: release, err: err}
+	}()
+	return c
+}
+
+func waitQueued(t *testing.T, l *Limiter, n int) {
+	require.Eventually(t, func() bool { return l.Queued() == n }, time.Second, time.Millisecond)
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("limit", func(t *testing.T) {
+		l := NewLimiter(2, 0)
+		assert.Equal(t, 2, l.Limit())
+		r1, err := l.Acquire(ctx, PriorityNormal)
+		require.NoError(t, err)
+		r2, err := l.Acquire(ctx, PriorityNormal)
+		require.NoError(t, err)
+		assert.Equal(t, 2, l.InFlight())
+
+		_, err = l.Acquire(ctx, PriorityHigh)
+		require.ErrorIs(t, err, ErrQueueFull)
+
+		// Critical requests are always admitted
+		r3, err := l.Acquire(ctx, PriorityCritical)
+		require.NoError(t, err)
+		assert.Equal(t, 3, l.InFlight())
+
+		r1(false)
+		r1(false) // Releasing twice has no effect
+		r2(false)
+		r3(false)
+		assert.Equal(t, 0, l.InFlight())
+
+		assert.Equal(t, 1, NewLimiter(0, 0).Limit())
+	})
+
+	t.Run("queue_priority", func(t *testing.T) {
+		l := NewLimiter(1, 3)
+		release, err := l.Acquire(ctx, PriorityNormal)
+		require.NoError(t, err)
+
+		low := acquireAsync(l, ctx, PriorityLow)
+		waitQueued(t, l, 1)
+		normal := acquireAsync(l, ctx, PriorityNormal)
+		waitQueued(t, l, 2)
+		high := acquireAsync(l, ctx, PriorityHigh)
+		waitQueued(t, l, 3)
+
+		release(false)
+		r := <-high
+		require.NoError(t, r.err)
+		assert.Equal(t, 2, l.Queued())
+		r.release(false)
+		r = <-normal
+		require.NoError(t, r.err)
+		r.release(false)
+		r = <-low
+		require.NoError(t, r.err)
+		r.release(false)
+		assert.Equal(t, 0, l.InFlight())
+		assert.Equal(t, 0, l.Queued())
+	})
+
+	t.Run("eviction", func(t *testing.T) {
+		l := NewLimiter(1, 1)
+		release, err := l.Acquire(ctx, PriorityNormal)
+		require.NoError(t, err)
+
+		low := acquireAsync(l, ctx, PriorityLow)
+		waitQueued(t, l, 1)
+
+		// Same priority: rejected
+		_, err = l.Acquire(ctx, PriorityLow)
+		require.ErrorIs(t, err, ErrQueueFull)
+
+		// Higher priority: evicts the low priority request
+		high := acquireAsync(l, ctx, PriorityHigh)
+		r := <-low
+		require.ErrorIs(t, r.err, ErrQueueFull)
+		waitQueued(t, l, 1)
+
+		release(false)
+		r = <-high
+		require.NoError(t, r.err)
+		r.release(false)
+	})
+
+	t.Run("timeout_and_cancel", func(t *testing.T) {
+		l := NewLimiter(1, 2)
+		l.QueueTimeout = 10 * time.Millisecond
+		release, err := l.Acquire(ctx, PriorityNormal)
+		require.NoError(t, err)
+
+		_, err = l.Acquire(ctx, PriorityNormal)
+		require.ErrorIs(t, err, ErrQueueTimeout)
+		assert.Equal(t, 0, l.Queued())
+
+		l.QueueTimeout = 0
+		cancelCtx, cancel := context.WithCancel(ctx)
+		c := acquireAsync(l, cancelCtx, PriorityNormal)
+		waitQueued(t, l, 1)
+		cancel()
+		r := <-c
+		require.ErrorIs(t, r.err, context.Canceled)
+		assert.Equal(t, 0, l.Queued())
+
+		release(false)
+		assert.Equal(t, 0, l.InFlight())
+	})
+
+	t.Run("adaptive", func(t *testing.T) {
+		l := NewLimiter(4, 0)
+		l.Algorithm = &AIMD{Min: 2}
+		release, err := l.Acquire(ctx, PriorityNormal)
+		require.NoError(t, err)
+		release(true)
+		assert.Equal(t, 3, l.Limit())
+		release, err = l.Acquire(ctx, PriorityNormal)
+		require.NoError(t, err)
+		release(true)
+		assert.Equal(t, 2, l.Limit())
+		release, err = l.Acquire(ctx, PriorityNormal)
+		require.NoError(t, err)
+		release(false)
+		assert.Equal(t, 3, l.Limit())
+	})
+}
@@ -121,7 +121,15 @@ func (o *Options) HandlePreflight(headers http.Header, requestHeaders http.Heade
 }
 
 func (o *Options) validateOrigin(requestHeaders http.Header) bool {
-	return len(o.AllowedOrigins) == 0 ||
-		o.AllowedOrigins[0] == "*" ||
-		slices.Contains(o.AllowedOrigins, requestHeaders.Get("Origin"))
+	return o.IsOriginAllowed(requestHeaders.Get("Origin"))
+}
+
+// IsOriginAllowed returns true if cross-domain requests can be executed from the given origin.
+func (o *Options) IsOriginAllowed(origin string) bool {
+	return o.AllowsAllOrigins() || slices.Contains(o.AllowedOrigins, origin)
+}
+
+// AllowsAllOrigins returns true if cross-domain requests can be executed from any origin.
+func (o *Options) AllowsAllOrigins() bool {
+	return len(o.AllowedOrigins) == 0 || o.AllowedOrigins[0] == "*"
 }
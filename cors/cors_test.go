@@ -96,6 +96,21 @@ func TestConfigureOrigin(t *testing.T) {
 	assert.Equal(t, "Origin", headers.Get("Vary"))
 }
 
+func TestIsOriginAllowed(t *testing.T) {
+	options := Default()
+	assert.True(t, options.AllowsAllOrigins())
+	assert.True(t, options.IsOriginAllowed("https://google.com"))
+
+	options.AllowedOrigins = []string{}
+	assert.True(t, options.AllowsAllOrigins())
+	assert.True(t, options.IsOriginAllowed("https://google.com"))
+
+	options.AllowedOrigins = []string{"https://google.com", "https://images.google.com"}
+	assert.False(t, options.AllowsAllOrigins())
+	assert.True(t, options.IsOriginAllowed("https://google.com"))
+	assert.False(t, options.IsOriginAllowed("https://systemglitch.me"))
+}
+
 func TestConfigureCommon(t *testing.T) {
 	options := Default()
 	options.AllowCredentials = true
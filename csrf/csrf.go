@@ -0,0 +1,250 @@
+package csrf
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/cors"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// MetaExempt routes having this meta set to `true` are not
+// protected by the CSRF middleware.
+const MetaExempt = "csrf.exempt"
+
+// DefaultHeaderName the default name of the header
+// from which the submitted token is read.
+const DefaultHeaderName = "X-CSRF-Token"
+
+// tokenLength the length of the generated tokens, in bytes, before encoding.
+const tokenLength = 32
+
+func init() {
+	config.Register("csrf.secret", config.Entry{
+		Value:            nil,
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("csrf.cookie.name", config.Entry{
+		Value:            "XSRF-TOKEN",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("csrf.cookie.path", config.Entry{
+		Value:            "/",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("csrf.cookie.domain", config.Entry{
+		Value:            "",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("csrf.cookie.secure", config.Entry{
+		Value:            false,
+		Type:             reflect.Bool,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("csrf.cookie.sameSite", config.Entry{
+		Value:            "Lax",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{"Lax", "Strict", "None"},
+	})
+}
+
+var safeMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace}
+
+// Middleware protects routes against Cross-Site Request Forgery.
+//
+// A token is associated with each client using the `Strategy` (double-submit cookie by default).
+// For requests using an unsafe method (anything other than GET, HEAD, OPTIONS and TRACE),
+// the token submitted in the header identified by `HeaderName`, or in the form field
+// identified by `FieldName`, must match the expected token. Otherwise the request is
+// rejected with "403 Forbidden" and the status handler is executed.
+//
+// Because the form field is read from `request.Data`, this middleware must be executed
+// after the parse middleware. It is therefore recommended to add it as a regular
+// (non-global) middleware.
+//
+// Cross-origin unsafe requests are also rejected unless the route's CORS options
+// allow credentials and explicitly list the request's origin.
+// Use `ConfigureCORS()` to allow the token header in cross-origin requests.
+//
+// Routes with the `MetaExempt` meta set to `true` are skipped.
+//
+// The token is stored in the request's `Extra` (key `goyave.ExtraCSRFToken{}`) so it can be
+// retrieved with `csrf.Token()` and is available in templates with the "csrf_token"
+// and "csrf_field" functions. The field name is stored as well (key `goyave.ExtraCSRFFieldName{}`)
+// so "csrf_field" generates a field named after `FieldName`. For single-page applications, the token can be obtained
+// from the double-submit cookie or from a route using `csrf.TokenHandler`.
+type Middleware struct {
+	goyave.Component
+
+	// Strategy defines how the expected token is stored.
+	// Defaults to `DoubleSubmit`.
+	Strategy Strategy
+
+	// HeaderName the name of the header containing the submitted token.
+	// Defaults to `DefaultHeaderName`.
+	HeaderName string
+
+	// FieldName the name of the form field containing the submitted token.
+	// Defaults to `goyave.DefaultCSRFFieldName`.
+	FieldName string
+}
+
+// New create a new CSRF middleware using the given strategy.
+// If the strategy is `nil`, `DoubleSubmit` is used.
+func New(strategy Strategy) *Middleware {
+	if strategy == nil {
+		strategy = &DoubleSubmit{}
+	}
+	return &Middleware{
+		Strategy:   strategy,
+		HeaderName: DefaultHeaderName,
+		FieldName:  goyave.DefaultCSRFFieldName,
+	}
+}
+
+// Init the middleware and its strategy if it is a `goyave.Composable`.
+func (m *Middleware) Init(server *goyave.Server) {
+	m.Component.Init(server)
+	if m.Strategy == nil {
+		m.Strategy = &DoubleSubmit{}
+	}
+	if c, ok := m.Strategy.(goyave.Composable); ok {
+		c.Init(server)
+	}
+}
+
+// Handle checks the submitted CSRF token and exposes the expected token.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		if exempt, ok := request.Route.LookupMeta(MetaExempt); ok && exempt == true {
+			next(response, request)
+			return
+		}
+
+		token, err := m.Strategy.Load(request)
+		if err != nil {
+			panic(errors.New(err))
+		}
+
+		if !slices.Contains(safeMethods, request.Method()) {
+			if !m.checkOrigin(request) || !verify(token, m.submittedToken(request)) {
+				response.Status(http.StatusForbidden)
+				return
+			}
+		}
+
+		if token == "" {
+			token, err = m.Strategy.Issue(response, request)
+			if err != nil {
+				panic(errors.New(err))
+			}
+		}
+
+		request.Extra[goyave.ExtraCSRFToken{}] = token
+		request.Extra[goyave.ExtraCSRFFieldName{}] = m.fieldName()
+		next(response, request)
+	}
+}
+
+// ConfigureCORS adds the token header to the allowed headers of the given
+// CORS options so cross-origin requests can submit the token. Returns the given options.
+func (m *Middleware) ConfigureCORS(options *cors.Options) *cors.Options {
+	header := m.headerName()
+	if len(options.AllowedHeaders) > 0 && options.AllowedHeaders[0] != "*" && !slices.Contains(options.AllowedHeaders, header) {
+		options.AllowedHeaders = append(options.AllowedHeaders, header)
+	}
+	return options
+}
+
+func (m *Middleware) headerName() string {
+	if m.HeaderName == "" {
+		return DefaultHeaderName
+	}
+	return m.HeaderName
+}
+
+func (m *Middleware) fieldName() string {
+	if m.FieldName == "" {
+		return goyave.DefaultCSRFFieldName
+	}
+	return m.FieldName
+}
+
+func (m *Middleware) submittedToken(request *goyave.Request) string {
+	if token := request.Header().Get(m.headerName()); token != "" {
+		return token
+	}
+	if data, ok := request.Data.(map[string]any); ok {
+		if token, ok := data[m.fieldName()].(string); ok {
+			return token
+		}
+	}
+	return ""
+}
+
+// checkOrigin returns false if the request is a cross-origin request that
+// is not explicitly allowed to include credentials by the route's CORS options.
+func (m *Middleware) checkOrigin(request *goyave.Request) bool {
+	origin := request.Header().Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && u.Host != "" && strings.EqualFold(u.Host, request.Host()) {
+		return true
+	}
+
+	o, ok := request.Route.LookupMeta(goyave.MetaCORS)
+	if !ok {
+		return false
+	}
+	options, ok := o.(*cors.Options)
+	if !ok || options == nil || !options.AllowCredentials || options.AllowsAllOrigins() {
+		return false
+	}
+	return options.IsOriginAllowed(origin)
+}
+
+func verify(expected, submitted string) bool {
+	return expected != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(submitted)) == 1
+}
+
+// GenerateToken returns a new random token.
+func GenerateToken() (string, error) {
+	b := make([]byte, tokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.New(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Token returns the CSRF token of the given request set by the CSRF middleware.
+// Returns an empty string if there is none.
+func Token(request *goyave.Request) string {
+	token, _ := request.Extra[goyave.ExtraCSRFToken{}].(string)
+	return token
+}
+
+// TokenHandler responds with a JSON object containing the CSRF token of the request
+// (e.g.: `{"token":"..."}`). This is useful for single-page applications that cannot read
+// the token from a cookie or from the rendered page.
+func TokenHandler(response *goyave.Response, request *goyave.Request) {
+	response.JSON(http.StatusOK, map[string]string{"token": Token(request)})
+}
@@ -0,0 +1,226 @@
+package csrf
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/cors"
+	"goyave.dev/goyave/v5/middleware/parse"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func prepareCSRFTest(t *testing.T, cfg *config.Config, strategy Strategy) (*testutil.TestServer, *goyave.Router) {
+	if cfg == nil {
+		cfg = config.LoadDefault()
+	}
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+	router := server.Router()
+	router.GlobalMiddleware(&parse.Middleware{})
+	router.Middleware(New(strategy))
+	handler := func(response *goyave.Response, request *goyave.Request) {
+		response.String(http.StatusOK, Token(request))
+	}
+	router.Get("/form", handler)
+	router.Get("/token", TokenHandler)
+	router.Post("/submit", handler)
+	router.Post("/exempt", handler).SetMeta(MetaExempt, true)
+	return server, router
+}
+
+func csrfCookie(resp *http.Response) *http.Cookie {
+	for _, c := range resp.Cookies() {
+		if c.Name == "XSRF-TOKEN" {
+			return c
+		}
+	}
+	return nil
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return string(body)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("issue_token", func(t *testing.T) {
+		server, _ := prepareCSRFTest(t, nil, nil)
+		resp := server.TestRequest(httptest.NewRequest(http.MethodGet, "/form", nil))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		cookie := csrfCookie(resp)
+		require.NotNil(t, cookie)
+		assert.False(t, cookie.HttpOnly)
+		assert.Equal(t, cookie.Value, readBody(t, resp))
+
+		// Existing token reused
+		req := httptest.NewRequest(http.MethodGet, "/form", nil)
+		req.AddCookie(cookie)
+		resp = server.TestRequest(req)
+		assert.Nil(t, csrfCookie(resp))
+		assert.Equal(t, cookie.Value, readBody(t, resp))
+	})
+
+	t.Run("token_handler", func(t *testing.T) {
+		server, _ := prepareCSRFTest(t, nil, nil)
+		resp := server.TestRequest(httptest.NewRequest(http.MethodGet, "/token", nil))
+		cookie := csrfCookie(resp)
+		require.NotNil(t, cookie)
+		assert.Equal(t, `{"token":"`+cookie.Value+`"}`+"\n", readBody(t, resp))
+	})
+
+	t.Run("header", func(t *testing.T) {
+		server, _ := prepareCSRFTest(t, nil, nil)
+		cookie := csrfCookie(server.TestRequest(httptest.NewRequest(http.MethodGet, "/form", nil)))
+		require.NotNil(t, cookie)
+
+		req := httptest.NewRequest(http.MethodPost, "/submit", nil)
+		req.AddCookie(cookie)
+		req.Header.Set(DefaultHeaderName, cookie.Value)
+		resp := server.TestRequest(req)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, cookie.Value, readBody(t, resp))
+	})
+
+	t.Run("form_field", func(t *testing.T) {
+		server, _ := prepareCSRFTest(t, nil, nil)
+		cookie := csrfCookie(server.TestRequest(httptest.NewRequest(http.MethodGet, "/form", nil)))
+		require.NotNil(t, cookie)
+
+		form := url.Values{goyave.DefaultCSRFFieldName: {cookie.Value}}
+		req := httptest.NewRequest(http.MethodPost, "/submit", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(cookie)
+		resp := server.TestRequest(req)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		_ = resp.Body.Close()
+	})
+
+	t.Run("custom_field_name", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		router := server.Router()
+		router.GlobalMiddleware(&parse.Middleware{})
+		m := New(nil)
+		m.FieldName = "custom_token"
+		router.Middleware(m)
+		router.Get("/form", func(response *goyave.Response, request *goyave.Request) {
+			response.String(http.StatusOK, request.Extra[goyave.ExtraCSRFFieldName{}].(string))
+		})
+		router.Post("/submit", func(response *goyave.Response, _ *goyave.Request) {
+			response.Status(http.StatusOK)
+		})
+
+		resp := server.TestRequest(httptest.NewRequest(http.MethodGet, "/form", nil))
+		cookie := csrfCookie(resp)
+		require.NotNil(t, cookie)
+		fieldName := readBody(t, resp)
+		assert.Equal(t, "custom_token", fieldName)
+
+		form := url.Values{fieldName: {cookie.Value}}
+		req := httptest.NewRequest(http.MethodPost, "/submit", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(cookie)
+		resp = server.TestRequest(req)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		_ = resp.Body.Close()
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		server, _ := prepareCSRFTest(t, nil, nil)
+		cookie := csrfCookie(server.TestRequest(httptest.NewRequest(http.MethodGet, "/form", nil)))
+		require.NotNil(t, cookie)
+
+		cases := []struct {
+			cookie *http.Cookie
+			token  string
+		}{
+			{cookie: nil, token: ""},
+			{cookie: nil, token: cookie.Value},
+			{cookie: cookie, token: ""},
+			{cookie: cookie, token: "invalid"},
+		}
+		for _, c := range cases {
+			req := httptest.NewRequest(http.MethodPost, "/submit", nil)
+			if c.cookie != nil {
+				req.AddCookie(c.cookie)
+			}
+			if c.token != "" {
+				req.Header.Set(DefaultHeaderName, c.token)
+			}
+			resp := server.TestRequest(req)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+			assert.Equal(t, "{\"error\":\""+http.StatusText(http.StatusForbidden)+"\"}\n", readBody(t, resp))
+		}
+	})
+
+	t.Run("exempt", func(t *testing.T) {
+		server, _ := prepareCSRFTest(t, nil, nil)
+		resp := server.TestRequest(httptest.NewRequest(http.MethodPost, "/exempt", nil))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, readBody(t, resp))
+	})
+
+	t.Run("cors", func(t *testing.T) {
+		server, router := prepareCSRFTest(t, nil, nil)
+		cookie := csrfCookie(server.TestRequest(httptest.NewRequest(http.MethodGet, "/form", nil)))
+		require.NotNil(t, cookie)
+
+		request := func(origin string) int {
+			req := httptest.NewRequest(http.MethodPost, "/submit", nil)
+			req.AddCookie(cookie)
+			req.Header.Set(DefaultHeaderName, cookie.Value)
+			req.Header.Set("Origin", origin)
+			resp := server.TestRequest(req)
+			_ = resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		// Same origin
+		assert.Equal(t, http.StatusOK, request("http://example.com"))
+		// No CORS
+		assert.Equal(t, http.StatusForbidden, request("https://other.org"))
+
+		options := cors.Default()
+		router.CORS(options)
+		assert.Equal(t, http.StatusForbidden, request("https://other.org")) // No credentials
+
+		options.AllowCredentials = true
+		assert.Equal(t, http.StatusForbidden, request("https://other.org")) // Wildcard origin
+
+		options.AllowedOrigins = []string{"https://other.org"}
+		assert.Equal(t, http.StatusOK, request("https://other.org"))
+		assert.Equal(t, http.StatusForbidden, request("https://evil.org"))
+		assert.Equal(t, http.StatusForbidden, request("null"))
+	})
+
+	t.Run("ConfigureCORS", func(t *testing.T) {
+		m := New(nil)
+		options := m.ConfigureCORS(cors.Default())
+		assert.Equal(t, []string{"Origin", "Accept", "Content-Type", "X-Requested-With", "Authorization", DefaultHeaderName}, options.AllowedHeaders)
+		m.ConfigureCORS(options)
+		assert.Len(t, options.AllowedHeaders, 6)
+
+		options.AllowedHeaders = []string{"*"}
+		m.ConfigureCORS(options)
+		assert.Equal(t, []string{"*"}, options.AllowedHeaders)
+
+		options.AllowedHeaders = []string{}
+		m.ConfigureCORS(options)
+		assert.Empty(t, options.AllowedHeaders)
+	})
+
+	t.Run("Token", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodGet, "/", nil)
+		assert.Empty(t, Token(request))
+		request.Extra[goyave.ExtraCSRFToken{}] = "token"
+		assert.Equal(t, "token", Token(request))
+	})
+}
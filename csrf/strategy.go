@@ -0,0 +1,139 @@
+package csrf
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/session"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// SessionKey the session key used by the `Synchronizer` strategy to store the token.
+const SessionKey = "_csrf_token"
+
+var sameSiteModes = map[string]http.SameSite{
+	"Lax":    http.SameSiteLaxMode,
+	"Strict": http.SameSiteStrictMode,
+	"None":   http.SameSiteNoneMode,
+}
+
+// Strategy defines where the expected CSRF token of a client is stored.
+type Strategy interface {
+	// Load returns the token associated with the client sending the given request.
+	// Returns an empty string if there is none.
+	Load(request *goyave.Request) (string, error)
+
+	// Issue generates a new token, associates it with the client sending the given
+	// request and returns it.
+	Issue(response *goyave.Response, request *goyave.Request) (string, error)
+}
+
+// DoubleSubmit stores the token in a cookie readable by client-side scripts.
+// The client must send the cookie value back in a header or form field.
+// The cookie attributes are defined by the "csrf.cookie.*" config entries.
+//
+// If a secret is defined (`Secret` or the "csrf.secret" config entry), tokens are signed
+// using HMAC-SHA256 and cookies with an invalid signature are ignored. This prevents an
+// attacker able to write cookies (e.g. from a sibling sub-domain) from forging tokens.
+type DoubleSubmit struct {
+	goyave.Component
+
+	// Secret the key used to sign the tokens.
+	// If not set, uses the "csrf.secret" config entry.
+	Secret []byte
+}
+
+func (s *DoubleSubmit) secret() []byte {
+	if len(s.Secret) > 0 {
+		return s.Secret
+	}
+	if s.Config().Has("csrf.secret") {
+		return []byte(s.Config().GetString("csrf.secret"))
+	}
+	return nil
+}
+
+func (s *DoubleSubmit) sign(token string) string {
+	mac := hmac.New(sha256.New, s.secret())
+	mac.Write([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Load returns the value of the CSRF cookie.
+func (s *DoubleSubmit) Load(request *goyave.Request) (string, error) {
+	cookie, err := request.Request().Cookie(s.Config().GetString("csrf.cookie.name"))
+	if err != nil {
+		return "", nil
+	}
+	if len(s.secret()) == 0 {
+		return cookie.Value, nil
+	}
+	token, signature, ok := strings.Cut(cookie.Value, ".")
+	if !ok || token == "" || !hmac.Equal([]byte(signature), []byte(s.sign(token))) {
+		return "", nil
+	}
+	return cookie.Value, nil
+}
+
+// Issue generates a new token and sets the CSRF cookie.
+func (s *DoubleSubmit) Issue(response *goyave.Response, _ *goyave.Request) (string, error) {
+	token, err := GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	if len(s.secret()) > 0 {
+		token += "." + s.sign(token)
+	}
+	cfg := s.Config()
+	response.Cookie(&http.Cookie{
+		Name:     cfg.GetString("csrf.cookie.name"),
+		Value:    token,
+		Path:     cfg.GetString("csrf.cookie.path"),
+		Domain:   cfg.GetString("csrf.cookie.domain"),
+		Secure:   cfg.GetBool("csrf.cookie.secure"),
+		HttpOnly: false, // Must be readable by client-side scripts
+		SameSite: sameSiteModes[cfg.GetString("csrf.cookie.sameSite")],
+	})
+	return token, nil
+}
+
+// Synchronizer stores the token in the server-side session (see the `session` package).
+// The session middleware must be executed before the CSRF middleware.
+type Synchronizer struct{}
+
+func (Synchronizer) session(request *goyave.Request) (*session.Session, error) {
+	s := session.FromRequest(request)
+	if s == nil {
+		return nil, errors.New("csrf: the synchronizer strategy requires the session middleware")
+	}
+	return s, nil
+}
+
+// Load returns the token stored in the session.
+func (st Synchronizer) Load(request *goyave.Request) (string, error) {
+	s, err := st.session(request)
+	if err != nil {
+		return "", err
+	}
+	v, _ := s.Get(SessionKey)
+	token, _ := v.(string)
+	return token, nil
+}
+
+// Issue generates a new token and stores it in the session.
+func (st Synchronizer) Issue(_ *goyave.Response, request *goyave.Request) (string, error) {
+	s, err := st.session(request)
+	if err != nil {
+		return "", err
+	}
+	token, err := GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	s.Set(SessionKey, token)
+	return token, nil
+}
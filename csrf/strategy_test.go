@@ -0,0 +1,103 @@
+package csrf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/session"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func TestDoubleSubmit(t *testing.T) {
+	t.Run("signed", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("csrf.secret", "secret")
+		server, _ := prepareCSRFTest(t, cfg, nil)
+		cookie := csrfCookie(server.TestRequest(httptest.NewRequest(http.MethodGet, "/form", nil)))
+		require.NotNil(t, cookie)
+		token, signature, ok := strings.Cut(cookie.Value, ".")
+		require.True(t, ok)
+		assert.NotEmpty(t, token)
+		assert.NotEmpty(t, signature)
+
+		req := httptest.NewRequest(http.MethodPost, "/submit", nil)
+		req.AddCookie(cookie)
+		req.Header.Set(DefaultHeaderName, cookie.Value)
+		resp := server.TestRequest(req)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, cookie.Value, readBody(t, resp))
+
+		// Forged cookie
+		forged := &http.Cookie{Name: cookie.Name, Value: "forged.signature"}
+		req = httptest.NewRequest(http.MethodPost, "/submit", nil)
+		req.AddCookie(forged)
+		req.Header.Set(DefaultHeaderName, forged.Value)
+		resp = server.TestRequest(req)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		_ = resp.Body.Close()
+	})
+
+	t.Run("secret_field", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		s := &DoubleSubmit{}
+		s.Init(server.Server)
+		assert.Nil(t, s.secret())
+		s.Secret = []byte("secret")
+		assert.Equal(t, []byte("secret"), s.secret())
+	})
+}
+
+func TestSynchronizer(t *testing.T) {
+	t.Run("no_session", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodGet, "/", nil)
+		_, err := Synchronizer{}.Load(request)
+		require.Error(t, err)
+		_, err = Synchronizer{}.Issue(nil, request)
+		require.Error(t, err)
+	})
+
+	t.Run("middleware", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("session.secret", "secret")
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+		router := server.Router()
+		router.GlobalMiddleware(session.New(session.NewMemoryStore()))
+		router.Middleware(New(Synchronizer{}))
+		handler := func(response *goyave.Response, request *goyave.Request) {
+			response.String(http.StatusOK, Token(request))
+		}
+		router.Get("/form", handler)
+		router.Post("/submit", handler)
+
+		resp := server.TestRequest(httptest.NewRequest(http.MethodGet, "/form", nil))
+		assert.Nil(t, csrfCookie(resp))
+		token := readBody(t, resp)
+		assert.NotEmpty(t, token)
+		var sessionCookie *http.Cookie
+		for _, c := range resp.Cookies() {
+			if c.Name == "goyave_session" {
+				sessionCookie = c
+			}
+		}
+		require.NotNil(t, sessionCookie)
+
+		req := httptest.NewRequest(http.MethodPost, "/submit", nil)
+		req.AddCookie(sessionCookie)
+		req.Header.Set(DefaultHeaderName, token)
+		resp = server.TestRequest(req)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, token, readBody(t, resp))
+
+		req = httptest.NewRequest(http.MethodPost, "/submit", nil)
+		req.Header.Set(DefaultHeaderName, token)
+		resp = server.TestRequest(req)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		_ = resp.Body.Close()
+	})
+}
@@ -0,0 +1,117 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// Model the database representation of an idempotency record used by the `GormStore`.
+type Model struct {
+	ExpiresAt   time.Time   `gorm:"index"`
+	Header      http.Header `gorm:"serializer:json"`
+	ID          string      `gorm:"primaryKey;size:255"`
+	Fingerprint string      `gorm:"size:64"`
+	Body        []byte
+	Status      int
+	Completed   bool
+	Written     bool
+}
+
+// TableName returns the default table name for the idempotency records: "idempotency_keys".
+func (Model) TableName() string {
+	return "idempotency_keys"
+}
+
+func (m *Model) record() *Record {
+	return &Record{
+		ExpiresAt:   m.ExpiresAt,
+		Header:      m.Header,
+		Key:         m.ID,
+		Fingerprint: m.Fingerprint,
+		Body:        m.Body,
+		Status:      m.Status,
+		Completed:   m.Completed,
+		Written:     m.Written,
+	}
+}
+
+// GormStore an idempotency `Store` keeping the records in a database table, allowing
+// multiple instances of the application to share them. Records are locked by inserting
+// the in-progress record: the primary key guarantees only one request can create it.
+//
+// The table can be created using the `Model` structure with auto-migrations.
+type GormStore struct {
+	DB *gorm.DB
+
+	// Table the name of the table storing the records.
+	// If empty, the default "idempotency_keys" is used.
+	Table string
+}
+
+// NewGormStore create a new `GormStore` using the given database.
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{DB: db}
+}
+
+func (s *GormStore) table(db *gorm.DB) *gorm.DB {
+	if s.Table != "" {
+		return db.Table(s.Table)
+	}
+	return db.Model(&Model{})
+}
+
+// Lock creates an in-progress record for the given key if there is none or if it is expired.
+func (s *GormStore) Lock(ctx context.Context, key, fingerprint string, expiresAt time.Time) (*Record, bool, error) {
+	db := s.DB.WithContext(ctx)
+	if err := s.table(db).Where("id = ? AND expires_at <= ?", key, time.Now()).Delete(&Model{}).Error; err != nil {
+		return nil, false, errors.New(err)
+	}
+
+	m := &Model{ID: key, Fingerprint: fingerprint, ExpiresAt: expiresAt, Header: http.Header{}}
+	res := s.table(db).Clauses(clause.OnConflict{DoNothing: true}).Create(m)
+	if res.Error != nil {
+		return nil, false, errors.New(res.Error)
+	}
+	if res.RowsAffected == 1 {
+		return m.record(), true, nil
+	}
+
+	existing := &Model{}
+	if err := s.table(db).Where("id = ?", key).Take(existing).Error; err != nil {
+		return nil, false, errors.New(err)
+	}
+	return existing.record(), false, nil
+}
+
+// Save the given completed record.
+func (s *GormStore) Save(ctx context.Context, record *Record) error {
+	m := &Model{
+		ExpiresAt:   record.ExpiresAt,
+		Header:      record.Header,
+		ID:          record.Key,
+		Fingerprint: record.Fingerprint,
+		Body:        record.Body,
+		Status:      record.Status,
+		Completed:   record.Completed,
+		Written:     record.Written,
+	}
+	return errors.New(s.table(s.DB.WithContext(ctx)).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"expires_at", "header", "fingerprint", "body", "status", "completed", "written"}),
+	}).Create(m).Error)
+}
+
+// Delete the record identified by the given key.
+func (s *GormStore) Delete(ctx context.Context, key string) error {
+	return errors.New(s.table(s.DB.WithContext(ctx)).Where("id = ?", key).Delete(&Model{}).Error)
+}
+
+// GC deletes all expired records.
+func (s *GormStore) GC(ctx context.Context) error {
+	return errors.New(s.table(s.DB.WithContext(ctx)).Where("expires_at <= ?", time.Now()).Delete(&Model{}).Error)
+}
@@ -0,0 +1,51 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+
+	_ "goyave.dev/goyave/v5/database/dialect/sqlite"
+)
+
+func TestGormStore(t *testing.T) {
+	cfg := config.LoadDefault()
+	cfg.Set("database.connection", "sqlite3")
+	cfg.Set("database.name", "testidempotencygormstore.db")
+	cfg.Set("database.options", "mode=memory")
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+	require.NoError(t, server.DB().AutoMigrate(&Model{}))
+
+	store := NewGormStore(server.DB())
+	testStore(t, store)
+
+	t.Run("GC", func(t *testing.T) {
+		_, _, err := store.Lock(context.Background(), "expired", "fingerprint", time.Now().Add(-time.Second))
+		require.NoError(t, err)
+		require.NoError(t, store.GC(context.Background()))
+
+		var count int64
+		require.NoError(t, server.DB().Model(&Model{}).Where("id = ?", "expired").Count(&count).Error)
+		assert.Zero(t, count)
+		require.NoError(t, server.DB().Model(&Model{}).Where("id = ?", "a").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("custom_table", func(t *testing.T) {
+		require.NoError(t, server.DB().Table("custom_idempotency_keys").AutoMigrate(&Model{}))
+		store := &GormStore{DB: server.DB(), Table: "custom_idempotency_keys"}
+		_, created, err := store.Lock(context.Background(), "key", "fingerprint", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.True(t, created)
+
+		var count int64
+		require.NoError(t, server.DB().Table("custom_idempotency_keys").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+}
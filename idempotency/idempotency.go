@@ -0,0 +1,363 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+const (
+	// HeaderName the name of the request header containing the idempotency key.
+	HeaderName = "Idempotency-Key"
+
+	// ReplayedHeaderName the name of the response header set to "true" on replayed responses.
+	ReplayedHeaderName = "Idempotent-Replayed"
+
+	// DefaultExpiry the default duration during which completed records are kept.
+	DefaultExpiry = 24 * time.Hour
+
+	// DefaultLockTimeout the default duration after which an in-progress record
+	// is considered abandoned (e.g. if the application crashed).
+	DefaultLockTimeout = time.Minute
+
+	// maxKeyLength the maximum length of an idempotency key.
+	maxKeyLength = 255
+)
+
+// ScopeFunc returns a string isolating the idempotency keys of different clients
+// (e.g. the authenticated user ID), so a client cannot replay the response of another.
+type ScopeFunc func(request *goyave.Request) string
+
+// DefaultScope isolates the idempotency keys of different clients by their IP address.
+func DefaultScope(request *goyave.Request) string {
+	addr := request.RemoteAddress()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// Middleware implementation of the IETF "The Idempotency-Key HTTP Header Field" draft.
+//
+// The first request with a given "Idempotency-Key" header is processed normally and its
+// response (status, header and body) is stored. The following requests with the same key
+// receive the stored response, with the "Idempotent-Replayed: true" header, without executing
+// the handler again.
+//   - If the first request is still being processed, responds with "409 Conflict".
+//   - If the request payload differs from the first request, responds with "422 Unprocessable Entity".
+//   - If `Required` is `true` and the header is missing, responds with "400 Bad Request".
+//
+// Errors are written as problem details documents (RFC 9457), as recommended by the draft.
+//
+// The payload fingerprint is computed from the method, the path and the request body.
+// If the request `Data` was already parsed, it is used instead of the raw body. Uploaded
+// files are identified by their name, size and the SHA-256 digest of their content.
+//
+// Responses with a 5xx status, hijacked responses and panicking handlers are not stored,
+// so the request can be retried with the same key. Error responses generated by status
+// handlers (not written before the end of the middleware execution) are not stored either.
+// Keys are scoped by the method and path of the request, and by `Scope`.
+//
+// By default, keys are scoped by the client IP address only. Clients sharing an IP address
+// (e.g. behind a NAT or a proxy not listed in "server.trustedProxies") can replay each
+// other's responses if they use the same key. If your clients are authenticated,
+// always set `Scope` to a function returning the user ID (e.g. `ratelimit.ByUser`).
+type Middleware struct {
+	goyave.Component
+	Store Store
+
+	// Scope isolates the keys of different clients. Defaults to `DefaultScope`.
+	// Return an authenticated user identifier whenever possible.
+	Scope ScopeFunc
+
+	// Methods the methods for which keys are handled. Defaults to "POST" and "PATCH".
+	Methods []string
+
+	// Expiry the duration during which completed records are kept. Defaults to `DefaultExpiry`.
+	Expiry time.Duration
+
+	// LockTimeout the duration after which an in-progress record is considered
+	// abandoned. Defaults to `DefaultLockTimeout`.
+	LockTimeout time.Duration
+
+	// Required if `true`, requests without key are rejected with "400 Bad Request".
+	Required bool
+}
+
+// New create a new idempotency middleware using the given store.
+func New(store Store) *Middleware {
+	return &Middleware{Store: store}
+}
+
+// Handle processes the request or replays the stored response.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		if !m.handlesMethod(request.Method()) {
+			next(response, request)
+			return
+		}
+		idempotencyKey := request.Header().Get(HeaderName)
+		if idempotencyKey == "" {
+			if m.Required {
+				response.Problem(goyave.NewProblem(http.StatusBadRequest).WithDetail("The \"Idempotency-Key\" header is required."))
+				return
+			}
+			next(response, request)
+			return
+		}
+		if len(idempotencyKey) > maxKeyLength {
+			response.Problem(goyave.NewProblem(http.StatusBadRequest).WithDetail("The \"Idempotency-Key\" header is too long."))
+			return
+		}
+
+		key := m.key(request, idempotencyKey)
+		fingerprint, err := m.fingerprint(request)
+		if err != nil {
+			panic(err)
+		}
+
+		ctx := request.Context()
+		record, created, err := m.Store.Lock(ctx, key, fingerprint, time.Now().Add(m.lockTimeout()))
+		if err != nil {
+			panic(errors.New(err))
+		}
+		if !created {
+			m.replay(response, record, fingerprint)
+			return
+		}
+
+		writer := &idempotencyWriter{
+			CommonWriter: goyave.NewCommonWriter(response.Writer()),
+			response:     response,
+		}
+		response.SetWriter(writer)
+
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			// The handler panicked: release the key so the request can be retried.
+			if err := m.Store.Delete(ctx, key); err != nil {
+				m.Logger().Error(errors.New(err))
+			}
+		}()
+
+		next(response, request)
+		completed = true
+
+		status := response.GetStatus()
+		if response.Hijacked() || status >= http.StatusInternalServerError || (!writer.written && status >= http.StatusBadRequest) {
+			if err := m.Store.Delete(ctx, key); err != nil {
+				m.Logger().Error(errors.New(err))
+			}
+			return
+		}
+
+		record.Completed = true
+		record.Status = status
+		record.Written = writer.written
+		record.Header = writer.header
+		record.Body = writer.body.Bytes()
+		record.ExpiresAt = time.Now().Add(m.expiry())
+		if err := m.Store.Save(ctx, record); err != nil {
+			m.Logger().Error(errors.New(err))
+		}
+	}
+}
+
+func (m *Middleware) replay(response *goyave.Response, record *Record, fingerprint string) {
+	switch {
+	case record.Fingerprint != fingerprint:
+		response.Problem(goyave.NewProblem(http.StatusUnprocessableEntity).WithDetail("The \"Idempotency-Key\" was already used with a different request payload."))
+	case !record.Completed:
+		response.Problem(goyave.NewProblem(http.StatusConflict).WithDetail("A request with the same \"Idempotency-Key\" is still being processed."))
+	case !record.Written:
+		response.Header().Set(ReplayedHeaderName, "true")
+		if record.Status != 0 {
+			response.Status(record.Status)
+		}
+	default:
+		header := response.Header()
+		for k, v := range record.Header {
+			header[k] = slices.Clone(v)
+		}
+		header.Set(ReplayedHeaderName, "true")
+		response.WriteHeader(record.Status)
+		if len(record.Body) > 0 {
+			if _, err := response.Write(record.Body); err != nil {
+				m.Logger().Error(errors.New(err))
+			}
+		}
+	}
+}
+
+func (m *Middleware) handlesMethod(method string) bool {
+	if m.Methods == nil {
+		return method == http.MethodPost || method == http.MethodPatch
+	}
+	return slices.Contains(m.Methods, method)
+}
+
+func (m *Middleware) key(request *goyave.Request, idempotencyKey string) string {
+	scope := m.Scope
+	if scope == nil {
+		scope = DefaultScope
+	}
+	var b strings.Builder
+	b.WriteString(scope(request))
+	b.WriteByte(' ')
+	b.WriteString(request.Method())
+	b.WriteByte(' ')
+	b.WriteString(request.URL().Path)
+	b.WriteByte(' ')
+	b.WriteString(idempotencyKey)
+	return b.String()
+}
+
+// fingerprint returns the SHA-256 hash of the method, path and payload of the request.
+func (m *Middleware) fingerprint(request *goyave.Request) (string, error) {
+	h := sha256.New()
+	h.Write([]byte(request.Method() + " " + request.URL().RequestURI() + "\n"))
+	if request.Data != nil {
+		payload, err := fingerprintData(request.Data)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return "", errors.New(err)
+		}
+		h.Write(data)
+	} else if body := request.Body(); body != nil {
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			return "", errors.New(err)
+		}
+		request.Request().Body = io.NopCloser(bytes.NewReader(raw))
+		h.Write(raw)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// fileFingerprint the representation of an uploaded file in the payload fingerprint.
+type fileFingerprint struct {
+	Name   string `json:"name"`
+	Digest string `json:"digest"`
+	Size   int64  `json:"size"`
+}
+
+// fingerprintData returns a copy of the given request data that can be marshaled
+// deterministically: files are replaced with their name, size and content digest.
+// `fsutil.File` cannot be marshaled directly as it generates a random identifier.
+func fingerprintData(data any) (any, error) {
+	switch v := data.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for k, value := range v {
+			f, err := fingerprintData(value)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = f
+		}
+		return result, nil
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, value := range v {
+			f, err := fingerprintData(value)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, f)
+		}
+		return result, nil
+	case []fsutil.File:
+		result := make([]fileFingerprint, 0, len(v))
+		for _, file := range v {
+			f, err := fingerprintFile(file)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, f)
+		}
+		return result, nil
+	case fsutil.File:
+		return fingerprintFile(v)
+	default:
+		return v, nil
+	}
+}
+
+func fingerprintFile(file fsutil.File) (fingerprint fileFingerprint, err error) {
+	f, err := file.Open()
+	if err != nil {
+		return fingerprint, err
+	}
+	defer func() {
+		closeError := f.Close()
+		if err == nil && closeError != nil {
+			err = errors.New(closeError)
+		}
+	}()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return fingerprint, errors.New(err)
+	}
+	fingerprint.Digest = hex.EncodeToString(h.Sum(nil))
+	if file.Header != nil {
+		fingerprint.Name = file.Header.Filename
+		fingerprint.Size = file.Header.Size
+	}
+	return fingerprint, nil
+}
+
+func (m *Middleware) expiry() time.Duration {
+	if m.Expiry <= 0 {
+		return DefaultExpiry
+	}
+	return m.Expiry
+}
+
+func (m *Middleware) lockTimeout() time.Duration {
+	if m.LockTimeout <= 0 {
+		return DefaultLockTimeout
+	}
+	return m.LockTimeout
+}
+
+// idempotencyWriter chained writer capturing the response so it can be stored.
+type idempotencyWriter struct {
+	goyave.CommonWriter
+	response *goyave.Response
+	header   http.Header
+	body     bytes.Buffer
+	written  bool
+}
+
+// PreWrite captures the response header before calling PreWrite on the child writer.
+func (w *idempotencyWriter) PreWrite(b []byte) {
+	header := w.response.Header()
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", http.DetectContentType(b))
+	}
+	w.header = header.Clone()
+	w.written = true
+	w.CommonWriter.PreWrite(b)
+}
+
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.CommonWriter.Write(b)
+}
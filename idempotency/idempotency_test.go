@@ -0,0 +1,281 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/middleware/parse"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+type idempotencyTest struct {
+	server *testutil.TestServer
+	router *goyave.Router
+	store  *MemoryStore
+	calls  int
+}
+
+func prepareIdempotencyTest(t *testing.T, middleware *Middleware) *idempotencyTest {
+	test := &idempotencyTest{
+		server: testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()}),
+		store:  NewMemoryStore(),
+	}
+	if middleware == nil {
+		middleware = New(test.store)
+	} else {
+		middleware.Store = test.store
+	}
+	test.router = test.server.Router()
+	test.router.GlobalMiddleware(&parse.Middleware{})
+	test.router.Middleware(middleware)
+	test.router.Post("/payments", func(response *goyave.Response, _ *goyave.Request) {
+		test.calls++
+		response.Header().Set("Location", "/payments/"+strconv.Itoa(test.calls))
+		response.JSON(http.StatusCreated, map[string]any{"id": test.calls})
+	})
+	return test
+}
+
+func (test *idempotencyTest) request(t *testing.T, method, path, key, body string) (*http.Response, string) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(HeaderName, key)
+	}
+	resp := test.server.TestRequest(req)
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp, string(respBody)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("replay", func(t *testing.T) {
+		test := prepareIdempotencyTest(t, nil)
+		resp, body := test.request(t, http.MethodPost, "/payments", "key1", `{"amount":10}`)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, `{"id":1}`+"\n", body)
+		assert.Empty(t, resp.Header.Get(ReplayedHeaderName))
+
+		resp, body = test.request(t, http.MethodPost, "/payments", "key1", `{ "amount": 10 }`)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, `{"id":1}`+"\n", body)
+		assert.Equal(t, "/payments/1", resp.Header.Get("Location"))
+		assert.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "true", resp.Header.Get(ReplayedHeaderName))
+		assert.Equal(t, 1, test.calls)
+
+		// Another key
+		_, body = test.request(t, http.MethodPost, "/payments", "key2", `{"amount":10}`)
+		assert.Equal(t, `{"id":2}`+"\n", body)
+
+		// No key
+		_, body = test.request(t, http.MethodPost, "/payments", "", `{"amount":10}`)
+		assert.Equal(t, `{"id":3}`+"\n", body)
+		_, body = test.request(t, http.MethodPost, "/payments", "", `{"amount":10}`)
+		assert.Equal(t, `{"id":4}`+"\n", body)
+
+		record, created, err := test.store.Lock(context.Background(), "192.0.2.1 POST /payments key1", "", time.Now())
+		require.NoError(t, err)
+		require.False(t, created)
+		assert.WithinDuration(t, time.Now().Add(DefaultExpiry), record.ExpiresAt, time.Minute)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		test := prepareIdempotencyTest(t, nil)
+		test.request(t, http.MethodPost, "/payments", "key", `{"amount":10}`)
+		resp, body := test.request(t, http.MethodPost, "/payments", "key", `{"amount":20}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		assert.Equal(t, goyave.ContentTypeProblemJSON, resp.Header.Get("Content-Type"))
+		assert.Contains(t, body, `"status":422`)
+
+		resp, _ = test.request(t, http.MethodPost, "/payments?a=1", "key", `{"amount":10}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		assert.Equal(t, 1, test.calls)
+	})
+
+	t.Run("raw_body", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		server.Router().Middleware(New(NewMemoryStore()))
+		calls := 0
+		server.Router().Post("/raw", func(response *goyave.Response, request *goyave.Request) {
+			calls++
+			body, err := io.ReadAll(request.Body())
+			require.NoError(t, err)
+			response.String(http.StatusOK, string(body))
+		})
+
+		for _, body := range []string{"raw body", "raw body", "other body"} {
+			req := httptest.NewRequest(http.MethodPost, "/raw", strings.NewReader(body))
+			req.Header.Set(HeaderName, "key")
+			resp := server.TestRequest(req)
+			respBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			if body == "raw body" {
+				assert.Equal(t, body, string(respBody))
+			} else {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+			}
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		test := prepareIdempotencyTest(t, nil)
+		multipartRequest := func(content string) (*http.Response, string) {
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			require.NoError(t, writer.WriteField("amount", "10"))
+			part, err := writer.CreateFormFile("receipt", "receipt.txt")
+			require.NoError(t, err)
+			_, err = part.Write([]byte(content))
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			req := httptest.NewRequest(http.MethodPost, "/payments", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			req.Header.Set(HeaderName, "key")
+			resp := test.server.TestRequest(req)
+			respBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			return resp, string(respBody)
+		}
+
+		resp, body := multipartRequest("receipt content")
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.JSONEq(t, `{"id":1}`, body)
+
+		resp, body = multipartRequest("receipt content")
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "true", resp.Header.Get(ReplayedHeaderName))
+		assert.JSONEq(t, `{"id":1}`, body)
+
+		resp, _ = multipartRequest("other content")
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		assert.Equal(t, 1, test.calls)
+	})
+
+	t.Run("default_scope", func(t *testing.T) {
+		test := prepareIdempotencyTest(t, nil)
+		test.request(t, http.MethodPost, "/payments", "key", `{}`)
+
+		req := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(HeaderName, "key")
+		req.RemoteAddr = "192.0.2.2:1234"
+		resp := test.server.TestRequest(req)
+		assert.Empty(t, resp.Header.Get(ReplayedHeaderName))
+		assert.Equal(t, 2, test.calls)
+
+		// Same client on another connection
+		req = httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(HeaderName, "key")
+		req.RemoteAddr = "192.0.2.1:5678"
+		resp = test.server.TestRequest(req)
+		assert.Equal(t, "true", resp.Header.Get(ReplayedHeaderName))
+		assert.Equal(t, 2, test.calls)
+		assert.Contains(t, test.store.records, "192.0.2.2 POST /payments key")
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		test := prepareIdempotencyTest(t, nil)
+		_, _, err := test.store.Lock(context.Background(), "192.0.2.1 POST /payments key", "", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		// Fingerprint of the in-progress request
+		test.store.records["192.0.2.1 POST /payments key"].Fingerprint = fingerprintOf(t, `{"amount":10}`)
+
+		resp, body := test.request(t, http.MethodPost, "/payments", "key", `{"amount":10}`)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, goyave.ContentTypeProblemJSON, resp.Header.Get("Content-Type"))
+		assert.Contains(t, body, `"status":409`)
+		assert.Equal(t, 0, test.calls)
+	})
+
+	t.Run("not_stored", func(t *testing.T) {
+		test := prepareIdempotencyTest(t, nil)
+		test.router.Post("/error", func(response *goyave.Response, _ *goyave.Request) {
+			test.calls++
+			response.Status(http.StatusBadRequest)
+		})
+		test.router.Post("/server-error", func(response *goyave.Response, _ *goyave.Request) {
+			test.calls++
+			response.String(http.StatusServiceUnavailable, "unavailable")
+		})
+		test.router.Post("/panic", func(_ *goyave.Response, _ *goyave.Request) {
+			test.calls++
+			panic("test panic")
+		})
+		for _, path := range []string{"/error", "/server-error", "/panic"} {
+			test.request(t, http.MethodPost, path, "key", `{}`)
+			test.request(t, http.MethodPost, path, "key", `{}`)
+		}
+		assert.Equal(t, 6, test.calls)
+		assert.Empty(t, test.store.records)
+	})
+
+	t.Run("no_content", func(t *testing.T) {
+		test := prepareIdempotencyTest(t, nil)
+		test.router.Post("/empty", func(response *goyave.Response, _ *goyave.Request) {
+			test.calls++
+			response.Status(http.StatusAccepted)
+		})
+		test.request(t, http.MethodPost, "/empty", "key", `{}`)
+		resp, body := test.request(t, http.MethodPost, "/empty", "key", `{}`)
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		assert.Equal(t, "true", resp.Header.Get(ReplayedHeaderName))
+		assert.Empty(t, body)
+		assert.Equal(t, 1, test.calls)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		test := prepareIdempotencyTest(t, &Middleware{
+			Required: true,
+			Methods:  []string{http.MethodPost},
+			Scope: func(request *goyave.Request) string {
+				return request.Header().Get("X-User")
+			},
+			Expiry: time.Minute,
+		})
+		resp, _ := test.request(t, http.MethodPost, "/payments", "", `{}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		resp, _ = test.request(t, http.MethodPost, "/payments", strings.Repeat("a", 256), `{}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, 0, test.calls)
+
+		test.request(t, http.MethodPost, "/payments", "key", `{}`)
+		req := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(HeaderName, "key")
+		req.Header.Set("X-User", "other")
+		resp = test.server.TestRequest(req)
+		assert.Empty(t, resp.Header.Get(ReplayedHeaderName))
+		assert.Equal(t, 2, test.calls)
+
+		record := test.store.records[" POST /payments key"]
+		require.NotNil(t, record)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), record.ExpiresAt, 10*time.Second)
+	})
+}
+
+func fingerprintOf(t *testing.T, body string) string {
+	req := testutil.NewTestRequest(http.MethodPost, "/payments", nil)
+	require.NoError(t, json.Unmarshal([]byte(body), &req.Data))
+	fingerprint, err := (&Middleware{}).fingerprint(req)
+	require.NoError(t, err)
+	return fingerprint
+}
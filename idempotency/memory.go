@@ -0,0 +1,66 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore an idempotency `Store` keeping records in memory.
+// Records are lost when the process stops and are not shared between multiple instances
+// of the application. Expired records are replaced when locked again or removed when calling `GC`.
+type MemoryStore struct {
+	records map[string]*Record
+	mu      sync.Mutex
+}
+
+// NewMemoryStore create a new empty `MemoryStore`.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]*Record),
+	}
+}
+
+// Lock creates an in-progress record for the given key if there is none or if it is expired.
+func (s *MemoryStore) Lock(_ context.Context, key, fingerprint string, expiresAt time.Time) (*Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[key]; ok && record.ExpiresAt.After(time.Now()) {
+		clone := *record
+		return &clone, false, nil
+	}
+	record := &Record{Key: key, Fingerprint: fingerprint, ExpiresAt: expiresAt}
+	s.records[key] = record
+	clone := *record
+	return &clone, true, nil
+}
+
+// Save the given completed record.
+func (s *MemoryStore) Save(_ context.Context, record *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clone := *record
+	s.records[record.Key] = &clone
+	return nil
+}
+
+// Delete the record identified by the given key.
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// GC removes all expired records.
+func (s *MemoryStore) GC(_ context.Context) error {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, record := range s.records {
+		if !record.ExpiresAt.After(now) {
+			delete(s.records, key)
+		}
+	}
+	return nil
+}
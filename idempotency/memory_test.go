@@ -0,0 +1,23 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	testStore(t, store)
+
+	t.Run("GC", func(t *testing.T) {
+		_, _, err := store.Lock(context.Background(), "expired", "fingerprint", time.Now().Add(-time.Second))
+		require.NoError(t, err)
+		require.NoError(t, store.GC(context.Background()))
+		assert.NotContains(t, store.records, "expired")
+		assert.Contains(t, store.records, "a")
+	})
+}
@@ -0,0 +1,49 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Record the state of a request identified by an idempotency key.
+type Record struct {
+	ExpiresAt time.Time
+	Header    http.Header
+	Key       string
+
+	// Fingerprint identifies the request payload. A request reusing the key
+	// with a different fingerprint is rejected.
+	Fingerprint string
+	Body        []byte
+	Status      int
+
+	// Completed false while the first request is still being processed.
+	Completed bool
+
+	// Written true if the response header and body were written by the handler.
+	// If false, only the status is replayed, letting the status handlers generate the body.
+	Written bool
+}
+
+// Store persists the idempotency records. Implementations must be safe for concurrent use.
+type Store interface {
+	// Lock atomically creates an in-progress record for the given key if there is
+	// no record for this key or if it is expired. Returns `true` if the record was created.
+	// Otherwise returns the existing record and `false`.
+	Lock(ctx context.Context, key, fingerprint string, expiresAt time.Time) (*Record, bool, error)
+
+	// Save the given completed record, replacing the in-progress record.
+	Save(ctx context.Context, record *Record) error
+
+	// Delete the record identified by the given key, so the request can be retried.
+	// Deleting a record that doesn't exist is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// GarbageCollector can be implemented by stores that need expired
+// records to be purged periodically.
+type GarbageCollector interface {
+	// GC deletes all expired records.
+	GC(ctx context.Context) error
+}
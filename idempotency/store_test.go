@@ -0,0 +1,69 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	t.Run("lock_save", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Minute)
+		record, created, err := store.Lock(ctx, "a", "fingerprint", expiresAt)
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "a", record.Key)
+		assert.Equal(t, "fingerprint", record.Fingerprint)
+		assert.False(t, record.Completed)
+
+		existing, created, err := store.Lock(ctx, "a", "other", expiresAt)
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, "fingerprint", existing.Fingerprint)
+		assert.False(t, existing.Completed)
+
+		record.Completed = true
+		record.Written = true
+		record.Status = http.StatusCreated
+		record.Header = http.Header{"Content-Type": {"application/json"}}
+		record.Body = []byte(`{"id":1}`)
+		record.ExpiresAt = time.Now().Add(time.Hour)
+		require.NoError(t, store.Save(ctx, record))
+
+		existing, created, err = store.Lock(ctx, "a", "fingerprint", expiresAt)
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.True(t, existing.Completed)
+		assert.True(t, existing.Written)
+		assert.Equal(t, http.StatusCreated, existing.Status)
+		assert.Equal(t, http.Header{"Content-Type": {"application/json"}}, existing.Header)
+		assert.Equal(t, []byte(`{"id":1}`), existing.Body)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, created, err := store.Lock(ctx, "b", "fingerprint", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.True(t, created)
+		require.NoError(t, store.Delete(ctx, "b"))
+		require.NoError(t, store.Delete(ctx, "b"))
+		_, created, err = store.Lock(ctx, "b", "fingerprint", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.True(t, created)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, created, err := store.Lock(ctx, "c", "fingerprint", time.Now().Add(-time.Second))
+		require.NoError(t, err)
+		assert.True(t, created)
+		record, created, err := store.Lock(ctx, "c", "other", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "other", record.Fingerprint)
+	})
+}
@@ -3,22 +3,82 @@ package lang
 var enUS = &Language{
 	name: "en-US",
 	lines: map[string]string{
-		"malformed-request":              "Malformed request",
-		"malformed-json":                 "Malformed JSON",
-		"auth.invalid-credentials":       "Invalid credentials.",
-		"auth.no-credentials-provided":   "Invalid or missing authentication header.",
-		"auth.jwt-invalid":               "Your authentication token is invalid.",
-		"auth.jwt-not-valid-yet":         "Your authentication token is not valid yet.",
-		"auth.jwt-expired":               "Your authentication token is expired.",
-		"parse.invalid-query":            "Failed to parse query string due to invalid syntax or unexpected input format.",
-		"parse.json-invalid-body":        "The request Content-Type indicates JSON, but the request body is empty or invalid.",
-		"parse.invalid-content-for-type": "The request content does not match its type. E.g. invalid multipart/form-data or a problem with the file upload.",
-		"parse.error-in-request-body":    "Failed to read request body due to connection issues, timeouts, size mismatches, or corrupted data.",
+		"malformed-request":                  "Malformed request",
+		"malformed-json":                     "Malformed JSON",
+		"auth.invalid-credentials":           "Invalid credentials.",
+		"auth.no-credentials-provided":       "Invalid or missing authentication header.",
+		"auth.jwt-invalid":                   "Your authentication token is invalid.",
+		"auth.jwt-not-valid-yet":             "Your authentication token is not valid yet.",
+		"auth.jwt-expired":                   "Your authentication token is expired.",
+		"parse.invalid-query":                "Failed to parse query string due to invalid syntax or unexpected input format.",
+		"parse.json-invalid-body":            "The request Content-Type indicates JSON, but the request body is empty or invalid.",
+		"parse.invalid-content-for-type":     "The request content does not match its type. E.g. invalid multipart/form-data or a problem with the file upload.",
+		"parse.error-in-request-body":        "Failed to read request body due to connection issues, timeouts, size mismatches, or corrupted data.",
+		"parse.invalid-body":                 "The request body could not be decoded according to its Content-Type.",
+		"parse.unsupported-content-type":     "The request Content-Type is not supported.",
+		"parse.unsupported-content-encoding": "The request Content-Encoding is not supported.",
+		"maintenance":                        "The service is temporarily unavailable for maintenance. Please try again later.",
+		"problem.400":                        "Bad Request",
+		"problem.401":                        "Unauthorized",
+		"problem.402":                        "Payment Required",
+		"problem.403":                        "Forbidden",
+		"problem.404":                        "Not Found",
+		"problem.405":                        "Method Not Allowed",
+		"problem.406":                        "Not Acceptable",
+		"problem.407":                        "Proxy Authentication Required",
+		"problem.408":                        "Request Timeout",
+		"problem.409":                        "Conflict",
+		"problem.410":                        "Gone",
+		"problem.411":                        "Length Required",
+		"problem.412":                        "Precondition Failed",
+		"problem.413":                        "Request Entity Too Large",
+		"problem.414":                        "Request URI Too Long",
+		"problem.415":                        "Unsupported Media Type",
+		"problem.416":                        "Requested Range Not Satisfiable",
+		"problem.417":                        "Expectation Failed",
+		"problem.418":                        "I'm a teapot",
+		"problem.421":                        "Misdirected Request",
+		"problem.422":                        "Unprocessable Entity",
+		"problem.423":                        "Locked",
+		"problem.424":                        "Failed Dependency",
+		"problem.425":                        "Too Early",
+		"problem.426":                        "Upgrade Required",
+		"problem.428":                        "Precondition Required",
+		"problem.429":                        "Too Many Requests",
+		"problem.431":                        "Request Header Fields Too Large",
+		"problem.451":                        "Unavailable For Legal Reasons",
+		"problem.500":                        "Internal Server Error",
+		"problem.501":                        "Not Implemented",
+		"problem.502":                        "Bad Gateway",
+		"problem.503":                        "Service Unavailable",
+		"problem.504":                        "Gateway Timeout",
+		"problem.505":                        "HTTP Version Not Supported",
+		"problem.506":                        "Variant Also Negotiates",
+		"problem.507":                        "Insufficient Storage",
+		"problem.508":                        "Loop Detected",
+		"problem.510":                        "Not Extended",
+		"problem.511":                        "Network Authentication Required",
 	},
 	validation: validationLines{
 		rules: map[string]string{
 			"required":                           "The :field is required.",
 			"required.element":                   "The :field elements are required.",
+			"required_with":                      "The :field is required when :values is present.",
+			"required_with.element":              "The :field elements are required when :values is present.",
+			"required_with_all":                  "The :field is required when :values are present.",
+			"required_with_all.element":          "The :field elements are required when :values are present.",
+			"required_without":                   "The :field is required when :values is not present.",
+			"required_without.element":           "The :field elements are required when :values is not present.",
+			"required_unless":                    "The :field is required unless the :other is in :values.",
+			"required_unless.element":            "The :field elements are required unless the :other is in :values.",
+			"prohibited":                         "The :field is prohibited.",
+			"prohibited.element":                 "The :field elements are prohibited.",
+			"missing":                            "The :field must not be present.",
+			"missing.element":                    "The :field elements must not be present.",
+			"accepted":                           "The :field must be accepted.",
+			"accepted.element":                   "The :field elements must be accepted.",
+			"declined":                           "The :field must be declined.",
+			"declined.element":                   "The :field elements must be declined.",
 			"float32":                            "The :field must be numeric.",
 			"float32.element":                    "The :field elements must be numeric.",
 			"float64":                            "The :field must be numeric.",
@@ -141,6 +201,8 @@ var enUS = &Language{
 			"doesnt_start_with.element":          "The :field elements must not start with any of the following values: :values.",
 			"in":                                 "The :field must have one of the following values: :values.",
 			"in.element":                         "The :field elements must have one of the following values: :values.",
+			"enum":                               "The :field must be one of the following values: :values.",
+			"enum.element":                       "The :field elements must be one of the following values: :values.",
 			"not_in":                             "The :field must not have one of the following values: :values.",
 			"not_in.element":                     "The :field elements must not have one of the following values: :values.",
 			"in_field":                           "The :field must exist in the :other.",
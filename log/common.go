@@ -29,10 +29,11 @@ func CommonLogFormatter(ctx *Context) (string, []slog.Attr) {
 		}
 	}
 
-	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	remoteAddress := ctx.Request.RemoteAddress()
+	host, _, err := net.SplitHostPort(remoteAddress)
 
 	if err != nil {
-		host = req.RemoteAddr
+		host = remoteAddress
 	}
 
 	uri := req.RequestURI
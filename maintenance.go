@@ -0,0 +1,150 @@
+package goyave
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MetaMaintenanceAllow the meta key used to exempt a router or a route from the
+// maintenance mode. Health check routes should have this meta set to `true`.
+const MetaMaintenanceAllow = "maintenance.allow"
+
+// MaintenanceBypassCookieName the name of the cookie allowing clients
+// to bypass the maintenance mode.
+const MaintenanceBypassCookieName = "goyave_maintenance_bypass"
+
+// maintenanceFileCheckInterval the minimum duration between two checks of the maintenance flag file.
+const maintenanceFileCheckInterval = time.Second
+
+// maintenanceBypassMaxAge the lifetime of the maintenance bypass cookie.
+const maintenanceBypassMaxAge = 12 * time.Hour
+
+// ExtraMaintenance the key used in `Context.Extra` to mark responses
+// rejected because the server is in maintenance mode.
+type ExtraMaintenance struct{}
+
+// maintenanceState the maintenance mode state of a server.
+type maintenanceState struct {
+	lastFileCheck time.Time
+	enabled       atomic.Bool
+	fileFlag      bool
+	mu            sync.Mutex
+}
+
+// EnableMaintenance turns the maintenance mode on: all requests are answered with
+// "503 Service Unavailable", except those to routes with the `MetaMaintenanceAllow` meta
+// and those from clients holding a valid bypass cookie.
+func (s *Server) EnableMaintenance() {
+	s.maintenance.enabled.Store(true)
+}
+
+// DisableMaintenance turns the maintenance mode off. The maintenance mode stays
+// on as long as the flag file defined by the "server.maintenance.file" config entry exists.
+func (s *Server) DisableMaintenance() {
+	s.maintenance.enabled.Store(false)
+}
+
+// IsInMaintenance returns true if the maintenance mode is on, either because it was enabled
+// with the "server.maintenance.enabled" config entry or `EnableMaintenance()`, or because the
+// flag file defined by the "server.maintenance.file" config entry exists.
+// The existence of the file is checked at most once per second.
+func (s *Server) IsInMaintenance() bool {
+	if s.maintenance.enabled.Load() {
+		return true
+	}
+	file := s.config.GetString("server.maintenance.file")
+	if file == "" {
+		return false
+	}
+
+	s.maintenance.mu.Lock()
+	defer s.maintenance.mu.Unlock()
+	if now := time.Now(); now.Sub(s.maintenance.lastFileCheck) >= maintenanceFileCheckInterval {
+		_, err := os.Stat(file)
+		s.maintenance.fileFlag = err == nil
+		s.maintenance.lastFileCheck = now
+	}
+	return s.maintenance.fileFlag
+}
+
+// maintenanceBypassToken returns the value of the bypass cookie, derived from
+// the "server.maintenance.secret" config entry. Returns an empty string if there is no secret.
+func (s *Server) maintenanceBypassToken() string {
+	secret := s.config.GetString("server.maintenance.secret")
+	if secret == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(MaintenanceBypassCookieName))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// maintenanceMiddleware answers all requests with "503 Service Unavailable" while
+// the server is in maintenance mode. The "Retry-After" header is set to the
+// "server.maintenance.retryAfter" config entry (in seconds) if it is greater than 0.
+//
+// If the "server.maintenance.secret" config entry is set, clients requesting the path
+// "/<secret>" receive a bypass cookie and are redirected to the home page. Requests with
+// a valid bypass cookie, as well as requests to routes with the `MetaMaintenanceAllow` meta,
+// are processed normally.
+type maintenanceMiddleware struct {
+	Component
+}
+
+func (m *maintenanceMiddleware) Handle(next Handler) Handler {
+	return func(response *Response, request *Request) {
+		server := m.Server()
+		if !server.IsInMaintenance() {
+			next(response, request)
+			return
+		}
+		if allow, ok := request.Route.LookupMeta(MetaMaintenanceAllow); ok && allow == true {
+			next(response, request)
+			return
+		}
+
+		if token := server.maintenanceBypassToken(); token != "" {
+			if cookie, err := request.Request().Cookie(MaintenanceBypassCookieName); err == nil && hmac.Equal([]byte(cookie.Value), []byte(token)) {
+				next(response, request)
+				return
+			}
+			secret := server.config.GetString("server.maintenance.secret")
+			if strings.TrimPrefix(request.URL().Path, server.config.GetString("server.proxy.base")) == "/"+secret {
+				response.Cookie(&http.Cookie{
+					Name:     MaintenanceBypassCookieName,
+					Value:    token,
+					Path:     "/",
+					MaxAge:   int(maintenanceBypassMaxAge.Seconds()),
+					Secure:   request.Scheme() == "https",
+					HttpOnly: true,
+					SameSite: http.SameSiteLaxMode,
+				})
+				http.Redirect(response, request.Request(), server.config.GetString("server.proxy.base")+"/", http.StatusFound)
+				return
+			}
+		}
+
+		if retryAfter := server.config.GetInt("server.maintenance.retryAfter"); retryAfter > 0 {
+			response.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		}
+		request.Extra[ExtraMaintenance{}] = true
+		response.Status(http.StatusServiceUnavailable)
+	}
+}
+
+// maintenanceMessage returns the localized maintenance message if the
+// request was rejected because of the maintenance mode.
+func maintenanceMessage(request *Request) (string, bool) {
+	if request == nil || request.Extra[ExtraMaintenance{}] != true {
+		return "", false
+	}
+	return request.Lang.Get("maintenance"), true
+}
@@ -0,0 +1,147 @@
+package goyave
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/config"
+)
+
+func prepareMaintenanceTest(t *testing.T, cfg *config.Config) *Server {
+	server, err := New(Options{Config: cfg})
+	require.NoError(t, err)
+	server.Router().Get("/test", func(response *Response, _ *Request) {
+		response.String(http.StatusOK, "ok")
+	})
+	server.Router().Get("/health", func(response *Response, _ *Request) {
+		response.String(http.StatusOK, "healthy")
+	}).SetMeta(MetaMaintenanceAllow, true)
+	return server
+}
+
+func serveMaintenanceTest(server *Server, req *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	server.Router().ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestMaintenance(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		server := prepareMaintenanceTest(t, config.LoadDefault())
+		assert.False(t, server.IsInMaintenance())
+		recorder := serveMaintenanceTest(server, httptest.NewRequest(http.MethodGet, "/test", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "ok", recorder.Body.String())
+	})
+
+	t.Run("config", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("server.maintenance.enabled", true)
+		server := prepareMaintenanceTest(t, cfg)
+		assert.True(t, server.IsInMaintenance())
+
+		recorder := serveMaintenanceTest(server, httptest.NewRequest(http.MethodGet, "/test", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		assert.Equal(t, "60", recorder.Header().Get("Retry-After"))
+		assert.Equal(t, "{\"error\":\"The service is temporarily unavailable for maintenance. Please try again later.\"}\n", recorder.Body.String())
+
+		server.DisableMaintenance()
+		assert.False(t, server.IsInMaintenance())
+		recorder = serveMaintenanceTest(server, httptest.NewRequest(http.MethodGet, "/test", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("api", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("server.maintenance.retryAfter", 0)
+		server := prepareMaintenanceTest(t, cfg)
+		server.EnableMaintenance()
+		assert.True(t, server.IsInMaintenance())
+
+		recorder := serveMaintenanceTest(server, httptest.NewRequest(http.MethodGet, "/test", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		assert.Empty(t, recorder.Header().Get("Retry-After"))
+	})
+
+	t.Run("file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "maintenance")
+		cfg := config.LoadDefault()
+		cfg.Set("server.maintenance.file", file)
+		server := prepareMaintenanceTest(t, cfg)
+		assert.False(t, server.IsInMaintenance())
+
+		require.NoError(t, os.WriteFile(file, []byte{}, 0o600))
+		assert.False(t, server.IsInMaintenance()) // Not checked again yet
+		server.maintenance.lastFileCheck = time.Time{}
+		assert.True(t, server.IsInMaintenance())
+
+		server.DisableMaintenance()
+		assert.True(t, server.IsInMaintenance()) // The file still exists
+
+		require.NoError(t, os.Remove(file))
+		server.maintenance.lastFileCheck = time.Time{}
+		assert.False(t, server.IsInMaintenance())
+	})
+
+	t.Run("allow_meta", func(t *testing.T) {
+		server := prepareMaintenanceTest(t, config.LoadDefault())
+		server.EnableMaintenance()
+		recorder := serveMaintenanceTest(server, httptest.NewRequest(http.MethodGet, "/health", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "healthy", recorder.Body.String())
+	})
+
+	t.Run("bypass", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("server.maintenance.secret", "s3cr3t")
+		server := prepareMaintenanceTest(t, cfg)
+		server.EnableMaintenance()
+
+		recorder := serveMaintenanceTest(server, httptest.NewRequest(http.MethodGet, "/s3cr3t", nil))
+		assert.Equal(t, http.StatusFound, recorder.Code)
+		assert.Equal(t, "/", recorder.Header().Get("Location"))
+		cookies := recorder.Result().Cookies()
+		require.Len(t, cookies, 1)
+		cookie := cookies[0]
+		assert.Equal(t, MaintenanceBypassCookieName, cookie.Name)
+		assert.Equal(t, server.maintenanceBypassToken(), cookie.Value)
+		assert.True(t, cookie.HttpOnly)
+		assert.False(t, cookie.Secure)
+		assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.AddCookie(cookie)
+		recorder = serveMaintenanceTest(server, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.AddCookie(&http.Cookie{Name: MaintenanceBypassCookieName, Value: "invalid"})
+		recorder = serveMaintenanceTest(server, req)
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	})
+
+	t.Run("no_secret", func(t *testing.T) {
+		server := prepareMaintenanceTest(t, config.LoadDefault())
+		server.EnableMaintenance()
+		assert.Empty(t, server.maintenanceBypassToken())
+		recorder := serveMaintenanceTest(server, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		assert.Empty(t, recorder.Result().Cookies())
+	})
+
+	t.Run("problem_status_handler", func(t *testing.T) {
+		server := prepareMaintenanceTest(t, config.LoadDefault())
+		server.Router().StatusHandler(&ProblemStatusHandler{}, http.StatusServiceUnavailable)
+		server.EnableMaintenance()
+		recorder := serveMaintenanceTest(server, httptest.NewRequest(http.MethodGet, "/test", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		assert.Equal(t, ContentTypeProblemJSON, recorder.Header().Get("Content-Type"))
+		assert.Contains(t, recorder.Body.String(), "\"detail\":\"The service is temporarily unavailable for maintenance. Please try again later.\"")
+	})
+}
@@ -3,6 +3,8 @@ package goyave
 import (
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"gorm.io/gorm"
 	"goyave.dev/goyave/v5/cors"
@@ -129,6 +131,52 @@ type validateRequestMiddleware struct {
 	Component
 	BodyRules  RuleSetFunc
 	QueryRules RuleSetFunc
+
+	bodyCache  staticRulesCache
+	queryCache staticRulesCache
+
+	// Static if true, the rule sets are generated and compiled once,
+	// on the first request, and cached. See `Route.StaticRules()`.
+	Static bool
+}
+
+// staticRulesCache holds the rules compiled from a `RuleSetFunc` marked as static.
+type staticRulesCache struct {
+	rules atomic.Pointer[validation.StaticRules]
+	mu    sync.Mutex
+}
+
+// get returns the cached rules, compiling them if they are not cached yet.
+// The lock is only acquired on the compilation path. The result is only stored
+// if the compilation succeeds, so if `compile` panics, the next call tries again.
+func (c *staticRulesCache) get(compile func() *validation.StaticRules) *validation.StaticRules {
+	if rules := c.rules.Load(); rules != nil {
+		return rules
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if rules := c.rules.Load(); rules != nil {
+		return rules
+	}
+	rules := compile()
+	c.rules.Store(rules)
+	return rules
+}
+
+// reset removes the cached rules so they are compiled again on the next call of `get()`.
+func (c *staticRulesCache) reset() {
+	c.rules.Store(nil)
+}
+
+// rules returns the rules to use to validate the given request. If the middleware is
+// static, the rules are compiled on the first successful call and only copied afterwards.
+func (m *validateRequestMiddleware) rules(cache *staticRulesCache, ruleSetFunc RuleSetFunc, r *Request) validation.Rules {
+	if !m.Static {
+		return ruleSetFunc(r).AsRules()
+	}
+	return cache.get(func() *validation.StaticRules {
+		return validation.Static(ruleSetFunc(r))
+	}).AsRules()
 }
 
 func (m *validateRequestMiddleware) Handle(next Handler) Handler {
@@ -148,13 +196,14 @@ func (m *validateRequestMiddleware) Handle(next Handler) Handler {
 		if m.QueryRules != nil {
 			opt := &validation.Options{
 				Data:                     r.Query,
-				Rules:                    m.QueryRules(r).AsRules(),
+				Rules:                    m.rules(&m.queryCache, m.QueryRules, r),
 				ConvertSingleValueArrays: true,
 				Language:                 r.Lang,
 				DB:                       db,
 				Config:                   m.Config(),
 				Logger:                   m.Logger(),
 				Extra:                    extra,
+				RecordViolations:         true,
 			}
 			r.Extra[ExtraQueryValidationRules{}] = opt.Rules
 			var err []error
@@ -169,13 +218,14 @@ func (m *validateRequestMiddleware) Handle(next Handler) Handler {
 		if m.BodyRules != nil {
 			opt := &validation.Options{
 				Data:                     r.Data,
-				Rules:                    m.BodyRules(r).AsRules(),
+				Rules:                    m.rules(&m.bodyCache, m.BodyRules, r),
 				ConvertSingleValueArrays: !strings.HasPrefix(contentType, "application/json"),
 				Language:                 r.Lang,
 				DB:                       db,
 				Config:                   m.Config(),
 				Logger:                   m.Logger(),
 				Extra:                    extra,
+				RecordViolations:         true,
 			}
 			r.Extra[ExtraBodyValidationRules{}] = opt.Rules
 			var err []error
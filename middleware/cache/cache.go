@@ -0,0 +1,478 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	stderrors "errors"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// MetaCache the meta key used to enable the cache middleware on a router or a route.
+// Its value must be a `*Options`. Routes without this meta are never cached.
+const MetaCache = "goyave.cache"
+
+// ExtraTags the key used in `Context.Extra` to store the additional
+// cache tags of the current response. See `AddTags()`.
+type ExtraTags struct{}
+
+// revalidationKey context key marking the background requests
+// made to revalidate stale entries.
+type revalidationKey struct{}
+
+// cacheableStatus the status codes of the responses that can be cached.
+var cacheableStatus = map[int]struct{}{
+	http.StatusOK:                   {},
+	http.StatusNonAuthoritativeInfo: {},
+	http.StatusNoContent:            {},
+	http.StatusMovedPermanently:     {},
+	http.StatusNotFound:             {},
+	http.StatusGone:                 {},
+}
+
+// KeyFunc returns the key identifying the cached responses for the given request.
+type KeyFunc func(request *goyave.Request) string
+
+// Options defines how the responses of a route are cached.
+type Options struct {
+	// Key returns the cache key of the request. Defaults to
+	// `DefaultKey`: the request host, path and query.
+	Key KeyFunc
+
+	// Tags the tags added to all the entries of the route. Additional tags
+	// can be added for each response using `AddTags()`.
+	Tags []string
+
+	// TTL the duration during which responses are fresh if they don't define
+	// a "max-age" or "s-maxage" "Cache-Control" directive.
+	TTL time.Duration
+
+	// StaleWhileRevalidate the duration during which stale responses can still be
+	// served while they are being revalidated in the background, if the response doesn't
+	// define a "stale-while-revalidate" "Cache-Control" directive.
+	StaleWhileRevalidate time.Duration
+}
+
+// DefaultKey returns the request host, path and query.
+func DefaultKey(request *goyave.Request) string {
+	return request.Host() + request.URL().RequestURI()
+}
+
+// Middleware caches the full responses (status, header and body) of the `GET` routes
+// having the `MetaCache` meta. Cached responses are also used for `HEAD` requests.
+//
+// The cache key takes the "Vary" response header into account: a different entry is stored
+// for each combination of values of the request headers listed in "Vary". Responses
+// with "Vary: *" are not cached.
+//
+// Request "Cache-Control" directives are honored:
+//   - "no-store": the cache is bypassed entirely
+//   - "no-cache": the cache is not used but the response is stored
+//   - "max-age": entries older than this value are not used
+//   - "only-if-cached": returns "504 Gateway Timeout" if there is no usable entry
+//
+// Responses are stored only if their status is cacheable, if they don't set cookies and
+// if their "Cache-Control" header doesn't contain "no-store", "no-cache" or "private".
+//
+// As required from shared caches by RFC 9111 section 3.5, the responses to requests
+// containing an "Authorization" header are stored only if their "Cache-Control" header
+// contains "public", "must-revalidate" or "s-maxage". For the same reason, requests
+// containing an "Authorization" header are only served entries having one of these
+// directives. The same applies to requests authenticated by other means, for example
+// using a cookie, identified by a non-nil `request.User`. As the user is only known once
+// the authentication middleware has been executed, this check is done before storing the
+// response, so authenticated responses are never stored. Authenticated responses are
+// therefore never shared between users unless they are explicitly marked as shareable.
+// Their freshness is defined by the "s-maxage" or "max-age" directives, or by `Options.TTL`.
+// The "stale-while-revalidate" directive (or `Options.StaleWhileRevalidate`) allows
+// serving stale entries while the route is executed again in the background to refresh them.
+//
+// The "X-Cache" header is set to "HIT", "STALE" or "MISS", and the "Age" header is set
+// on responses served from the cache.
+//
+// This middleware should be executed after the compress middleware, so the
+// cached bodies are not compressed. Responses not written before the end of the
+// middleware execution (such as empty responses or status handler responses) are not cached.
+//
+// Use `Store.InvalidateTags()` or `Middleware.InvalidateTags()` from the handlers modifying
+// resources to purge the related entries.
+type Middleware struct {
+	goyave.Component
+	Store Store
+
+	// MaxBodySize the maximum size of a response body to be cached, in bytes.
+	// 0 means no limit.
+	MaxBodySize int64
+
+	revalidating sync.Map
+}
+
+// New create a new cache middleware using the given store.
+func New(store Store) *Middleware {
+	return &Middleware{Store: store}
+}
+
+// AddTags adds cache tags to the response of the given request. This function is
+// meant to be used in handlers so entries can be invalidated more precisely
+// (e.g. `cache.AddTags(request, "post:"+id)`).
+func AddTags(request *goyave.Request, tags ...string) {
+	existing, _ := request.Extra[ExtraTags{}].([]string)
+	request.Extra[ExtraTags{}] = append(existing, tags...)
+}
+
+// InvalidateTags deletes all the entries having at least one of the given tags.
+func (m *Middleware) InvalidateTags(ctx context.Context, tags ...string) error {
+	return errors.New(m.Store.InvalidateTags(ctx, tags...))
+}
+
+// Handle serves the response from the cache if possible, or captures and stores it.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		o, ok := request.Route.LookupMeta(MetaCache)
+		options, _ := o.(*Options)
+		method := request.Method()
+		if !ok || options == nil || (method != http.MethodGet && method != http.MethodHead) ||
+			response.Hijacked() || request.Header().Get("Upgrade") != "" {
+			next(response, request)
+			return
+		}
+
+		directives := parseCacheControl(request.Header().Get("Cache-Control"))
+		if _, noStore := directives["no-store"]; noStore {
+			next(response, request)
+			return
+		}
+
+		key := m.key(request, options)
+		if request.Context().Value(revalidationKey{}) == nil {
+			if m.serveFromCache(response, request, key, directives) {
+				return
+			}
+			if _, onlyIfCached := directives["only-if-cached"]; onlyIfCached {
+				response.Status(http.StatusGatewayTimeout)
+				return
+			}
+		}
+
+		if method == http.MethodHead {
+			next(response, request)
+			return
+		}
+
+		writer := &cacheWriter{
+			CommonWriter: goyave.NewCommonWriter(response.Writer()),
+			middleware:   m,
+			response:     response,
+			options:      options,
+			request:      request,
+		}
+		response.SetWriter(writer)
+
+		next(response, request)
+
+		if writer.capture && !response.Hijacked() {
+			m.store(request, key, writer)
+		}
+	}
+}
+
+func (m *Middleware) key(request *goyave.Request, options *Options) string {
+	if options.Key != nil {
+		return options.Key(request)
+	}
+	return DefaultKey(request)
+}
+
+// lookup the entry matching the request, taking the vary marker into account.
+func (m *Middleware) lookup(request *goyave.Request, key string) (*Entry, string) {
+	entry, err := m.Store.Get(request.Context(), key)
+	if err != nil {
+		if !stderrors.Is(err, ErrNotFound) {
+			m.Logger().Error(errors.New(err))
+		}
+		return nil, ""
+	}
+	if len(entry.Vary) == 0 {
+		return entry, key
+	}
+	key = variantKey(request, key, entry.Vary)
+	entry, err = m.Store.Get(request.Context(), key)
+	if err != nil {
+		if !stderrors.Is(err, ErrNotFound) {
+			m.Logger().Error(errors.New(err))
+		}
+		return nil, ""
+	}
+	return entry, key
+}
+
+func (m *Middleware) serveFromCache(response *goyave.Response, request *goyave.Request, key string, directives map[string]string) bool {
+	if _, noCache := directives["no-cache"]; noCache {
+		return false
+	}
+	entry, variant := m.lookup(request, key)
+	if entry == nil || len(entry.Vary) > 0 {
+		return false
+	}
+	if authorized(request) && !shareable(parseCacheControl(entry.Header.Get("Cache-Control"))) {
+		return false
+	}
+
+	now := time.Now()
+	age := now.Sub(entry.StoredAt)
+	if maxAge, ok := directives["max-age"]; ok {
+		if seconds, err := strconv.Atoi(maxAge); err == nil && age > time.Duration(seconds)*time.Second {
+			return false
+		}
+	}
+
+	status := "HIT"
+	if !entry.IsFresh(now) {
+		if !entry.IsUsable(now) {
+			return false
+		}
+		status = "STALE"
+		m.revalidate(request, variant)
+	}
+
+	header := response.Header()
+	for k, v := range entry.Header {
+		header[k] = slices.Clone(v)
+	}
+	header.Set("Age", strconv.Itoa(int(age.Seconds())))
+	header.Set("X-Cache", status)
+	response.WriteHeader(entry.Status)
+	if request.Method() != http.MethodHead && len(entry.Body) > 0 {
+		if _, err := response.Write(entry.Body); err != nil {
+			m.Logger().Error(errors.New(err))
+		}
+	}
+	return true
+}
+
+// revalidate executes the request again in the background to refresh
+// the entry identified by the given key. Only one revalidation per key
+// can run at the same time.
+func (m *Middleware) revalidate(request *goyave.Request, key string) {
+	if _, running := m.revalidating.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+	ctx := context.WithValue(context.WithoutCancel(request.Context()), revalidationKey{}, true)
+	req := request.Request().Clone(ctx)
+	req.Body = http.NoBody
+	router := m.Server().Router()
+	go func() {
+		defer m.revalidating.Delete(key)
+		router.ServeHTTP(&discardResponseWriter{header: http.Header{}}, req)
+	}()
+}
+
+func (m *Middleware) store(request *goyave.Request, key string, writer *cacheWriter) {
+	header := writer.header.Clone()
+	header.Del("X-Cache")
+	header.Del("Age")
+
+	now := time.Now()
+	tags := slices.Clone(writer.options.Tags)
+	if extraTags, ok := request.Extra[ExtraTags{}].([]string); ok {
+		tags = append(tags, extraTags...)
+	}
+	entry := &Entry{
+		StoredAt:   now,
+		FreshUntil: now.Add(writer.ttl),
+		StaleUntil: now.Add(writer.ttl + writer.staleWhileRevalidate),
+		Header:     header,
+		Body:       slices.Clone(writer.body.Bytes()),
+		Tags:       tags,
+		Status:     writer.response.GetStatus(),
+	}
+
+	ctx := request.Context()
+	if vary := varyHeaders(header); len(vary) > 0 {
+		marker := &Entry{
+			StoredAt:   entry.StoredAt,
+			FreshUntil: entry.FreshUntil,
+			StaleUntil: entry.StaleUntil,
+			Tags:       writer.options.Tags,
+			Vary:       vary,
+		}
+		if err := m.Store.Set(ctx, key, marker); err != nil {
+			m.Logger().Error(errors.New(err))
+			return
+		}
+		key = variantKey(request, key, vary)
+	}
+	if err := m.Store.Set(ctx, key, entry); err != nil {
+		m.Logger().Error(errors.New(err))
+	}
+}
+
+// cacheWriter chained writer capturing the response so it can be stored.
+type cacheWriter struct {
+	goyave.CommonWriter
+	middleware           *Middleware
+	response             *goyave.Response
+	options              *Options
+	header               http.Header
+	body                 bytes.Buffer
+	ttl                  time.Duration
+	staleWhileRevalidate time.Duration
+	request              *goyave.Request
+	capture              bool
+}
+
+// PreWrite checks if the response can be cached before calling PreWrite on the child writer.
+// If not set, the "Content-Type" header is detected using `http.DetectContentType()`
+// so it is stored with the response.
+func (w *cacheWriter) PreWrite(b []byte) {
+	header := w.response.Header()
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", http.DetectContentType(b))
+	}
+	w.capture = w.cacheable(header)
+	if w.capture {
+		w.header = header.Clone()
+	}
+	header.Set("X-Cache", "MISS")
+	w.CommonWriter.PreWrite(b)
+}
+
+func (w *cacheWriter) Write(b []byte) (int, error) {
+	if w.capture {
+		if w.middleware.MaxBodySize > 0 && int64(w.body.Len()+len(b)) > w.middleware.MaxBodySize {
+			w.capture = false
+			w.body = bytes.Buffer{}
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.CommonWriter.Write(b)
+}
+
+func (w *cacheWriter) cacheable(header http.Header) bool {
+	status := w.response.GetStatus()
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if _, ok := cacheableStatus[status]; !ok || header.Get("Set-Cookie") != "" {
+		return false
+	}
+	if slices.Contains(varyHeaders(header), "*") {
+		return false
+	}
+
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	for _, d := range []string{"no-store", "no-cache", "private"} {
+		if _, ok := directives[d]; ok {
+			return false
+		}
+	}
+	if authorized(w.request) && !shareable(directives) {
+		return false
+	}
+
+	w.ttl = w.options.TTL
+	if seconds, ok := directiveSeconds(directives, "s-maxage"); ok {
+		w.ttl = seconds
+	} else if seconds, ok := directiveSeconds(directives, "max-age"); ok {
+		w.ttl = seconds
+	}
+	w.staleWhileRevalidate = w.options.StaleWhileRevalidate
+	if seconds, ok := directiveSeconds(directives, "stale-while-revalidate"); ok {
+		w.staleWhileRevalidate = seconds
+	}
+	return w.ttl > 0
+}
+
+// discardResponseWriter `http.ResponseWriter` used for background revalidation requests.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(_ int) {}
+
+// parseCacheControl returns the directives of the given "Cache-Control" header value.
+// Directive names are lowercase and values are unquoted.
+func parseCacheControl(value string) map[string]string {
+	directives := make(map[string]string)
+	for _, d := range strings.Split(value, ",") {
+		d = strings.TrimSpace(d)
+		if d == "" {
+			continue
+		}
+		name, val, _ := strings.Cut(d, "=")
+		directives[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(val), `"`)
+	}
+	return directives
+}
+
+// authorized returns true if the request contains an "Authorization" header or
+// if it is authenticated (`request.User` is not nil), for example using a cookie.
+func authorized(request *goyave.Request) bool {
+	return request.Header().Get("Authorization") != "" || request.User != nil
+}
+
+// shareable returns true if the given response directives allow a shared cache to
+// use the response for requests containing an "Authorization" header (RFC 9111 section 3.5).
+func shareable(directives map[string]string) bool {
+	for _, d := range []string{"public", "must-revalidate", "s-maxage"} {
+		if _, ok := directives[d]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func directiveSeconds(directives map[string]string, name string) (time.Duration, bool) {
+	value, ok := directives[name]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// varyHeaders returns the sorted canonical names of the headers listed in the "Vary" header.
+func varyHeaders(header http.Header) []string {
+	vary := []string{}
+	for _, v := range header.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				vary = append(vary, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	slices.Sort(vary)
+	return slices.Compact(vary)
+}
+
+func variantKey(request *goyave.Request, key string, vary []string) string {
+	var b strings.Builder
+	b.WriteString(key)
+	for _, name := range vary {
+		b.WriteByte(0)
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(strings.Join(request.Header().Values(name), ","))
+	}
+	return b.String()
+}
@@ -0,0 +1,337 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+type cacheTest struct {
+	server *testutil.TestServer
+	router *goyave.Router
+	store  *MemoryStore
+	calls  *atomic.Int64
+}
+
+func prepareCacheTest(t *testing.T, options *Options) *cacheTest {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+	store := NewMemoryStore(0)
+	router := server.Router()
+	router.GlobalMiddleware(New(store))
+	calls := &atomic.Int64{}
+	router.Get("/", func(response *goyave.Response, request *goyave.Request) {
+		n := calls.Add(1)
+		if cc := request.URL().Query().Get("cc"); cc != "" {
+			response.Header().Set("Cache-Control", cc)
+		}
+		response.String(http.StatusOK, "call "+strconv.FormatInt(n, 10))
+	}).SetMeta(MetaCache, options)
+	return &cacheTest{server: server, router: router, store: store, calls: calls}
+}
+
+// cookieAuthMiddleware sets the request's user from the "session" cookie.
+type cookieAuthMiddleware struct {
+	goyave.Component
+}
+
+func (m *cookieAuthMiddleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		if cookie, err := request.Request().Cookie("session"); err == nil {
+			request.User = cookie.Value
+		}
+		next(response, request)
+	}
+}
+
+func (c *cacheTest) request(t *testing.T, req *http.Request) (*http.Response, string) {
+	resp := c.server.TestRequest(req)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp, string(body)
+}
+
+func TestParseCacheControl(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"no-cache":               "",
+		"max-age":                "60",
+		"stale-while-revalidate": "30",
+		"private":                "Set-Cookie",
+	}, parseCacheControl(`No-Cache, max-age=60,stale-while-revalidate="30", private="Set-Cookie",`))
+	assert.Empty(t, parseCacheControl(""))
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("hit", func(t *testing.T) {
+		c := prepareCacheTest(t, &Options{TTL: time.Minute, Tags: []string{"home"}})
+		resp, body := c.request(t, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "call 1", body)
+		assert.Equal(t, "MISS", resp.Header.Get("X-Cache"))
+
+		resp, body = c.request(t, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "call 1", body)
+		assert.Equal(t, "HIT", resp.Header.Get("X-Cache"))
+		assert.Equal(t, "0", resp.Header.Get("Age"))
+		assert.Equal(t, "text/plain; charset=utf-8", resp.Header.Get("Content-Type"))
+
+		resp, body = c.request(t, httptest.NewRequest(http.MethodHead, "/", nil))
+		assert.Equal(t, "HIT", resp.Header.Get("X-Cache"))
+		assert.Empty(t, body)
+		assert.Equal(t, int64(1), c.calls.Load())
+
+		// Different query: different entry
+		_, body = c.request(t, httptest.NewRequest(http.MethodGet, "/?a=1", nil))
+		assert.Equal(t, "call 2", body)
+
+		// Invalidation
+		require.NoError(t, New(c.store).InvalidateTags(context.Background(), "home"))
+		assert.Equal(t, 0, c.store.Len())
+		_, body = c.request(t, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "call 3", body)
+	})
+
+	t.Run("not_opted_in", func(t *testing.T) {
+		c := prepareCacheTest(t, nil)
+		c.router.Get("/other", func(response *goyave.Response, _ *goyave.Request) {
+			response.String(http.StatusOK, "other")
+		})
+		for i := 0; i < 2; i++ {
+			resp, _ := c.request(t, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Empty(t, resp.Header.Get("X-Cache"))
+			resp, _ = c.request(t, httptest.NewRequest(http.MethodGet, "/other", nil))
+			assert.Empty(t, resp.Header.Get("X-Cache"))
+		}
+		assert.Equal(t, 0, c.store.Len())
+	})
+
+	t.Run("request_cache_control", func(t *testing.T) {
+		c := prepareCacheTest(t, &Options{TTL: time.Minute})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Cache-Control", "only-if-cached")
+		resp, _ := c.request(t, req)
+		assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Cache-Control", "no-store")
+		_, body := c.request(t, req)
+		assert.Equal(t, "call 1", body)
+		assert.Equal(t, 0, c.store.Len())
+
+		_, body = c.request(t, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "call 2", body)
+
+		// no-cache: not served from the cache but stored
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Cache-Control", "no-cache")
+		_, body = c.request(t, req)
+		assert.Equal(t, "call 3", body)
+		_, body = c.request(t, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "call 3", body)
+
+		// max-age
+		entry, err := c.store.Get(context.Background(), "example.com/")
+		require.NoError(t, err)
+		entry.StoredAt = entry.StoredAt.Add(-10 * time.Second)
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Cache-Control", "max-age=5")
+		_, body = c.request(t, req)
+		assert.Equal(t, "call 4", body)
+	})
+
+	t.Run("response_cache_control", func(t *testing.T) {
+		c := prepareCacheTest(t, &Options{})
+
+		// No TTL: not cached
+		c.request(t, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, 0, c.store.Len())
+
+		for _, cc := range []string{"no-store", "private,max-age=60", "no-cache,max-age=60", "max-age=0"} {
+			c.request(t, httptest.NewRequest(http.MethodGet, "/?cc="+cc, nil))
+			assert.Equal(t, 0, c.store.Len(), cc)
+		}
+
+		c.request(t, httptest.NewRequest(http.MethodGet, "/?cc=max-age=60,s-maxage=120", nil))
+		require.Equal(t, 1, c.store.Len())
+		entry, err := c.store.Get(context.Background(), "example.com/?cc=max-age=60,s-maxage=120")
+		require.NoError(t, err)
+		assert.Equal(t, 120*time.Second, entry.FreshUntil.Sub(entry.StoredAt))
+		assert.Equal(t, "max-age=60,s-maxage=120", entry.Header.Get("Cache-Control"))
+		assert.Empty(t, entry.Header.Get("X-Cache"))
+	})
+
+	t.Run("authorization", func(t *testing.T) {
+		c := prepareCacheTest(t, &Options{TTL: time.Minute})
+		authorizedRequest := func(url, credentials string) *http.Request {
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.Header.Set("Authorization", "Bearer "+credentials)
+			return req
+		}
+
+		// Authenticated responses are not stored by default
+		_, body := c.request(t, authorizedRequest("/", "user1"))
+		assert.Equal(t, "call 1", body)
+		assert.Equal(t, 0, c.store.Len())
+		_, body = c.request(t, authorizedRequest("/", "user2"))
+		assert.Equal(t, "call 2", body)
+		_, body = c.request(t, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "call 3", body)
+		assert.Equal(t, 1, c.store.Len())
+
+		// Entries not explicitly shareable are not served to authenticated requests
+		resp, body := c.request(t, authorizedRequest("/", "user1"))
+		assert.Equal(t, "call 4", body)
+		assert.Equal(t, "MISS", resp.Header.Get("X-Cache"))
+		_, body = c.request(t, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "call 3", body)
+
+		for i, cc := range []string{"public", "must-revalidate", "s-maxage=60"} {
+			url := "/?cc=" + cc
+			_, body = c.request(t, authorizedRequest(url, "user1"))
+			assert.Equal(t, "call "+strconv.Itoa(5+i), body, cc)
+			resp, body = c.request(t, authorizedRequest(url, "user2"))
+			assert.Equal(t, "call "+strconv.Itoa(5+i), body, cc)
+			assert.Equal(t, "HIT", resp.Header.Get("X-Cache"), cc)
+		}
+	})
+
+	t.Run("authenticated_user", func(t *testing.T) {
+		c := prepareCacheTest(t, &Options{TTL: time.Minute})
+		// Cookie-based authentication executed after the cache middleware
+		c.router.Get("/user", func(response *goyave.Response, request *goyave.Request) {
+			n := c.calls.Add(1)
+			if cc := request.URL().Query().Get("cc"); cc != "" {
+				response.Header().Set("Cache-Control", cc)
+			}
+			response.String(http.StatusOK, "call "+strconv.FormatInt(n, 10)+" "+request.User.(string))
+		}).SetMeta(MetaCache, &Options{TTL: time.Minute}).Middleware(&cookieAuthMiddleware{})
+		userRequest := func(url, user string) *http.Request {
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.AddCookie(&http.Cookie{Name: "session", Value: user})
+			return req
+		}
+
+		_, body := c.request(t, userRequest("/user", "user1"))
+		assert.Equal(t, "call 1 user1", body)
+		assert.Equal(t, 0, c.store.Len())
+		_, body = c.request(t, userRequest("/user", "user2"))
+		assert.Equal(t, "call 2 user2", body)
+
+		_, body = c.request(t, userRequest("/user?cc=public", "user1"))
+		assert.Equal(t, "call 3 user1", body)
+		assert.Equal(t, 1, c.store.Len())
+	})
+
+	t.Run("not_cacheable_responses", func(t *testing.T) {
+		c := prepareCacheTest(t, &Options{TTL: time.Minute})
+		options := &Options{TTL: time.Minute}
+		c.router.Get("/cookie", func(response *goyave.Response, _ *goyave.Request) {
+			response.Cookie(&http.Cookie{Name: "a", Value: "b"})
+			response.String(http.StatusOK, "cookie")
+		}).SetMeta(MetaCache, options)
+		c.router.Get("/error", func(response *goyave.Response, _ *goyave.Request) {
+			response.String(http.StatusInternalServerError, "error")
+		}).SetMeta(MetaCache, options)
+		c.router.Get("/vary", func(response *goyave.Response, _ *goyave.Request) {
+			response.Header().Set("Vary", "*")
+			response.String(http.StatusOK, "vary")
+		}).SetMeta(MetaCache, options)
+		c.router.Get("/empty", func(response *goyave.Response, _ *goyave.Request) {
+			response.Status(http.StatusNoContent)
+		}).SetMeta(MetaCache, options)
+		c.router.Post("/post", func(response *goyave.Response, _ *goyave.Request) {
+			response.String(http.StatusOK, "post")
+		}).SetMeta(MetaCache, options)
+
+		for _, path := range []string{"/cookie", "/error", "/vary", "/empty"} {
+			c.request(t, httptest.NewRequest(http.MethodGet, path, nil))
+		}
+		c.request(t, httptest.NewRequest(http.MethodPost, "/post", nil))
+		assert.Equal(t, 0, c.store.Len())
+	})
+
+	t.Run("max_body_size", func(t *testing.T) {
+		c := prepareCacheTest(t, &Options{TTL: time.Minute})
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		middleware := New(c.store)
+		middleware.MaxBodySize = 3
+		server.Router().GlobalMiddleware(middleware)
+		server.Router().Get("/", func(response *goyave.Response, _ *goyave.Request) {
+			response.String(http.StatusOK, "too long")
+		}).SetMeta(MetaCache, &Options{TTL: time.Minute})
+		resp := server.TestRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "too long", string(body))
+		assert.Equal(t, 0, c.store.Len())
+	})
+
+	t.Run("vary", func(t *testing.T) {
+		c := prepareCacheTest(t, &Options{TTL: time.Minute})
+		c.router.Get("/vary", func(response *goyave.Response, request *goyave.Request) {
+			c.calls.Add(1)
+			AddTags(request, "lang:"+request.Header().Get("Accept-Language"))
+			response.Header().Set("Vary", "Accept-Language, accept-language")
+			response.String(http.StatusOK, request.Header().Get("Accept-Language"))
+		}).SetMeta(MetaCache, &Options{TTL: time.Minute})
+
+		for _, lang := range []string{"en", "fr", "en", "fr"} {
+			req := httptest.NewRequest(http.MethodGet, "/vary", nil)
+			req.Header.Set("Accept-Language", lang)
+			_, body := c.request(t, req)
+			assert.Equal(t, lang, body)
+		}
+		assert.Equal(t, int64(2), c.calls.Load())
+		assert.Equal(t, 3, c.store.Len()) // Marker + 2 variants
+
+		marker, err := c.store.Get(context.Background(), "example.com/vary")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Accept-Language"}, marker.Vary)
+
+		require.NoError(t, c.store.InvalidateTags(context.Background(), "lang:fr"))
+		assert.Equal(t, 2, c.store.Len())
+	})
+
+	t.Run("stale_while_revalidate", func(t *testing.T) {
+		c := prepareCacheTest(t, &Options{TTL: time.Minute, StaleWhileRevalidate: time.Minute})
+		_, body := c.request(t, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "call 1", body)
+
+		entry, err := c.store.Get(context.Background(), "example.com/")
+		require.NoError(t, err)
+		entry.FreshUntil = time.Now().Add(-time.Second)
+
+		resp, body := c.request(t, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "call 1", body)
+		assert.Equal(t, "STALE", resp.Header.Get("X-Cache"))
+
+		assert.Eventually(t, func() bool {
+			e, err := c.store.Get(context.Background(), "example.com/")
+			return err == nil && e.IsFresh(time.Now()) && string(e.Body) == "call 2"
+		}, time.Second, 10*time.Millisecond)
+
+		resp, body = c.request(t, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "call 2", body)
+		assert.Equal(t, "HIT", resp.Header.Get("X-Cache"))
+
+		// Out of the stale window
+		entry, err = c.store.Get(context.Background(), "example.com/")
+		require.NoError(t, err)
+		entry.FreshUntil = time.Now().Add(-2 * time.Minute)
+		entry.StaleUntil = time.Now().Add(-time.Minute)
+		_, body = c.request(t, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "call 3", body)
+	})
+}
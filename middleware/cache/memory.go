@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultMaxSize the default maximum size of a `MemoryStore`, in bytes (64 MiB).
+const DefaultMaxSize = 64 << 20
+
+type memoryEntry struct {
+	key   string
+	entry *Entry
+	size  int64
+}
+
+// MemoryStore a cache `Store` keeping entries in memory. When the total size of the
+// entries exceeds `MaxSize`, or if the number of entries exceeds `MaxEntries`,
+// the least recently used entries are evicted.
+//
+// Entries are lost when the process stops and are not shared between multiple instances
+// of the application.
+type MemoryStore struct {
+	entries map[string]*list.Element
+	tags    map[string]map[string]struct{}
+	lru     *list.List
+	size    int64
+
+	// MaxSize the maximum total size of the entries, in bytes.
+	// Entries bigger than this limit are never stored.
+	MaxSize int64
+
+	// MaxEntries the maximum number of entries. 0 means no limit.
+	MaxEntries int
+
+	mu sync.Mutex
+}
+
+// NewMemoryStore create a new empty `MemoryStore` with the given
+// maximum size in bytes. If `maxSize <= 0`, `DefaultMaxSize` is used.
+func NewMemoryStore(maxSize int64) *MemoryStore {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	return &MemoryStore{
+		entries: make(map[string]*list.Element),
+		tags:    make(map[string]map[string]struct{}),
+		lru:     list.New(),
+		MaxSize: maxSize,
+	}
+}
+
+// Get the entry identified by the given key and mark it as recently used.
+func (s *MemoryStore) Get(_ context.Context, key string) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	e := elem.Value.(*memoryEntry)
+	if !e.entry.IsUsable(time.Now()) {
+		s.remove(elem)
+		return nil, ErrNotFound
+	}
+	s.lru.MoveToFront(elem)
+	return e.entry, nil
+}
+
+// Set the entry identified by the given key, then evicts the least recently used
+// entries until the store is within its limits.
+func (s *MemoryStore) Set(_ context.Context, key string, entry *Entry) error {
+	size := entry.size() + int64(len(key))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.entries[key]; ok {
+		s.remove(elem)
+	}
+	if size > s.MaxSize {
+		return nil
+	}
+
+	elem := s.lru.PushFront(&memoryEntry{key: key, entry: entry, size: size})
+	s.entries[key] = elem
+	s.size += size
+	for _, tag := range entry.Tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			s.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	for s.size > s.MaxSize || (s.MaxEntries > 0 && s.lru.Len() > s.MaxEntries) {
+		s.remove(s.lru.Back())
+	}
+	return nil
+}
+
+// Delete the entry identified by the given key.
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.entries[key]; ok {
+		s.remove(elem)
+	}
+	return nil
+}
+
+// InvalidateTags deletes all the entries having at least one of the given tags.
+func (s *MemoryStore) InvalidateTags(_ context.Context, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			if elem, ok := s.entries[key]; ok {
+				s.remove(elem)
+			}
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries in the store.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
+
+// Size returns the total size of the entries in the store, in bytes.
+func (s *MemoryStore) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+func (s *MemoryStore) remove(elem *list.Element) {
+	e := elem.Value.(*memoryEntry)
+	s.lru.Remove(elem)
+	delete(s.entries, e.key)
+	s.size -= e.size
+	for _, tag := range e.entry.Tags {
+		if keys, ok := s.tags[tag]; ok {
+			delete(keys, e.key)
+			if len(keys) == 0 {
+				delete(s.tags, tag)
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEntry(body string, tags ...string) *Entry {
+	now := time.Now()
+	return &Entry{
+		StoredAt:   now,
+		FreshUntil: now.Add(time.Minute),
+		StaleUntil: now.Add(time.Minute),
+		Header:     http.Header{"Content-Type": {"text/plain"}},
+		Body:       []byte(body),
+		Tags:       tags,
+		Status:     http.StatusOK,
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("get_set_delete", func(t *testing.T) {
+		store := NewMemoryStore(0)
+		assert.Equal(t, int64(DefaultMaxSize), store.MaxSize)
+
+		_, err := store.Get(ctx, "a")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		entry := newEntry("hello")
+		require.NoError(t, store.Set(ctx, "a", entry))
+		res, err := store.Get(ctx, "a")
+		require.NoError(t, err)
+		assert.Same(t, entry, res)
+		assert.Equal(t, 1, store.Len())
+		assert.Equal(t, entry.size()+1, store.Size())
+
+		// Replace
+		require.NoError(t, store.Set(ctx, "a", newEntry("hi")))
+		assert.Equal(t, 1, store.Len())
+		assert.Equal(t, newEntry("hi").size()+1, store.Size())
+
+		require.NoError(t, store.Delete(ctx, "a"))
+		require.NoError(t, store.Delete(ctx, "a"))
+		_, err = store.Get(ctx, "a")
+		require.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, int64(0), store.Size())
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		store := NewMemoryStore(0)
+		entry := newEntry("hello")
+		entry.FreshUntil = time.Now().Add(-time.Second)
+		entry.StaleUntil = time.Now().Add(time.Minute)
+		require.NoError(t, store.Set(ctx, "stale", entry))
+		_, err := store.Get(ctx, "stale")
+		require.NoError(t, err)
+
+		entry.StaleUntil = time.Now().Add(-time.Second)
+		_, err = store.Get(ctx, "stale")
+		require.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, 0, store.Len())
+	})
+
+	t.Run("lru_size", func(t *testing.T) {
+		entrySize := newEntry(strings.Repeat("a", 100)).size() + 1
+		store := NewMemoryStore(entrySize * 2)
+		require.NoError(t, store.Set(ctx, "a", newEntry(strings.Repeat("a", 100))))
+		require.NoError(t, store.Set(ctx, "b", newEntry(strings.Repeat("b", 100))))
+		_, err := store.Get(ctx, "a") // "a" becomes the most recently used
+		require.NoError(t, err)
+		require.NoError(t, store.Set(ctx, "c", newEntry(strings.Repeat("c", 100))))
+
+		assert.Equal(t, 2, store.Len())
+		_, err = store.Get(ctx, "b")
+		require.ErrorIs(t, err, ErrNotFound)
+		_, err = store.Get(ctx, "a")
+		require.NoError(t, err)
+		_, err = store.Get(ctx, "c")
+		require.NoError(t, err)
+
+		// Too big
+		require.NoError(t, store.Set(ctx, "d", newEntry(strings.Repeat("d", 1000))))
+		_, err = store.Get(ctx, "d")
+		require.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, 2, store.Len())
+	})
+
+	t.Run("lru_entries", func(t *testing.T) {
+		store := NewMemoryStore(0)
+		store.MaxEntries = 2
+		require.NoError(t, store.Set(ctx, "a", newEntry("a")))
+		require.NoError(t, store.Set(ctx, "b", newEntry("b")))
+		require.NoError(t, store.Set(ctx, "c", newEntry("c")))
+		assert.Equal(t, 2, store.Len())
+		_, err := store.Get(ctx, "a")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("invalidate_tags", func(t *testing.T) {
+		store := NewMemoryStore(0)
+		require.NoError(t, store.Set(ctx, "a", newEntry("a", "posts", "post:1")))
+		require.NoError(t, store.Set(ctx, "b", newEntry("b", "posts", "post:2")))
+		require.NoError(t, store.Set(ctx, "c", newEntry("c", "users")))
+
+		require.NoError(t, store.InvalidateTags(ctx, "post:1"))
+		assert.Equal(t, 2, store.Len())
+		require.NoError(t, store.InvalidateTags(ctx, "posts", "unknown"))
+		assert.Equal(t, 1, store.Len())
+		_, err := store.Get(ctx, "c")
+		require.NoError(t, err)
+		assert.Empty(t, store.tags["posts"])
+		assert.Len(t, store.tags, 1)
+	})
+}
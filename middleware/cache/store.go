@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrNotFound returned by `Store.Get` if there is no entry for the given key.
+var ErrNotFound = errors.New("cache entry not found")
+
+// Entry a cached response.
+type Entry struct {
+	// StoredAt the time at which the response was generated.
+	StoredAt time.Time
+
+	// FreshUntil the time after which the entry is stale.
+	FreshUntil time.Time
+
+	// StaleUntil the time until which the stale entry can still be served
+	// while it is being revalidated in the background (stale-while-revalidate).
+	// The entry can be evicted after this time.
+	StaleUntil time.Time
+
+	Header http.Header
+	Body   []byte
+
+	// Tags used to invalidate the entry with `Store.InvalidateTags`.
+	Tags []string
+
+	// Vary if not empty, this entry is not a response but a marker
+	// listing the request headers the responses for this URL depend on.
+	Vary []string
+
+	Status int
+}
+
+// IsFresh returns true if the entry is still fresh at the given time.
+func (e *Entry) IsFresh(now time.Time) bool {
+	return now.Before(e.FreshUntil)
+}
+
+// IsUsable returns true if the entry can still be served at the given time,
+// either because it is fresh or because it is within the stale-while-revalidate window.
+func (e *Entry) IsUsable(now time.Time) bool {
+	return now.Before(e.FreshUntil) || now.Before(e.StaleUntil)
+}
+
+// size an approximation of the memory used by the entry, in bytes.
+func (e *Entry) size() int64 {
+	size := int64(len(e.Body))
+	for k, values := range e.Header {
+		size += int64(len(k))
+		for _, v := range values {
+			size += int64(len(v))
+		}
+	}
+	for _, t := range e.Tags {
+		size += int64(len(t))
+	}
+	for _, v := range e.Vary {
+		size += int64(len(v))
+	}
+	return size
+}
+
+// Store persists cached responses. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get the entry identified by the given key.
+	// Returns `ErrNotFound` if the entry doesn't exist or cannot be used anymore.
+	Get(ctx context.Context, key string) (*Entry, error)
+
+	// Set the entry identified by the given key, replacing any existing entry.
+	Set(ctx context.Context, key string, entry *Entry) error
+
+	// Delete the entry identified by the given key.
+	// Deleting an entry that doesn't exist is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// InvalidateTags deletes all the entries having at least one of the given tags.
+	InvalidateTags(ctx context.Context, tags ...string) error
+}
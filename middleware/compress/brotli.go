@@ -38,3 +38,8 @@ func (w *Brotli) NewWriter(wr io.Writer) io.WriteCloser {
 		LGWin:   w.LGWin,
 	})
 }
+
+// NewReader returns a new `brotli.Reader` reading the given compressed stream.
+func (w *Brotli) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(brotli.NewReader(r)), nil
+}
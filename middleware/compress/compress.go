@@ -26,6 +26,14 @@ type Encoder interface {
 	Encoding() string
 }
 
+// Decompressor is an interface implemented by encoders that can also decompress
+// request bodies. It is used by the parse middleware to read request bodies
+// with a "Content-Encoding" header.
+type Decompressor interface {
+	NewReader(r io.Reader) (io.ReadCloser, error)
+	Encoding() string
+}
+
 type compressWriter struct {
 	goyave.CommonWriter
 	responseWriter http.ResponseWriter
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"goyave.dev/goyave/v5"
@@ -325,3 +326,45 @@ func TestEncoderPriority(t *testing.T) {
 		})
 	}
 }
+
+func TestDecompressors(t *testing.T) {
+	decompressors := []interface {
+		Encoder
+		Decompressor
+	}{
+		&Gzip{Level: gzip.BestSpeed},
+		&Zlib{Level: 5},
+		&Zlib{Level: 5, Dict: []byte("hello")},
+		&Brotli{Quality: 5},
+		&Zstd{},
+		&LZW{},
+	}
+
+	for _, d := range decompressors {
+		t.Run(d.Encoding(), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			writer := d.NewWriter(buf)
+			_, err := writer.Write([]byte("hello world"))
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			reader, err := d.NewReader(buf)
+			require.NoError(t, err)
+			content, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.NoError(t, reader.Close())
+			assert.Equal(t, "hello world", string(content))
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := (&Gzip{}).NewReader(bytes.NewReader([]byte("not gzip")))
+		require.Error(t, err)
+		_, err = (&Zlib{}).NewReader(bytes.NewReader([]byte("not zlib")))
+		require.Error(t, err)
+		_, err = (&Zstd{DecoderOptions: []zstd.DOption{zstd.WithDecoderConcurrency(-1)}}).NewReader(bytes.NewReader([]byte{}))
+		require.Error(t, err)
+		_, err = (&LZW{LitWidth: 9}).NewReader(bytes.NewReader([]byte{}))
+		require.Error(t, err)
+	})
+}
@@ -29,3 +29,12 @@ func (w *Gzip) NewWriter(wr io.Writer) io.WriteCloser {
 	}
 	return writer
 }
+
+// NewReader returns a new `compress/gzip.Reader` reading the given compressed stream.
+func (w *Gzip) NewReader(r io.Reader) (io.ReadCloser, error) {
+	reader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return reader, nil
+}
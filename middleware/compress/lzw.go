@@ -40,3 +40,17 @@ func (w *LZW) NewWriter(wr io.Writer) io.WriteCloser {
 	}
 	return lzw.NewWriter(wr, w.Order, w.LitWidth)
 }
+
+// NewReader returns a new `compress/lzw.Reader` reading the given compressed stream,
+// using the bit ordering and literal width defined in this LZW encoder.
+// Defaults to a LitWidth of 8 if LitWidth was not set.
+func (w *LZW) NewReader(r io.Reader) (io.ReadCloser, error) {
+	litWidth := w.LitWidth
+	if litWidth == 0 {
+		litWidth = 8
+	}
+	if litWidth < 2 || litWidth > 8 {
+		return nil, errors.New("LitWidth must be in range [2, 8]")
+	}
+	return lzw.NewReader(r, w.Order, litWidth), nil
+}
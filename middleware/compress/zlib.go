@@ -31,3 +31,13 @@ func (w *Zlib) NewWriter(wr io.Writer) io.WriteCloser {
 	}
 	return writer
 }
+
+// NewReader returns a new `compress/zlib` reader reading the given compressed stream,
+// using the dictionary defined in this Zlib encoder.
+func (w *Zlib) NewReader(r io.Reader) (io.ReadCloser, error) {
+	reader, err := zlib.NewReaderDict(r, w.Dict)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return reader, nil
+}
@@ -12,6 +12,9 @@ import (
 // Refer to the package documentation for more information
 type Zstd struct {
 	Options []zstd.EOption
+
+	// DecoderOptions the options used when decompressing request bodies.
+	DecoderOptions []zstd.DOption
 }
 
 // Encoding returns "zstd".
@@ -28,3 +31,15 @@ func (w *Zstd) NewWriter(wr io.Writer) io.WriteCloser {
 	}
 	return writer
 }
+
+// NewReader returns a new `zstd.Decoder` reading the given compressed stream, using the
+// zstd.DOptions defined in the Zstd encoder. The decoder doesn't use concurrency by
+// default as request bodies are expected to be relatively small.
+func (w *Zstd) NewReader(r io.Reader) (io.ReadCloser, error) {
+	options := append([]zstd.DOption{zstd.WithDecoderConcurrency(1)}, w.DecoderOptions...)
+	decoder, err := zstd.NewReader(r, options...)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return decoder.IOReadCloser(), nil
+}
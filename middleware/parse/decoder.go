@@ -0,0 +1,337 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+	"gopkg.in/yaml.v3"
+	"goyave.dev/goyave/v5"
+)
+
+// Decoder decodes a raw request body. The result should have the same shape as
+// a JSON body decoded into `any`, that is `map[string]any` for objects and `[]any` for arrays,
+// because this is what the validator expects.
+type Decoder interface {
+	Decode(body []byte) (any, error)
+}
+
+// StreamDecoder a `Decoder` able to decode the body directly from the request body
+// stream, without reading it entirely in memory first.
+type StreamDecoder interface {
+	Decoder
+	DecodeStream(body io.Reader) (any, error)
+}
+
+// maxDepth the maximum nesting depth of the objects and arrays accepted by the XML and
+// MessagePack decoders, so deeply nested bodies cannot exhaust the stack.
+// This is the same limit as `encoding/json`.
+const maxDepth = 10000
+
+var errMaxDepth = errors.New("parse middleware: exceeded max nesting depth")
+
+// DecoderFunc function implementing `Decoder`.
+type DecoderFunc func(body []byte) (any, error)
+
+// Decode calls the function.
+func (f DecoderFunc) Decode(body []byte) (any, error) {
+	return f(body)
+}
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]Decoder{
+		"application/json":        JSONDecoder{},
+		"application/csp-report":  JSONDecoder{},
+		"application/xml":         DecoderFunc(DecodeXML),
+		"text/xml":                DecoderFunc(DecodeXML),
+		"application/msgpack":     DecoderFunc(DecodeMessagePack),
+		"application/x-msgpack":   DecoderFunc(DecodeMessagePack),
+		"application/vnd.msgpack": DecoderFunc(DecodeMessagePack),
+		"application/cbor":        DecoderFunc(DecodeCBOR),
+		"application/yaml":        DecoderFunc(DecodeYAML),
+		"application/x-yaml":      DecoderFunc(DecodeYAML),
+		"text/yaml":               DecoderFunc(DecodeYAML),
+	}
+
+	// suffixes maps structured syntax suffixes (RFC 6839) to
+	// the media type of the decoder used as a fallback.
+	suffixes = map[string]string{
+		"json":    "application/json",
+		"xml":     "application/xml",
+		"cbor":    "application/cbor",
+		"yaml":    "application/yaml",
+		"msgpack": "application/msgpack",
+	}
+)
+
+// RegisterDecoder registers a body decoder for the given media type (e.g. "application/json").
+// Registering a decoder for an existing media type replaces it. A nil decoder removes
+// the media type from the registry.
+//
+// Decoders are global and shared by all parse middleware instances that don't define
+// their own `Middleware.Decoders`. This function should be called at startup, before
+// the server starts.
+func RegisterDecoder(mediaType string, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	mediaType = strings.ToLower(mediaType)
+	if decoder == nil {
+		delete(decoders, mediaType)
+		return
+	}
+	decoders[mediaType] = decoder
+}
+
+// lookupDecoder returns the decoder registered for the given media type. If there is
+// no exact match and the media type has a structured syntax suffix (e.g. "application/ld+json"),
+// the decoder registered for the suffix is returned.
+func lookupDecoder(registry map[string]Decoder, mediaType string) (Decoder, bool) {
+	if registry == nil {
+		decodersMu.RLock()
+		defer decodersMu.RUnlock()
+		registry = decoders
+	}
+	if d, ok := registry[mediaType]; ok {
+		return d, true
+	}
+	if i := strings.LastIndexByte(mediaType, '+'); i != -1 {
+		if fallback, ok := suffixes[mediaType[i+1:]]; ok {
+			d, ok := registry[fallback]
+			return d, ok
+		}
+	}
+	return nil, false
+}
+
+// JSONDecoder `StreamDecoder` for JSON bodies.
+type JSONDecoder struct{}
+
+// Decode a JSON body. See `DecodeJSON`.
+func (JSONDecoder) Decode(body []byte) (any, error) {
+	return DecodeJSON(body)
+}
+
+// DecodeStream a JSON body. See `DecodeJSONStream`.
+func (JSONDecoder) DecodeStream(body io.Reader) (any, error) {
+	return DecodeJSONStream(body)
+}
+
+// DecodeJSON decodes a JSON body. Errors are wrapped with `goyave.ErrInvalidJSONBody`.
+func DecodeJSON(body []byte) (any, error) {
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("%w: %w", goyave.ErrInvalidJSONBody, err)
+	}
+	return data, nil
+}
+
+// DecodeJSONStream decodes a JSON body from a stream. The body must contain a single
+// JSON value. Errors are wrapped with `goyave.ErrInvalidJSONBody`.
+func DecodeJSONStream(body io.Reader) (any, error) {
+	decoder := json.NewDecoder(body)
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("%w: %w", goyave.ErrInvalidJSONBody, err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("invalid data after top-level value")
+		}
+		return nil, fmt.Errorf("%w: %w", goyave.ErrInvalidJSONBody, err)
+	}
+	return data, nil
+}
+
+// DecodeMessagePack decodes a MessagePack body. Map keys are converted to strings.
+// Bodies nested deeper than 10000 levels are rejected.
+func DecodeMessagePack(body []byte) (any, error) {
+	decoder := msgpack.NewDecoder(bytes.NewReader(body))
+	data, err := decodeMessagePackValue(decoder, 0)
+	if err != nil {
+		return nil, err
+	}
+	return normalize(data), nil
+}
+
+// decodeMessagePackValue decodes arrays and maps recursively, keeping track of the depth.
+// Other values are decoded by `msgpack.Decoder.DecodeInterface()`.
+func decodeMessagePackValue(decoder *msgpack.Decoder, depth int) (any, error) {
+	code, err := decoder.PeekCode()
+	if err != nil {
+		return nil, err
+	}
+	isArray := msgpcode.IsFixedArray(code) || code == msgpcode.Array16 || code == msgpcode.Array32
+	isMap := msgpcode.IsFixedMap(code) || code == msgpcode.Map16 || code == msgpcode.Map32
+	if !isArray && !isMap {
+		return decoder.DecodeInterface()
+	}
+	if depth >= maxDepth {
+		return nil, errMaxDepth
+	}
+
+	if isArray {
+		n, err := decoder.DecodeArrayLen()
+		if err != nil {
+			return nil, err
+		}
+		// The capacity is bounded: the length comes from the client and the
+		// elements may not actually be present.
+		array := make([]any, 0, min(n, 1024))
+		for i := 0; i < n; i++ {
+			value, err := decodeMessagePackValue(decoder, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		return array, nil
+	}
+
+	n, err := decoder.DecodeMapLen()
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]any, min(n, 1024))
+	for i := 0; i < n; i++ {
+		key, err := decodeMessagePackValue(decoder, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		value, err := decodeMessagePackValue(decoder, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(normalize(key))] = value
+	}
+	return m, nil
+}
+
+// DecodeCBOR decodes a CBOR body. Map keys are converted to strings.
+func DecodeCBOR(body []byte) (any, error) {
+	var data any
+	if err := cbor.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	return normalize(data), nil
+}
+
+// DecodeYAML decodes a YAML body. Only the first document is decoded.
+// Map keys are converted to strings.
+func DecodeYAML(body []byte) (any, error) {
+	var data any
+	if err := yaml.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, io.EOF
+	}
+	return normalize(data), nil
+}
+
+// DecodeXML decodes an XML body into a generic structure. The root element
+// itself is discarded and its content is returned:
+//   - elements containing only text are converted to a string
+//   - elements with child elements or attributes are converted to `map[string]any`
+//   - attributes are stored with the "@" prefix (e.g. "@id")
+//   - if an element has both attributes or children and text, the text is stored in the "#text" key
+//   - repeated sibling elements with the same name are grouped into a `[]any`
+//   - empty elements are converted to an empty string
+//
+// Bodies nested deeper than 10000 levels are rejected.
+func DecodeXML(body []byte) (any, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			data, err := decodeXMLElement(decoder, start, 0)
+			if err != nil {
+				return nil, err
+			}
+			return data, nil
+		}
+	}
+}
+
+func decodeXMLElement(decoder *xml.Decoder, start xml.StartElement, depth int) (any, error) {
+	if depth >= maxDepth {
+		return nil, errMaxDepth
+	}
+	children := make(map[string]any, len(start.Attr))
+	for _, attr := range start.Attr {
+		children["@"+attr.Name.Local] = attr.Value
+	}
+	text := strings.Builder{}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			value, err := decodeXMLElement(decoder, t, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			name := t.Name.Local
+			switch existing := children[name].(type) {
+			case nil:
+				children[name] = value
+			case []any:
+				children[name] = append(existing, value)
+			default:
+				children[name] = []any{existing, value}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if len(children) == 0 {
+				return s, nil
+			}
+			if s != "" {
+				children["#text"] = s
+			}
+			return children, nil
+		}
+	}
+}
+
+// normalize recursively converts maps with non-string keys to `map[string]any`
+// and byte slices to strings so the decoded data has the same shape as decoded JSON.
+func normalize(data any) any {
+	switch v := data.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = normalize(value)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = normalize(value)
+		}
+		return m
+	case []any:
+		for i, value := range v {
+			v[i] = normalize(value)
+		}
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,234 @@
+package parse
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	"goyave.dev/goyave/v5"
+)
+
+func TestDecoders(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		data, err := DecodeJSON([]byte(`{"a":"b","c":[1,2]}`))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"a": "b", "c": []any{1.0, 2.0}}, data)
+
+		_, err = DecodeJSON([]byte(`{"a"`))
+		assert.ErrorIs(t, err, goyave.ErrInvalidJSONBody)
+	})
+
+	t.Run("XML", func(t *testing.T) {
+		body := `<?xml version="1.0" encoding="UTF-8"?>
+<user id="1">
+	<name>John</name>
+	<email verified="true">johndoe@example.org</email>
+	<tag>a</tag>
+	<tag>b</tag>
+	<tag>c</tag>
+	<address>
+		<city>Paris</city>
+	</address>
+	<empty/>
+</user>`
+		data, err := DecodeXML([]byte(body))
+		require.NoError(t, err)
+		expected := map[string]any{
+			"@id":  "1",
+			"name": "John",
+			"email": map[string]any{
+				"@verified": "true",
+				"#text":     "johndoe@example.org",
+			},
+			"tag": []any{"a", "b", "c"},
+			"address": map[string]any{
+				"city": "Paris",
+			},
+			"empty": "",
+		}
+		assert.Equal(t, expected, data)
+
+		data, err = DecodeXML([]byte(`<value>text</value>`))
+		require.NoError(t, err)
+		assert.Equal(t, "text", data)
+
+		_, err = DecodeXML([]byte(`<user><name>John</name>`))
+		require.Error(t, err)
+
+		_, err = DecodeXML([]byte(``))
+		require.ErrorIs(t, err, io.EOF)
+
+		_, err = DecodeXML([]byte(`<user><name>John</user>`))
+		require.Error(t, err)
+
+		_, err = DecodeXML([]byte(strings.Repeat("<a>", maxDepth) + strings.Repeat("</a>", maxDepth)))
+		require.NoError(t, err)
+		_, err = DecodeXML([]byte(strings.Repeat("<a>", maxDepth+1) + strings.Repeat("</a>", maxDepth+1)))
+		require.ErrorIs(t, err, errMaxDepth)
+		_, err = DecodeXML([]byte("<r>" + strings.Repeat("<a>", 3_000_000)))
+		require.ErrorIs(t, err, errMaxDepth)
+	})
+
+	t.Run("MessagePack", func(t *testing.T) {
+		body, err := msgpack.Marshal(map[string]any{
+			"a": "b",
+			"c": []any{1, 2},
+			"d": map[int]any{1: "e"},
+			"f": []byte("bytes"),
+		})
+		require.NoError(t, err)
+		data, err := DecodeMessagePack(body)
+		require.NoError(t, err)
+		expected := map[string]any{
+			"a": "b",
+			"c": []any{int8(1), int8(2)},
+			"d": map[string]any{"1": "e"},
+			"f": "bytes",
+		}
+		assert.Equal(t, expected, data)
+
+		_, err = DecodeMessagePack([]byte{0xc1})
+		require.Error(t, err)
+
+		// Declared length larger than the actual content
+		_, err = DecodeMessagePack([]byte{0xdd, 0xff, 0xff, 0xff, 0xff})
+		require.Error(t, err)
+
+		nested := append(bytes.Repeat([]byte{0x91}, maxDepth-1), 0x81, 0xa1, 'a', 0x01)
+		data, err = DecodeMessagePack(nested)
+		require.NoError(t, err)
+		for i := 0; i < maxDepth-1; i++ {
+			array, ok := data.([]any)
+			require.True(t, ok)
+			data = array[0]
+		}
+		assert.Equal(t, map[string]any{"a": int8(1)}, data)
+
+		_, err = DecodeMessagePack(append(bytes.Repeat([]byte{0x91}, maxDepth+1), 0x01))
+		require.ErrorIs(t, err, errMaxDepth)
+		_, err = DecodeMessagePack(bytes.Repeat([]byte{0x81}, 9_000_000))
+		require.ErrorIs(t, err, errMaxDepth)
+		_, err = DecodeMessagePack(bytes.Repeat([]byte{0x91}, 9_000_000))
+		require.ErrorIs(t, err, errMaxDepth)
+	})
+
+	t.Run("CBOR", func(t *testing.T) {
+		body, err := cbor.Marshal(map[string]any{
+			"a": "b",
+			"c": []any{1, 2},
+			"d": map[int]any{1: "e"},
+		})
+		require.NoError(t, err)
+		data, err := DecodeCBOR(body)
+		require.NoError(t, err)
+		expected := map[string]any{
+			"a": "b",
+			"c": []any{uint64(1), uint64(2)},
+			"d": map[string]any{"1": "e"},
+		}
+		assert.Equal(t, expected, data)
+
+		_, err = DecodeCBOR([]byte{0xff})
+		require.Error(t, err)
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		body := `
+a: b
+c:
+  - 1
+  - 2
+d:
+  1: e
+`
+		data, err := DecodeYAML([]byte(body))
+		require.NoError(t, err)
+		expected := map[string]any{
+			"a": "b",
+			"c": []any{1, 2},
+			"d": map[string]any{"1": "e"},
+		}
+		assert.Equal(t, expected, data)
+
+		_, err = DecodeYAML([]byte(""))
+		require.ErrorIs(t, err, io.EOF)
+
+		_, err = DecodeYAML([]byte("a: [b"))
+		require.Error(t, err)
+	})
+}
+
+func TestLookupDecoder(t *testing.T) {
+	d, ok := lookupDecoder(nil, "application/json")
+	assert.True(t, ok)
+	assert.NotNil(t, d)
+
+	d, ok = lookupDecoder(nil, "application/ld+json")
+	assert.True(t, ok)
+	assert.NotNil(t, d)
+
+	_, ok = lookupDecoder(nil, "application/octet-stream")
+	assert.False(t, ok)
+
+	_, ok = lookupDecoder(nil, "application/vnd.custom+unknown")
+	assert.False(t, ok)
+
+	custom := map[string]Decoder{"text/plain": DecoderFunc(func(body []byte) (any, error) { return string(body), nil })}
+	d, ok = lookupDecoder(custom, "text/plain")
+	require.True(t, ok)
+	data, err := d.Decode([]byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", data)
+
+	_, ok = lookupDecoder(custom, "application/json")
+	assert.False(t, ok)
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	decoder := DecoderFunc(func(body []byte) (any, error) { return string(body), nil })
+	RegisterDecoder("Text/Plain", decoder)
+	t.Cleanup(func() {
+		RegisterDecoder("text/plain", nil)
+	})
+
+	d, ok := lookupDecoder(nil, "text/plain")
+	require.True(t, ok)
+	data, err := d.Decode([]byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", data)
+
+	RegisterDecoder("text/plain", nil)
+	_, ok = lookupDecoder(nil, "text/plain")
+	assert.False(t, ok)
+}
+
+func TestDecodeJSONStream(t *testing.T) {
+	data, err := DecodeJSONStream(strings.NewReader(`{"a":"b","c":[1,2]}` + "\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": "b", "c": []any{1.0, 2.0}}, data)
+
+	data, err = JSONDecoder{}.DecodeStream(strings.NewReader(`[1]`))
+	require.NoError(t, err)
+	assert.Equal(t, []any{1.0}, data)
+
+	data, err = JSONDecoder{}.Decode([]byte(`"a"`))
+	require.NoError(t, err)
+	assert.Equal(t, "a", data)
+
+	_, err = DecodeJSONStream(strings.NewReader(``))
+	require.ErrorIs(t, err, goyave.ErrInvalidJSONBody)
+
+	_, err = DecodeJSONStream(strings.NewReader(`{"a"`))
+	require.ErrorIs(t, err, goyave.ErrInvalidJSONBody)
+
+	_, err = DecodeJSONStream(strings.NewReader(`{} {}`))
+	require.ErrorIs(t, err, goyave.ErrInvalidJSONBody)
+
+	_, err = DecodeJSONStream(strings.NewReader(`{} }`))
+	require.ErrorIs(t, err, goyave.ErrInvalidJSONBody)
+}
@@ -0,0 +1,178 @@
+package parse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/middleware/compress"
+	"goyave.dev/goyave/v5/util/fsutil"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func compressBody(t *testing.T, body []byte, encoders ...compress.Encoder) *bytes.Buffer {
+	for _, encoder := range encoders {
+		buf := &bytes.Buffer{}
+		writer := encoder.NewWriter(buf)
+		_, err := writer.Write(body)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		body = buf.Bytes()
+	}
+	return bytes.NewBuffer(body)
+}
+
+func TestDecompression(t *testing.T) {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+	json := []byte(`{"a":"b","c":["d","e"]}`)
+	expected := map[string]any{"a": "b", "c": []any{"d", "e"}}
+
+	cases := []struct {
+		contentEncoding string
+		encoders        []compress.Encoder
+	}{
+		{contentEncoding: "gzip", encoders: []compress.Encoder{&compress.Gzip{Level: gzip.BestSpeed}}},
+		{contentEncoding: "br", encoders: []compress.Encoder{&compress.Brotli{Quality: 5}}},
+		{contentEncoding: "zstd", encoders: []compress.Encoder{&compress.Zstd{}}},
+		{contentEncoding: "deflate", encoders: []compress.Encoder{&compress.Zlib{Level: 5}}},
+		{contentEncoding: "GZIP", encoders: []compress.Encoder{&compress.Gzip{Level: gzip.BestSpeed}}},
+		{contentEncoding: "deflate, gzip", encoders: []compress.Encoder{&compress.Zlib{Level: 5}, &compress.Gzip{Level: gzip.BestSpeed}}},
+		{contentEncoding: "identity"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.contentEncoding, func(t *testing.T) {
+			request := testutil.NewTestRequest(http.MethodPost, "/parse", compressBody(t, json, c.encoders...))
+			request.Header().Set("Content-Type", "application/json")
+			request.Header().Set("Content-Encoding", c.contentEncoding)
+			request.Header().Set("Content-Length", "1234")
+
+			result := server.TestMiddleware(&Middleware{}, request, func(resp *goyave.Response, req *goyave.Request) {
+				assert.Equal(t, expected, req.Data)
+				if c.encoders != nil {
+					assert.Empty(t, req.Header().Get("Content-Encoding"))
+				}
+				resp.Status(http.StatusOK)
+			})
+			assert.NoError(t, result.Body.Close())
+			assert.Equal(t, http.StatusOK, result.StatusCode)
+		})
+	}
+
+	t.Run("multipart", func(t *testing.T) {
+		body, contentType := createMultipartBody(t, map[string]string{"name": "John"}, map[string]string{"document": "hello world"})
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", compressBody(t, body.Bytes(), &compress.Gzip{Level: gzip.BestSpeed}))
+		request.Header().Set("Content-Type", contentType)
+		request.Header().Set("Content-Encoding", "gzip")
+
+		result := server.TestMiddleware(&Middleware{}, request, func(resp *goyave.Response, req *goyave.Request) {
+			data := req.Data.(map[string]any)
+			assert.Equal(t, "John", data["name"])
+			assert.Equal(t, int64(11), data["document"].([]fsutil.File)[0].Header.Size)
+			resp.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", bytes.NewReader(json))
+		request.Lang = server.Lang.GetDefault()
+		request.Header().Set("Content-Type", "application/json")
+		request.Header().Set("Content-Encoding", "compress")
+
+		result := server.TestMiddleware(&Middleware{}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusUnsupportedMediaType, result.StatusCode)
+		assert.Equal(t, "gzip, br, zstd, deflate", result.Header.Get("Accept-Encoding"))
+		extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+		require.True(t, ok)
+		assert.ErrorIs(t, extraError, goyave.ErrUnsupportedContentEncoding)
+	})
+
+	t.Run("custom_decompressors", func(t *testing.T) {
+		m := &Middleware{Decompressors: []compress.Decompressor{&compress.LZW{}}}
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", compressBody(t, json, &compress.LZW{}))
+		request.Header().Set("Content-Type", "application/json")
+		request.Header().Set("Content-Encoding", "compress")
+
+		result := server.TestMiddleware(m, request, func(resp *goyave.Response, req *goyave.Request) {
+			assert.Equal(t, expected, req.Data)
+			resp.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+
+		request = testutil.NewTestRequest(http.MethodPost, "/parse", compressBody(t, json, &compress.Gzip{}))
+		request.Lang = server.Lang.GetDefault()
+		request.Header().Set("Content-Type", "application/json")
+		request.Header().Set("Content-Encoding", "gzip")
+		result = server.TestMiddleware(m, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusUnsupportedMediaType, result.StatusCode)
+		assert.Equal(t, "compress", result.Header.Get("Accept-Encoding"))
+	})
+
+	t.Run("invalid_header", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", bytes.NewReader(json))
+		request.Lang = server.Lang.GetDefault()
+		request.Header().Set("Content-Type", "application/json")
+		request.Header().Set("Content-Encoding", "gzip")
+
+		result := server.TestMiddleware(&Middleware{}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+		extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+		require.True(t, ok)
+		assert.ErrorIs(t, extraError, goyave.ErrErrorInRequestBody)
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		body := compressBody(t, json, &compress.Gzip{Level: gzip.BestSpeed}).Bytes()
+		body = body[:len(body)-10]
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", bytes.NewReader(body))
+		request.Lang = server.Lang.GetDefault()
+		request.Header().Set("Content-Type", "application/json")
+		request.Header().Set("Content-Encoding", "gzip")
+
+		result := server.TestMiddleware(&Middleware{}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+		extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+		require.True(t, ok)
+		assert.ErrorIs(t, extraError, goyave.ErrErrorInRequestBody)
+		assert.ErrorIs(t, extraError, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("max_upload_size_decompressed", func(t *testing.T) {
+		// Compresses to a few KiB but exceeds the max upload size once decompressed.
+		large := []byte(`"` + strings.Repeat("a", 2*1024*1024) + `"`)
+		body := compressBody(t, large, &compress.Gzip{Level: gzip.BestCompression})
+		require.Less(t, body.Len(), 1024*1024)
+
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", "application/json")
+		request.Header().Set("Content-Encoding", "gzip")
+
+		result := server.TestMiddleware(&Middleware{MaxUploadSize: 1}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusRequestEntityTooLarge, result.StatusCode)
+	})
+}
@@ -0,0 +1,206 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/google/uuid"
+	"goyave.dev/goyave/v5/slog"
+	"goyave.dev/goyave/v5/util/fsutil"
+
+	errorutil "goyave.dev/goyave/v5/util/errors"
+)
+
+var (
+	errBodyTooLarge  = errors.New("parse middleware: request body too large")
+	errFieldTooLarge = errors.New("parse middleware: multipart field too large")
+	errFileTooLarge  = errors.New("parse middleware: uploaded file too large")
+	errUploadStorage = errors.New("parse middleware: could not store uploaded file")
+)
+
+// limitedReader reads from r but returns the `tooLarge` error if more than
+// `remaining` bytes are available. The first read error of the underlying reader
+// (other than `io.EOF`) is kept in `readErr` so it can be distinguished from parsing errors.
+type limitedReader struct {
+	r         io.Reader
+	tooLarge  error
+	readErr   error
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, l.tooLarge
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), l.tooLarge
+	}
+	if err != nil && err != io.EOF && l.readErr == nil {
+		l.readErr = err
+	}
+	return n, err
+}
+
+// sniffer keeps the first 512 bytes written to it for content type detection.
+type sniffer struct {
+	buf []byte
+}
+
+func (s *sniffer) Write(p []byte) (int, error) {
+	if remaining := 512 - len(s.buf); remaining > 0 {
+		s.buf = append(s.buf, p[:min(remaining, len(p))]...)
+	}
+	return len(p), nil
+}
+
+// uploads streams uploaded files to a file system and keeps track of
+// them so they can be removed at the end of the request.
+type uploads struct {
+	fs    fsutil.WritableFS
+	paths []string
+}
+
+// store streams the given part to a new file with a random name. Returns
+// `errFileTooLarge` if the file exceeds the given limit (in bytes). If the limit
+// is negative, the size of the file is not limited.
+func (u *uploads) store(part *multipart.Part, limit int64) (fsutil.File, error) {
+	path := "goyave-upload-" + uuid.NewString()
+	f, err := u.fs.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fsutil.File{}, fmt.Errorf("%w: %w", errUploadStorage, err)
+	}
+	u.paths = append(u.paths, path)
+
+	var reader io.Reader = part
+	if limit >= 0 {
+		reader = &limitedReader{r: part, remaining: limit, tooLarge: fmt.Errorf("%w: %q", errFileTooLarge, part.FormName())}
+	}
+	sniff := &sniffer{buf: make([]byte, 0, 512)}
+	size, err := io.Copy(io.MultiWriter(f, sniff), reader)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("%w: %w", errUploadStorage, closeErr)
+	}
+	if err != nil {
+		return fsutil.File{}, err
+	}
+
+	// Detect the content type the same way as `fsutil.ParseMultipartFiles`.
+	fileHeader := make([]byte, 512)
+	copy(fileHeader, sniff.buf)
+	return fsutil.File{
+		Header: &multipart.FileHeader{
+			Filename: part.FileName(),
+			Header:   part.Header,
+			Size:     size,
+		},
+		FS:       u.fs,
+		MIMEType: http.DetectContentType(fileHeader),
+		Path:     path,
+	}, nil
+}
+
+// cleanup removes all the stored files if the file system implements `fsutil.RemoveFS`.
+func (u *uploads) cleanup(logger *slog.Logger) {
+	remover, ok := u.fs.(fsutil.RemoveFS)
+	if !ok {
+		return
+	}
+	for _, path := range u.paths {
+		if err := remover.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger.Error(errorutil.New(err))
+		}
+	}
+	u.paths = nil
+}
+
+// parseMultipart reads the given multipart body part by part. Field values are read in memory,
+// files are streamed to the uploads file system. The field and file size limits are in bytes,
+// negative values disable the limit.
+func parseMultipart(body io.Reader, boundary string, files *uploads, maxFieldSize, maxFileSize int64) (url.Values, map[string][]fsutil.File, error) {
+	if boundary == "" {
+		return nil, nil, http.ErrMissingBoundary
+	}
+	reader := multipart.NewReader(body, boundary)
+	values := url.Values{}
+	uploaded := map[string][]fsutil.File{}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+
+		if part.FileName() == "" {
+			var r io.Reader = part
+			if maxFieldSize >= 0 {
+				r = &limitedReader{r: part, remaining: maxFieldSize, tooLarge: fmt.Errorf("%w: %q", errFieldTooLarge, name)}
+			}
+			value, err := io.ReadAll(r)
+			if err != nil {
+				return nil, nil, err
+			}
+			values[name] = append(values[name], string(value))
+			continue
+		}
+
+		file, err := files.store(part, maxFileSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		uploaded[name] = append(uploaded[name], file)
+	}
+	return values, uploaded, nil
+}
+
+// readMultipart reads the given multipart body in memory using `multipart.Reader.ReadForm()`,
+// so the content of the files is held by their `*multipart.FileHeader`. The whole body is kept
+// in memory as it cannot exceed `maxMemory`. The field and file size limits are in bytes,
+// negative values disable the limit.
+func readMultipart(body io.Reader, boundary string, maxMemory, maxFieldSize, maxFileSize int64) (url.Values, map[string][]fsutil.File, error) {
+	if boundary == "" {
+		return nil, nil, http.ErrMissingBoundary
+	}
+	form, err := multipart.NewReader(body, boundary).ReadForm(maxMemory)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name, values := range form.Value {
+		for _, value := range values {
+			if maxFieldSize >= 0 && int64(len(value)) > maxFieldSize {
+				return nil, nil, fmt.Errorf("%w: %q", errFieldTooLarge, name)
+			}
+		}
+	}
+	uploaded := make(map[string][]fsutil.File, len(form.File))
+	for name, headers := range form.File {
+		for _, fh := range headers {
+			if maxFileSize >= 0 && fh.Size > maxFileSize {
+				return nil, nil, fmt.Errorf("%w: %q", errFileTooLarge, name)
+			}
+		}
+		files, err := fsutil.ParseMultipartFiles(headers)
+		if err != nil {
+			return nil, nil, err
+		}
+		uploaded[name] = files
+	}
+	return url.Values(form.Value), uploaded, nil
+}
@@ -0,0 +1,320 @@
+package parse
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/fsutil"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+type errReader struct{}
+
+func (errReader) Read(_ []byte) (int, error) {
+	return 0, fmt.Errorf("read error")
+}
+
+type failingFS struct{}
+
+func (failingFS) OpenFile(_ string, _ int, _ fs.FileMode) (io.ReadWriteCloser, error) {
+	return nil, fmt.Errorf("open failed")
+}
+
+func createMultipartBody(t *testing.T, fields map[string]string, files map[string]string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, content := range files {
+		part, err := writer.CreateFormFile(name, name+".txt")
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func countFiles(t *testing.T, dir string) int {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	return len(entries)
+}
+
+func TestMultipartInMemory(t *testing.T) {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+
+	t.Run("header_open", func(t *testing.T) {
+		body, contentType := createMultipartBody(t, map[string]string{"name": "John"}, map[string]string{"document": "hello world"})
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", contentType)
+
+		result := server.TestMiddleware(&Middleware{}, request, func(resp *goyave.Response, req *goyave.Request) {
+			data := req.Data.(map[string]any)
+			assert.Equal(t, "John", data["name"])
+			files, ok := data["document"].([]fsutil.File)
+			require.True(t, ok)
+			require.Len(t, files, 1)
+			file := files[0]
+			assert.Equal(t, "document.txt", file.Header.Filename)
+			assert.Equal(t, int64(11), file.Header.Size)
+			assert.Nil(t, file.FS)
+			assert.Empty(t, file.Path)
+
+			f, err := file.Header.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(f)
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+			assert.Equal(t, "hello world", string(content))
+			resp.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	})
+
+	t.Run("max_file_size", func(t *testing.T) {
+		body, contentType := createMultipartBody(t, nil, map[string]string{"document": strings.Repeat("a", 2048)})
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", contentType)
+
+		result := server.TestMiddleware(&Middleware{MaxFileSize: 1.0 / 1024}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusRequestEntityTooLarge, result.StatusCode)
+	})
+
+	t.Run("max_upload_size", func(t *testing.T) {
+		body, contentType := createMultipartBody(t, nil, map[string]string{"document": strings.Repeat("a", 20*1024)})
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", contentType)
+
+		result := server.TestMiddleware(&Middleware{MaxUploadSize: 0.01}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusRequestEntityTooLarge, result.StatusCode)
+	})
+}
+
+func TestMultipartStreaming(t *testing.T) {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+
+	t.Run("upload_fs", func(t *testing.T) {
+		dir := t.TempDir()
+		body, contentType := createMultipartBody(t, map[string]string{"name": "John"}, map[string]string{"document": "hello world"})
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", contentType)
+
+		result := server.TestMiddleware(&Middleware{UploadFS: osfs.New(dir), StreamUploads: true}, request, func(resp *goyave.Response, req *goyave.Request) {
+			data := req.Data.(map[string]any)
+			assert.Equal(t, "John", data["name"])
+			files, ok := data["document"].([]fsutil.File)
+			require.True(t, ok)
+			require.Len(t, files, 1)
+			file := files[0]
+			assert.Equal(t, "document.txt", file.Header.Filename)
+			assert.Equal(t, int64(11), file.Header.Size)
+			assert.Equal(t, "application/octet-stream", file.MIMEType)
+			assert.NotNil(t, file.FS)
+			assert.NotEmpty(t, file.Path)
+			assert.Equal(t, 1, countFiles(t, dir))
+
+			f, err := file.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(f)
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+			assert.Equal(t, "hello world", string(content))
+			resp.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+		assert.Equal(t, 0, countFiles(t, dir)) // Cleaned up
+	})
+
+	t.Run("default_fs", func(t *testing.T) {
+		body, contentType := createMultipartBody(t, nil, map[string]string{"document": "hello world"})
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", contentType)
+
+		var path string
+		result := server.TestMiddleware(&Middleware{StreamUploads: true}, request, func(resp *goyave.Response, req *goyave.Request) {
+			files := req.Data.(map[string]any)["document"].([]fsutil.File)
+			path = files[0].Path
+			_, err := os.Stat(os.TempDir() + "/" + path)
+			require.NoError(t, err)
+			resp.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+		_, err := os.Stat(os.TempDir() + "/" + path)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("max_file_size", func(t *testing.T) {
+		dir := t.TempDir()
+		body, contentType := createMultipartBody(t, nil, map[string]string{"document": strings.Repeat("a", 2048)})
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", contentType)
+
+		m := &Middleware{UploadFS: osfs.New(dir), MaxFileSize: 1.0 / 1024, StreamUploads: true}
+		result := server.TestMiddleware(m, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusRequestEntityTooLarge, result.StatusCode)
+		assert.Equal(t, 0, countFiles(t, dir))
+	})
+
+	t.Run("max_field_size", func(t *testing.T) {
+		for _, stream := range []bool{true, false} {
+			body, contentType := createMultipartBody(t, map[string]string{"name": strings.Repeat("a", 2048)}, nil)
+			request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+			request.Header().Set("Content-Type", contentType)
+
+			result := server.TestMiddleware(&Middleware{MaxFieldSize: 1.0 / 1024, StreamUploads: stream}, request, func(_ *goyave.Response, _ *goyave.Request) {
+				assert.Fail(t, "Middleware should not pass")
+			})
+			assert.NoError(t, result.Body.Close())
+			assert.Equal(t, http.StatusRequestEntityTooLarge, result.StatusCode)
+		}
+	})
+
+	t.Run("max_upload_size", func(t *testing.T) {
+		dir := t.TempDir()
+		body, contentType := createMultipartBody(t, nil, map[string]string{"document": strings.Repeat("a", 20*1024)})
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", contentType)
+
+		m := &Middleware{UploadFS: osfs.New(dir), MaxUploadSize: 0.01, StreamUploads: true}
+		result := server.TestMiddleware(m, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusRequestEntityTooLarge, result.StatusCode)
+		assert.Equal(t, 0, countFiles(t, dir))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		dir := t.TempDir()
+		router := server.Router()
+		handlerDone := make(chan struct{})
+		var content string
+		var readErr error
+		router.GlobalMiddleware(&Middleware{UploadFS: osfs.New(dir), StreamUploads: true})
+		router.Post("/timeout", func(_ *goyave.Response, req *goyave.Request) {
+			defer close(handlerDone)
+			<-req.Context().Done()
+			time.Sleep(20 * time.Millisecond) // Let the timeout middleware respond
+			file := req.Data.(map[string]any)["document"].([]fsutil.File)[0]
+			var f io.ReadCloser
+			f, readErr = file.Open()
+			if readErr != nil {
+				return
+			}
+			var raw []byte
+			raw, readErr = io.ReadAll(f)
+			content = string(raw)
+			_ = f.Close()
+		}).Timeout(10 * time.Millisecond)
+
+		body, contentType := createMultipartBody(t, nil, map[string]string{"document": "hello world"})
+		req := httptest.NewRequest(http.MethodPost, "/timeout", body)
+		req.Header.Set("Content-Type", contentType)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+		<-handlerDone
+		require.NoError(t, readErr)
+		assert.Equal(t, "hello world", content)
+		assert.Eventually(t, func() bool {
+			entries, err := os.ReadDir(dir)
+			return err == nil && len(entries) == 0
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		body, contentType := createMultipartBody(t, nil, map[string]string{"document": "hello"})
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", contentType)
+
+		result := server.TestMiddleware(&Middleware{UploadFS: failingFS{}, StreamUploads: true}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	})
+
+	t.Run("missing_boundary", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader("body"))
+		request.Lang = server.Lang.GetDefault()
+		request.Header().Set("Content-Type", "multipart/form-data")
+
+		result := server.TestMiddleware(&Middleware{}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+		extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+		require.True(t, ok)
+		assert.ErrorIs(t, extraError, goyave.ErrInvalidContentForType)
+		assert.ErrorIs(t, extraError, http.ErrMissingBoundary)
+	})
+
+	t.Run("json_trailing_data", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader(`{"a":"b"} {"c":"d"}`))
+		request.Lang = server.Lang.GetDefault()
+		request.Header().Set("Content-Type", "application/json")
+
+		result := server.TestMiddleware(&Middleware{}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+		extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+		require.True(t, ok)
+		assert.ErrorIs(t, extraError, goyave.ErrInvalidJSONBody)
+	})
+}
+
+func TestLimitedReader(t *testing.T) {
+	errTest := fmt.Errorf("too large")
+
+	r := &limitedReader{r: strings.NewReader("hello"), remaining: 5, tooLarge: errTest}
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+
+	r = &limitedReader{r: strings.NewReader("hello world"), remaining: 5, tooLarge: errTest}
+	content, err = io.ReadAll(r)
+	require.ErrorIs(t, err, errTest)
+	assert.Equal(t, "hello", string(content))
+	n, err := r.Read(make([]byte, 10))
+	assert.Equal(t, 0, n)
+	require.ErrorIs(t, err, errTest)
+	assert.NoError(t, r.readErr)
+
+	r = &limitedReader{r: io.MultiReader(strings.NewReader("hel"), errReader{}), remaining: 5, tooLarge: errTest}
+	_, err = io.ReadAll(r)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, errTest)
+	assert.Equal(t, err, r.readErr)
+}
@@ -0,0 +1,285 @@
+package parse
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+const (
+	// DefaultMaxDepth the default maximum nesting depth of keys
+	// when parsing nested form and query keys.
+	DefaultMaxDepth = 10
+
+	// DefaultMaxArrayIndex the default maximum array index
+	// when parsing nested form and query keys.
+	DefaultMaxArrayIndex = 100
+)
+
+type segmentType uint8
+
+const (
+	segmentField segmentType = iota
+	segmentIndex
+	segmentAppend
+)
+
+// segment a single step of a nested key.
+type segment struct {
+	name  string
+	index int
+	typ   segmentType
+}
+
+// arrayNode temporary representation of an array while building the nested
+// structure. Elements are identified by their index, which can be sparse.
+type arrayNode struct {
+	elements map[int]any
+	next     int
+}
+
+func (a *arrayNode) set(index int, value any) {
+	a.elements[index] = value
+	if index >= a.next {
+		a.next = index + 1
+	}
+}
+
+// nestedParser converts flat form or query keys using bracket and dot
+// notation (e.g. "user[address][city]", "user.address.city", "tags[]", "items[0][name]")
+// into nested `map[string]any` and `[]any`.
+type nestedParser struct {
+	maxDepth      int
+	maxArrayIndex int
+}
+
+// parseKey splits the given key into segments. Empty brackets ("[]") are
+// only allowed at the end of the key.
+func (p nestedParser) parseKey(key string) ([]segment, error) {
+	end := strings.IndexAny(key, "[.")
+	if end == -1 {
+		return []segment{{name: key, typ: segmentField}}, nil
+	}
+	if end == 0 {
+		return nil, fmt.Errorf("invalid key %q: missing field name", key)
+	}
+
+	segments := []segment{{name: key[:end], typ: segmentField}}
+	rest := key[end:]
+	for rest != "" {
+		if len(segments)-1 >= p.maxDepth {
+			return nil, fmt.Errorf("invalid key %q: max depth of %d exceeded", key, p.maxDepth)
+		}
+		if segments[len(segments)-1].typ == segmentAppend {
+			return nil, fmt.Errorf("invalid key %q: empty brackets are only allowed at the end of the key", key)
+		}
+
+		var s segment
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, "[.")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid key %q: empty field name", key)
+			}
+			s = segment{name: rest[:end], typ: segmentField}
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid key %q: unclosed bracket", key)
+			}
+			content := rest[1:end]
+			rest = rest[end+1:]
+			switch {
+			case content == "":
+				s = segment{typ: segmentAppend}
+			case isIndex(content):
+				index, err := strconv.Atoi(content)
+				if err != nil || index > p.maxArrayIndex {
+					return nil, fmt.Errorf("invalid key %q: array index exceeds the maximum of %d", key, p.maxArrayIndex)
+				}
+				s = segment{index: index, typ: segmentIndex}
+			default:
+				s = segment{name: content, typ: segmentField}
+			}
+		default:
+			return nil, fmt.Errorf("invalid key %q: unexpected character %q", key, rest[0])
+		}
+		segments = append(segments, s)
+	}
+	return segments, nil
+}
+
+func isIndex(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parse the given flat values and files into a nested structure. Keys are processed in
+// lexical order so the result is deterministic.
+//
+// Leaf values follow the same rules as `flatten`: single values are converted to a string,
+// multiple values are kept as a `[]string`. If the key ends with empty brackets, each value
+// is appended to the array. For files, trailing empty brackets are ignored and the field
+// is always of type `[]fsutil.File`.
+func (p nestedParser) parse(values url.Values, files map[string][]fsutil.File) (map[string]any, error) {
+	root := make(map[string]any, len(values)+len(files))
+
+	for _, key := range sortedKeys(values) {
+		segments, err := p.parseKey(key)
+		if err != nil {
+			return nil, err
+		}
+		vals := values[key]
+		if segments[len(segments)-1].typ == segmentAppend {
+			for _, v := range vals {
+				if err := p.set(root, key, segments, v); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		var value any = vals
+		if len(vals) == 1 {
+			value = vals[0]
+		}
+		if err := p.set(root, key, segments, value); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, key := range sortedKeys(files) {
+		segments, err := p.parseKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if segments[len(segments)-1].typ == segmentAppend {
+			segments = segments[:len(segments)-1]
+		}
+		if err := p.set(root, key, segments, files[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	return finalize(root).(map[string]any), nil
+}
+
+// set the value at the location described by the given segments, creating
+// intermediate objects and arrays if needed.
+func (p nestedParser) set(root map[string]any, key string, segments []segment, value any) error {
+	var container any = root
+	for i, s := range segments {
+		last := i == len(segments)-1
+		var child any
+		if !last {
+			if segments[i+1].typ == segmentField {
+				child = map[string]any{}
+			} else {
+				child = &arrayNode{elements: map[int]any{}}
+			}
+		} else {
+			child = value
+		}
+
+		var existing any
+		var exists bool
+		switch c := container.(type) {
+		case map[string]any:
+			if s.typ != segmentField {
+				return fmt.Errorf("invalid key %q: conflicting types", key)
+			}
+			existing, exists = c[s.name]
+			if !exists || last {
+				if exists && !isLeaf(existing) {
+					return fmt.Errorf("invalid key %q: conflicting types", key)
+				}
+				c[s.name] = child
+			}
+		case *arrayNode:
+			if s.typ == segmentField {
+				return fmt.Errorf("invalid key %q: conflicting types", key)
+			}
+			index := s.index
+			if s.typ == segmentAppend {
+				index = c.next
+				if index > p.maxArrayIndex {
+					return fmt.Errorf("invalid key %q: array index exceeds the maximum of %d", key, p.maxArrayIndex)
+				}
+			}
+			existing, exists = c.elements[index]
+			if !exists || last {
+				if exists && !isLeaf(existing) {
+					return fmt.Errorf("invalid key %q: conflicting types", key)
+				}
+				c.set(index, child)
+			}
+		}
+
+		if last {
+			return nil
+		}
+		if exists {
+			if isLeaf(existing) || (segments[i+1].typ == segmentField) != isObject(existing) {
+				return fmt.Errorf("invalid key %q: conflicting types", key)
+			}
+			child = existing
+		}
+		container = child
+	}
+	return nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := lo.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
+
+func isLeaf(v any) bool {
+	switch v.(type) {
+	case map[string]any, *arrayNode:
+		return false
+	default:
+		return true
+	}
+}
+
+func isObject(v any) bool {
+	_, ok := v.(map[string]any)
+	return ok
+}
+
+// finalize recursively converts array nodes to `[]any`. Sparse arrays are compacted:
+// elements keep their relative order but holes are removed.
+func finalize(v any) any {
+	switch n := v.(type) {
+	case map[string]any:
+		for key, value := range n {
+			n[key] = finalize(value)
+		}
+		return n
+	case *arrayNode:
+		indexes := lo.Keys(n.elements)
+		slices.Sort(indexes)
+		result := make([]any, 0, len(indexes))
+		for _, i := range indexes {
+			result = append(result, finalize(n.elements[i]))
+		}
+		return result
+	default:
+		return v
+	}
+}
@@ -0,0 +1,87 @@
+package parse
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+func TestNestedParser(t *testing.T) {
+	p := nestedParser{maxDepth: 3, maxArrayIndex: 10}
+
+	cases := []struct {
+		expected map[string]any
+		desc     string
+		query    string
+		wantErr  bool
+	}{
+		{desc: "flat", query: "a=b&c=d&e=1&e=2", expected: map[string]any{"a": "b", "c": "d", "e": []string{"1", "2"}}},
+		{desc: "brackets", query: "user[address][city]=Paris&user[name]=John", expected: map[string]any{"user": map[string]any{"address": map[string]any{"city": "Paris"}, "name": "John"}}},
+		{desc: "dots", query: "user.address.city=Paris&user.name=John", expected: map[string]any{"user": map[string]any{"address": map[string]any{"city": "Paris"}, "name": "John"}}},
+		{desc: "mixed", query: "user.address[city]=Paris", expected: map[string]any{"user": map[string]any{"address": map[string]any{"city": "Paris"}}}},
+		{desc: "append", query: "tags[]=a&tags[]=b", expected: map[string]any{"tags": []any{"a", "b"}}},
+		{desc: "append_single", query: "tags[]=a", expected: map[string]any{"tags": []any{"a"}}},
+		{desc: "indexes", query: "items[1][name]=b&items[0][name]=a&items[0][qty]=2", expected: map[string]any{"items": []any{map[string]any{"name": "a", "qty": "2"}, map[string]any{"name": "b"}}}},
+		{desc: "sparse", query: "a[5]=c&a[2]=b", expected: map[string]any{"a": []any{"b", "c"}}},
+		{desc: "nested_arrays", query: "a[0][1]=b&a[0][0]=a", expected: map[string]any{"a": []any{[]any{"a", "b"}}}},
+		{desc: "nested_append", query: "a[0][]=a&a[0][]=b", expected: map[string]any{"a": []any{[]any{"a", "b"}}}},
+		{desc: "filter", query: "filter[and][0][field]=name&filter[and][0][value]=John", expected: map[string]any{"filter": map[string]any{"and": []any{map[string]any{"field": "name", "value": "John"}}}}},
+		{desc: "literal_closing_bracket", query: "a]=b", expected: map[string]any{"a]": "b"}},
+		{desc: "max_depth", query: "a[b][c][d]=e", expected: map[string]any{"a": map[string]any{"b": map[string]any{"c": map[string]any{"d": "e"}}}}},
+		{desc: "max_depth_exceeded", query: "a[b][c][d][e]=f", wantErr: true},
+		{desc: "max_index_exceeded", query: "a[11]=b", wantErr: true},
+		{desc: "max_index_exceeded_append", query: "a[10]=b&a[]=c", wantErr: true},
+		{desc: "huge_index", query: "a[99999999999999999999999]=b", wantErr: true},
+		{desc: "append_not_last", query: "a[][b]=c", wantErr: true},
+		{desc: "unclosed_bracket", query: "a[b=c", wantErr: true},
+		{desc: "missing_name", query: "[a]=b", wantErr: true},
+		{desc: "empty_dot", query: "a..b=c", wantErr: true},
+		{desc: "unexpected_char", query: "a[b]c=d", wantErr: true},
+		{desc: "conflict_leaf_object", query: "a=1&a[b]=2", wantErr: true},
+		{desc: "conflict_object_leaf", query: "a.b=1&a=2", wantErr: true},
+		{desc: "conflict_object_array", query: "a[b]=1&a[0]=2", wantErr: true},
+		{desc: "conflict_array_object", query: "a[0]=1&a[0][b]=2", wantErr: true},
+		{desc: "conflict_array_field", query: "a[0][b]=1&a[0][0]=2", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			values, err := url.ParseQuery(c.query)
+			require.NoError(t, err)
+			result, err := p.parse(values, nil)
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, result)
+		})
+	}
+
+	t.Run("files", func(t *testing.T) {
+		files := []fsutil.File{{MIMEType: "image/png"}}
+		values := url.Values{"user[name]": {"John"}}
+		result, err := p.parse(values, map[string][]fsutil.File{
+			"user[avatar]": files,
+			"photos[]":     files,
+		})
+		require.NoError(t, err)
+		expected := map[string]any{
+			"user": map[string]any{
+				"name":   "John",
+				"avatar": files,
+			},
+			"photos": files,
+		}
+		assert.Equal(t, expected, result)
+
+		_, err = p.parse(url.Values{"user": {"John"}}, map[string][]fsutil.File{"user[avatar]": files})
+		require.Error(t, err)
+
+		_, err = p.parse(nil, map[string][]fsutil.File{"[avatar]": files})
+		require.Error(t, err)
+	})
+}
@@ -1,16 +1,20 @@
 package parse
 
 import (
-	"bytes"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 
+	"github.com/samber/lo"
 	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/middleware/compress"
 	"goyave.dev/goyave/v5/util/fsutil"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
 )
 
 // Middleware reading the raw request query and body.
@@ -21,23 +25,92 @@ import (
 //
 // The body is read only if the "Content-Type" header is set. If
 // the body exceeds the configured max upload size (in MiB), "413 Request Entity Too Large"
-// is returned.
-// If the content type is "application/json", the middleware will attempt
-// to unmarshal the body and put the result in the request's `Data`. If it fails, returns "400 Bad request".
-// If the content-type has another value, Go's standard `ParseMultipartForm` is called. The result
-// is put inside the request's `Data` after being flattened.
-// If the form is not a multipart form, attempts `ParseForm`. If `ParseMultipartForm` or `ParseForm` return
-// an error, returns "400 Bad request".
+// is returned. The body is parsed while it is read so it is never entirely
+// loaded in memory, except for decoders that don't implement `StreamDecoder`.
+//
+// If the content type is "multipart/form-data", the body is read in memory, like any other body.
+// If `StreamUploads` is enabled, the body is read part by part instead: field values are kept in memory
+// and files are streamed to temporary files in `UploadFS`. Streamed files are removed at the end of
+// the request (or once the handler returns if it is still running after a timeout): if you want to keep
+// them, use `fsutil.File.Save()`. If a field or a file exceeds `MaxFieldSize` or `MaxFileSize`,
+// "413 Request Entity Too Large" is returned.
+// If the content type is "application/x-www-form-urlencoded", the body is parsed using Go's
+// standard `url.ParseQuery()`. In both cases, the result is put inside the request's `Data` after being flattened.
+// If the parsing fails, returns "400 Bad request".
+//
+// If the body has a "Content-Encoding" header, it is decompressed while it is read using the
+// matching `Decompressors`. The max upload size applies to the decompressed body. If an encoding
+// is not supported, returns "415 Unsupported Media Type" with the "Accept-Encoding" header
+// listing the supported encodings. If the body cannot be decompressed, returns "400 Bad Request".
+//
+// For any other content type, the body is decoded using the `Decoder` registered
+// for its media type. Decoders for JSON, XML, MessagePack, CBOR and YAML are registered
+// by default. Media types with a structured syntax suffix (e.g. "application/ld+json") fall back
+// to the decoder of their suffix. If the decoding fails, returns "400 Bad request". If there is
+// no decoder for the media type, returns "415 Unsupported Media Type".
+// In both cases, the error is stored in the request's extra `goyave.ExtraParseError`.
 //
 // In `multipart/form-data`, all file parts are automatically converted to `[]fsutil.File`.
 // Inside `request.Data`, a field of type "file" will therefore always be of type `[]fsutil.File`.
 // It is a slice so it support multi-file uploads in a single field.
+//
+// If `NestedKeys` is enabled, query and form keys using bracket or dot notation are converted
+// to nested objects and arrays, so the same `validation.RuleSet` can be used for JSON bodies,
+// forms and query strings:
+//
+//	user[address][city]=Paris -> {"user": {"address": {"city": "Paris"}}}
+//	user.address.city=Paris   -> {"user": {"address": {"city": "Paris"}}}
+//	tags[]=a&tags[]=b         -> {"tags": ["a", "b"]}
+//	items[0][name]=a          -> {"items": [{"name": "a"}]}
+//
+// Empty brackets are only allowed at the end of a key. Sparse array indexes are compacted.
+// Keys exceeding `MaxDepth` or `MaxArrayIndex`, malformed keys and keys conflicting with each other
+// (e.g. "a=1&a[b]=2") result in "400 Bad Request".
 type Middleware struct {
 	goyave.Component
 
+	// Decoders the body decoders used by this middleware, identified by media type (lowercase,
+	// without parameters). If nil, the global decoders registered with `RegisterDecoder()` are used.
+	Decoders map[string]Decoder
+
+	// Decompressors the algorithms used to decompress request bodies depending on their
+	// "Content-Encoding" header. Defaults to gzip, brotli, zstd and deflate.
+	Decompressors []compress.Decompressor
+
+	// UploadFS the file system uploaded files are streamed to if `StreamUploads` is enabled. Files are created at the root
+	// of the file system with a random name. They are removed at the end of the request
+	// only if the file system implements `fsutil.RemoveFS`.
+	// Defaults to the OS temporary directory.
+	UploadFS fsutil.WritableFS
+
 	// MaxUpoadSize the maximum size of the request (in MiB).
 	// Defaults to the value provided in the config "server.maxUploadSize".
 	MaxUploadSize float64
+
+	// MaxFileSize the maximum size of a single uploaded file (in MiB).
+	// If 0, only `MaxUploadSize` applies.
+	MaxFileSize float64
+
+	// MaxFieldSize the maximum size of a single non-file multipart field value (in MiB).
+	// If 0, only `MaxUploadSize` applies.
+	MaxFieldSize float64
+
+	// MaxDepth the maximum nesting depth of query and form keys when `NestedKeys` is enabled.
+	// Defaults to `DefaultMaxDepth`.
+	MaxDepth int
+
+	// MaxArrayIndex the maximum array index of query and form keys when `NestedKeys` is enabled.
+	// Defaults to `DefaultMaxArrayIndex`.
+	MaxArrayIndex int
+
+	// NestedKeys if true, query and form keys using bracket or dot notation
+	// are converted to nested objects and arrays.
+	NestedKeys bool
+
+	// StreamUploads if true, multipart files are streamed to `UploadFS` instead of being
+	// read in memory. The content of streamed files is not held by their `Header`:
+	// `fsutil.File.Header.Open()` cannot be used, use `fsutil.File.Open()` instead.
+	StreamUploads bool
 }
 
 // Handle reads the request query and body and parses it if necessary.
@@ -45,7 +118,7 @@ type Middleware struct {
 // middleware immediately passes after parsing the query.
 func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
 	return func(response *goyave.Response, r *goyave.Request) {
-		if err := parseQuery(r); err != nil {
+		if err := m.parseQuery(r); err != nil {
 			response.Status(http.StatusBadRequest)
 			r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", goyave.ErrInvalidQuery, err)
 			return
@@ -58,100 +131,261 @@ func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
 
 		r.Data = nil
 		contentType := r.Header().Get("Content-Type")
-		if contentType != "" {
-			maxSize := int64(m.getMaxUploadSize() * 1024 * 1024)
-			maxValueBytes := maxSize
-			var bodyBuf bytes.Buffer
-			n, err := io.CopyN(&bodyBuf, r.Body(), maxValueBytes+1)
-			if err == nil || err == io.EOF {
-				maxValueBytes -= n
-				if maxValueBytes < 0 {
-					response.Status(http.StatusRequestEntityTooLarge)
-					return
-				}
+		if contentType == "" {
+			next(response, r)
+			return
+		}
 
-				bodyBytes := bodyBuf.Bytes()
-				if strings.HasPrefix(contentType, "application/json") {
-					var body any
-					if err := json.Unmarshal(bodyBytes, &body); err != nil {
-						response.Status(http.StatusBadRequest)
-						r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", goyave.ErrInvalidJSONBody, err)
-					}
-					r.Data = body
-				} else {
-					req := r.Request()
-					req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-					r.Data, err = generateFlatMap(req, maxSize)
-					if err != nil {
-						response.Status(http.StatusBadRequest)
-						r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", goyave.ErrInvalidContentForType, err)
-					}
-				}
+		mediaType, params, _ := mime.ParseMediaType(contentType)
+		if mediaType == "" {
+			mediaType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+		}
+
+		reader, closeReaders, err := m.decompress(r)
+		if err != nil {
+			r.Extra[goyave.ExtraParseError{}] = err
+			if errors.Is(err, goyave.ErrUnsupportedContentEncoding) {
+				response.Header().Set("Accept-Encoding", strings.Join(lo.Map(m.getDecompressors(), func(d compress.Decompressor, _ int) string {
+					return d.Encoding()
+				}), ", "))
+				response.Status(http.StatusUnsupportedMediaType)
+				return
+			}
+			response.Status(http.StatusBadRequest)
+			return
+		}
+		defer closeReaders()
+
+		body := &limitedReader{
+			r:         reader,
+			remaining: int64(m.getMaxUploadSize() * 1024 * 1024),
+			tooLarge:  errBodyTooLarge,
+		}
+
+		switch mediaType {
+		case "multipart/form-data":
+			var values url.Values
+			var uploaded map[string][]fsutil.File
+			if m.StreamUploads {
+				files := &uploads{fs: m.getUploadFS()}
+				defer afterHandler(r, func() { files.cleanup(m.Logger()) })
+				values, uploaded, err = parseMultipart(body, params["boundary"], files, megabytes(m.MaxFieldSize), megabytes(m.MaxFileSize))
 			} else {
-				response.Status(http.StatusBadRequest)
-				r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", goyave.ErrErrorInRequestBody, err)
+				values, uploaded, err = readMultipart(body, params["boundary"], body.remaining, megabytes(m.MaxFieldSize), megabytes(m.MaxFileSize))
+			}
+			if err == nil {
+				r.Data, err = m.buildForm(values, uploaded)
 			}
+			if err != nil {
+				err = fmt.Errorf("%w: %w", goyave.ErrInvalidContentForType, err)
+			}
+		case "application/x-www-form-urlencoded":
+			var raw []byte
+			raw, err = io.ReadAll(body)
+			if err == nil {
+				var values url.Values
+				values, err = url.ParseQuery(string(raw))
+				if err == nil {
+					r.Data, err = m.buildForm(values, nil)
+				}
+			}
+			if err != nil {
+				err = fmt.Errorf("%w: %w", goyave.ErrInvalidContentForType, err)
+			}
+		default:
+			decoder, ok := lookupDecoder(m.Decoders, mediaType)
+			if !ok {
+				response.Status(http.StatusUnsupportedMediaType)
+				r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %q", goyave.ErrUnsupportedContentType, mediaType)
+				return
+			}
+			r.Data, err = decode(decoder, body)
 		}
 
-		if response.GetStatus() != http.StatusBadRequest {
-			next(response, r)
+		if err != nil {
+			r.Data = nil
+			switch {
+			case errors.Is(err, errUploadStorage):
+				response.Error(err)
+			case errors.Is(err, errBodyTooLarge), errors.Is(err, errFileTooLarge), errors.Is(err, errFieldTooLarge):
+				response.Status(http.StatusRequestEntityTooLarge)
+			case body.readErr != nil:
+				response.Status(http.StatusBadRequest)
+				r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", goyave.ErrErrorInRequestBody, body.readErr)
+			default:
+				response.Status(http.StatusBadRequest)
+				r.Extra[goyave.ExtraParseError{}] = err
+			}
+			return
 		}
+
+		next(response, r)
 	}
 }
 
-func (m *Middleware) getMaxUploadSize() float64 {
-	if m.MaxUploadSize == 0 {
-		return m.Config().GetFloat("server.maxUploadSize")
+// afterHandler executes the given function once the handler has returned. If the handler
+// is still running after a timeout, the function is executed in the background when
+// it returns (see `goyave.ExtraHandlerDone`).
+func afterHandler(r *goyave.Request, f func()) {
+	if done, ok := r.Extra[goyave.ExtraHandlerDone{}].(<-chan struct{}); ok {
+		go func() {
+			<-done
+			f()
+		}()
+		return
 	}
-
-	return m.MaxUploadSize
+	f()
 }
 
-func parseQuery(request *goyave.Request) error {
-	queryParams, err := url.ParseQuery(request.URL().RawQuery)
-	if err == nil {
-		request.Query = make(map[string]any, len(queryParams))
-		flatten(request.Query, queryParams)
+// decode the body using the given decoder. If the decoder doesn't implement `StreamDecoder`,
+// the body is read entirely first. Errors not wrapping `goyave.ErrInvalidJSONBody` or
+// `goyave.ErrInvalidBody` are wrapped with `goyave.ErrInvalidBody`.
+func decode(decoder Decoder, body io.Reader) (any, error) {
+	var data any
+	var err error
+	if streamDecoder, ok := decoder.(StreamDecoder); ok {
+		data, err = streamDecoder.DecodeStream(body)
+	} else {
+		var raw []byte
+		raw, err = io.ReadAll(body)
+		if err == nil {
+			data, err = decoder.Decode(raw)
+		}
 	}
-	return err
+	if err != nil && !errors.Is(err, goyave.ErrInvalidJSONBody) && !errors.Is(err, goyave.ErrInvalidBody) {
+		err = fmt.Errorf("%w: %w", goyave.ErrInvalidBody, err)
+	}
+	return data, err
 }
 
-func generateFlatMap(request *http.Request, maxSize int64) (map[string]any, error) {
-	flatMap := make(map[string]any)
-	request.Form = url.Values{} // Prevent Form from being parsed because it would be redundant with our parsing
-	err := request.ParseMultipartForm(maxSize)
+// buildForm converts the given form values and files to the request data, either
+// flattened or nested depending on `NestedKeys`.
+func (m *Middleware) buildForm(values url.Values, files map[string][]fsutil.File) (map[string]any, error) {
+	if p := m.nestedParser(); p != nil {
+		return p.parse(values, files)
+	}
+	data := make(map[string]any, len(values)+len(files))
+	flatten(data, values)
+	for field, f := range files {
+		data[field] = f
+	}
+	return data, nil
+}
 
-	if err != nil {
-		if err == http.ErrNotMultipart {
-			if err := request.ParseForm(); err != nil {
-				return nil, err
+// decompress returns a reader decompressing the request body according to its "Content-Encoding"
+// header. If multiple encodings are listed, they are removed in the reverse order. The returned function
+// closes the decompression readers. The "Content-Encoding" and "Content-Length" headers are removed
+// from the request as they don't describe the body anymore.
+func (m *Middleware) decompress(r *goyave.Request) (io.Reader, func(), error) {
+	encodings := make([]string, 0, 1)
+	for _, header := range r.Header().Values("Content-Encoding") {
+		for _, encoding := range strings.Split(header, ",") {
+			encoding = strings.ToLower(strings.TrimSpace(encoding))
+			if encoding != "" && encoding != "identity" {
+				encodings = append(encodings, encoding)
 			}
-		} else {
-			return nil, err
 		}
 	}
 
-	if request.PostForm != nil {
-		flatten(flatMap, request.PostForm)
+	var reader io.Reader = r.Body()
+	if len(encodings) == 0 {
+		return reader, func() {}, nil
 	}
-	if request.MultipartForm != nil {
-		flatten(flatMap, request.MultipartForm.Value)
 
-		for field, headers := range request.MultipartForm.File {
-			files, err := fsutil.ParseMultipartFiles(headers)
-			if err != nil {
-				return nil, err
-			}
-			flatMap[field] = files
+	closers := make([]io.Closer, 0, len(encodings))
+	closeReaders := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			_ = closers[i].Close()
+		}
+	}
+	decompressors := m.getDecompressors()
+	for i := len(encodings) - 1; i >= 0; i-- {
+		decompressor, ok := lo.Find(decompressors, func(d compress.Decompressor) bool {
+			return d.Encoding() == encodings[i]
+		})
+		if !ok {
+			closeReaders()
+			return nil, nil, fmt.Errorf("%w: %q", goyave.ErrUnsupportedContentEncoding, encodings[i])
+		}
+		rc, err := decompressor.NewReader(reader)
+		if err != nil {
+			closeReaders()
+			return nil, nil, fmt.Errorf("%w: %w", goyave.ErrErrorInRequestBody, err)
 		}
+		closers = append(closers, rc)
+		reader = rc
+	}
+
+	r.Header().Del("Content-Encoding")
+	r.Header().Del("Content-Length")
+	r.Request().ContentLength = -1
+	return reader, closeReaders, nil
+}
+
+func (m *Middleware) getDecompressors() []compress.Decompressor {
+	if m.Decompressors == nil {
+		return []compress.Decompressor{&compress.Gzip{}, &compress.Brotli{}, &compress.Zstd{}, &compress.Zlib{}}
+	}
+	return m.Decompressors
+}
+
+func (m *Middleware) getUploadFS() fsutil.WritableFS {
+	if m.UploadFS == nil {
+		return osfs.New(os.TempDir())
+	}
+	return m.UploadFS
+}
+
+// megabytes converts the given size in MiB to bytes. Returns -1 if the size is 0 or less.
+func megabytes(size float64) int64 {
+	if size <= 0 {
+		return -1
+	}
+	return int64(size * 1024 * 1024)
+}
+
+func (m *Middleware) getMaxUploadSize() float64 {
+	if m.MaxUploadSize == 0 {
+		return m.Config().GetFloat("server.maxUploadSize")
 	}
 
-	// Source form is not needed anymore, clear it.
-	request.Form = nil
-	request.PostForm = nil
-	request.MultipartForm = nil
+	return m.MaxUploadSize
+}
 
-	return flatMap, nil
+// nestedParser returns the parser used to convert nested keys, or nil if `NestedKeys` is disabled.
+func (m *Middleware) nestedParser() *nestedParser {
+	if !m.NestedKeys {
+		return nil
+	}
+	p := &nestedParser{
+		maxDepth:      m.MaxDepth,
+		maxArrayIndex: m.MaxArrayIndex,
+	}
+	if p.maxDepth <= 0 {
+		p.maxDepth = DefaultMaxDepth
+	}
+	if p.maxArrayIndex <= 0 {
+		p.maxArrayIndex = DefaultMaxArrayIndex
+	}
+	return p
+}
+
+func (m *Middleware) parseQuery(request *goyave.Request) error {
+	queryParams, err := url.ParseQuery(request.URL().RawQuery)
+	if err != nil {
+		return err
+	}
+	if p := m.nestedParser(); p != nil {
+		query, err := p.parse(queryParams, nil)
+		if err != nil {
+			return err
+		}
+		request.Query = query
+		return nil
+	}
+	request.Query = make(map[string]any, len(queryParams))
+	flatten(request.Query, queryParams)
+	return nil
 }
 
 func flatten(dst map[string]any, values url.Values) {
@@ -2,6 +2,7 @@ package parse
 
 import (
 	"bytes"
+	"fmt"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -66,8 +67,8 @@ func TestParseMiddleware(t *testing.T) {
 	})
 
 	t.Run("Entity Too Large", func(t *testing.T) {
-		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader(strings.Repeat("a", 1024*1024)))
-		request.Header().Set("Content-Type", "application/octet-stream")
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader(`"`+strings.Repeat("a", 1024*1024)+`"`))
+		request.Header().Set("Content-Type", "application/json")
 
 		result := server.TestMiddleware(&Middleware{MaxUploadSize: 0.01}, request, func(_ *goyave.Response, _ *goyave.Request) {
 			assert.Fail(t, "Middleware should not pass")
@@ -230,7 +231,7 @@ func TestParseMiddleware(t *testing.T) {
 		// Use the response body from our test server as the request body for our middleware
 		request := testutil.NewTestRequest(http.MethodPost, "/parse", resp.Body)
 		request.Lang = server.Lang.GetDefault()
-		request.Header().Set("Content-Type", "multipart/form-data")
+		request.Header().Set("Content-Type", "multipart/form-data; boundary=boundary")
 
 		result := server.TestMiddleware(&Middleware{}, request, func(resp *goyave.Response, _ *goyave.Request) {
 			resp.Status(http.StatusBadRequest)
@@ -269,6 +270,248 @@ func TestParseMiddleware(t *testing.T) {
 		assert.Equal(t, http.StatusOK, result.StatusCode)
 	})
 
+	t.Run("Decoders", func(t *testing.T) {
+		cases := []struct {
+			contentType string
+			body        string
+		}{
+			{contentType: "application/xml; charset=utf-8", body: `<user><a>b</a><h>i</h><h>j</h></user>`},
+			{contentType: "application/yaml", body: "a: b\nh: [i, j]\n"},
+			{contentType: "application/vnd.api+json", body: `{"a":"b","h":["i","j"]}`},
+		}
+
+		for _, c := range cases {
+			t.Run(c.contentType, func(t *testing.T) {
+				request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader(c.body))
+				request.Header().Set("Content-Type", c.contentType)
+
+				result := server.TestMiddleware(&Middleware{}, request, func(resp *goyave.Response, req *goyave.Request) {
+					expected := map[string]any{
+						"a": "b",
+						"h": []any{"i", "j"},
+					}
+					assert.Equal(t, expected, req.Data)
+					resp.Status(http.StatusOK)
+				})
+
+				assert.NoError(t, result.Body.Close())
+				assert.Equal(t, http.StatusOK, result.StatusCode)
+			})
+		}
+	})
+
+	t.Run("Decoder Error", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader("<user><a>b</user>"))
+		request.Lang = server.Lang.GetDefault()
+		request.Header().Set("Content-Type", "application/xml")
+
+		result := server.TestMiddleware(&Middleware{}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+		extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+		require.True(t, ok)
+		assert.ErrorIs(t, extraError, goyave.ErrInvalidBody)
+	})
+
+	t.Run("Decoder Max Depth", func(t *testing.T) {
+		cases := []struct {
+			contentType string
+			body        []byte
+		}{
+			{contentType: "application/xml", body: []byte("<r>" + strings.Repeat("<a>", 3_000_000))},
+			{contentType: "application/msgpack", body: bytes.Repeat([]byte{0x91}, 9_000_000)},
+		}
+		for _, c := range cases {
+			t.Run(c.contentType, func(t *testing.T) {
+				request := testutil.NewTestRequest(http.MethodPost, "/parse", bytes.NewReader(c.body))
+				request.Lang = server.Lang.GetDefault()
+				request.Header().Set("Content-Type", c.contentType)
+
+				result := server.TestMiddleware(&Middleware{}, request, func(_ *goyave.Response, _ *goyave.Request) {
+					assert.Fail(t, "Middleware should not pass")
+				})
+
+				assert.NoError(t, result.Body.Close())
+				assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+				extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+				require.True(t, ok)
+				assert.ErrorIs(t, extraError, goyave.ErrInvalidBody)
+				assert.ErrorIs(t, extraError, errMaxDepth)
+			})
+		}
+	})
+
+	t.Run("Unsupported Media Type", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader("hello"))
+		request.Lang = server.Lang.GetDefault()
+		request.Header().Set("Content-Type", "text/plain")
+
+		result := server.TestMiddleware(&Middleware{}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusUnsupportedMediaType, result.StatusCode)
+		extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+		require.True(t, ok)
+		assert.ErrorIs(t, extraError, goyave.ErrUnsupportedContentType)
+	})
+
+	t.Run("Custom Decoders", func(t *testing.T) {
+		m := &Middleware{
+			Decoders: map[string]Decoder{
+				"text/plain": DecoderFunc(func(body []byte) (any, error) {
+					return map[string]any{"text": string(body)}, nil
+				}),
+			},
+		}
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader("hello"))
+		request.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+		result := server.TestMiddleware(m, request, func(resp *goyave.Response, req *goyave.Request) {
+			assert.Equal(t, map[string]any{"text": "hello"}, req.Data)
+			resp.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+
+		// Global decoders are not used
+		request = testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader("{}"))
+		request.Lang = server.Lang.GetDefault()
+		request.Header().Set("Content-Type", "application/json")
+		result = server.TestMiddleware(m, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusUnsupportedMediaType, result.StatusCode)
+	})
+
+	t.Run("Custom Decoder Error", func(t *testing.T) {
+		m := &Middleware{
+			Decoders: map[string]Decoder{
+				"text/plain": DecoderFunc(func(_ []byte) (any, error) {
+					return nil, fmt.Errorf("custom error")
+				}),
+			},
+		}
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader("hello"))
+		request.Lang = server.Lang.GetDefault()
+		request.Header().Set("Content-Type", "text/plain")
+
+		result := server.TestMiddleware(m, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+		extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+		require.True(t, ok)
+		assert.ErrorIs(t, extraError, goyave.ErrInvalidBody)
+		assert.Contains(t, extraError.Error(), "custom error")
+	})
+
+	t.Run("Nested Query", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodGet, "/parse?filter[name]=John&filter[age][]=18&filter[age][]=25&sort.field=name", nil)
+
+		result := server.TestMiddleware(&Middleware{NestedKeys: true}, request, func(resp *goyave.Response, req *goyave.Request) {
+			expected := map[string]any{
+				"filter": map[string]any{
+					"name": "John",
+					"age":  []any{"18", "25"},
+				},
+				"sort": map[string]any{
+					"field": "name",
+				},
+			}
+			assert.Equal(t, expected, req.Query)
+			resp.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	})
+
+	t.Run("Nested Query Error", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodGet, "/parse?a[b][c]=d", nil)
+		request.Lang = server.Lang.GetDefault()
+
+		result := server.TestMiddleware(&Middleware{NestedKeys: true, MaxDepth: 1}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+		extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+		require.True(t, ok)
+		assert.ErrorIs(t, extraError, goyave.ErrInvalidQuery)
+	})
+
+	t.Run("Nested Form URL-encoded", func(t *testing.T) {
+		data := "user[name]=John&user[tags][]=a&user[tags][]=b&items[1]=y&items[0]=x"
+
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader(data))
+		request.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+
+		result := server.TestMiddleware(&Middleware{NestedKeys: true}, request, func(resp *goyave.Response, req *goyave.Request) {
+			expected := map[string]any{
+				"user": map[string]any{
+					"name": "John",
+					"tags": []any{"a", "b"},
+				},
+				"items": []any{"x", "y"},
+			}
+			assert.Equal(t, expected, req.Data)
+			resp.Status(http.StatusOK)
+		})
+
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	})
+
+	t.Run("Nested Form Error", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader("items[101]=a"))
+		request.Lang = server.Lang.GetDefault()
+		request.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+
+		result := server.TestMiddleware(&Middleware{NestedKeys: true}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+		extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+		require.True(t, ok)
+		assert.ErrorIs(t, extraError, goyave.ErrInvalidContentForType)
+	})
+
+	t.Run("Nested Multipart", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		require.NoError(t, testutil.WriteMultipartFile(writer, &osfs.FS{}, "../../resources/img/logo/goyave_16.png", "user[picture]", "goyave_16.png"))
+		require.NoError(t, writer.WriteField("user[email]", "johndoe@example.org"))
+		require.NoError(t, writer.Close())
+
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", writer.FormDataContentType())
+
+		result := server.TestMiddleware(&Middleware{NestedKeys: true}, request, func(resp *goyave.Response, req *goyave.Request) {
+			data, ok := req.Data.(map[string]any)
+			require.True(t, ok)
+			user, ok := data["user"].(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, "johndoe@example.org", user["email"])
+
+			picture, ok := user["picture"].([]fsutil.File)
+			require.True(t, ok)
+			require.Len(t, picture, 1)
+			assert.Equal(t, "goyave_16.png", picture[0].Header.Filename)
+			resp.Status(http.StatusOK)
+		})
+
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	})
+
 	t.Run("Body already parsed", func(t *testing.T) {
 		data := map[string]any{
 			"a": "b",
@@ -0,0 +1,173 @@
+package secure
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/csrf"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// DefaultReportPath the default path of the route registered by `ReportController`.
+const DefaultReportPath = "/csp-report"
+
+// maxReportSize the maximum size of a report body, in bytes.
+const maxReportSize = 64 * 1024
+
+// Report a Content-Security-Policy violation report sent by a browser.
+type Report struct {
+	DocumentURL        string `json:"documentURL"`
+	Referrer           string `json:"referrer,omitempty"`
+	BlockedURL         string `json:"blockedURL"`
+	EffectiveDirective string `json:"effectiveDirective"`
+	OriginalPolicy     string `json:"originalPolicy"`
+	Disposition        string `json:"disposition"`
+	SourceFile         string `json:"sourceFile,omitempty"`
+	Sample             string `json:"sample,omitempty"`
+	StatusCode         int    `json:"statusCode,omitempty"`
+	LineNumber         int    `json:"lineNumber,omitempty"`
+	ColumnNumber       int    `json:"columnNumber,omitempty"`
+}
+
+// legacyReport the "report-uri" format ("application/csp-report").
+type legacyReport struct {
+	Report struct {
+		DocumentURI        string `json:"document-uri"`
+		Referrer           string `json:"referrer"`
+		BlockedURI         string `json:"blocked-uri"`
+		ViolatedDirective  string `json:"violated-directive"`
+		EffectiveDirective string `json:"effective-directive"`
+		OriginalPolicy     string `json:"original-policy"`
+		Disposition        string `json:"disposition"`
+		SourceFile         string `json:"source-file"`
+		ScriptSample       string `json:"script-sample"`
+		StatusCode         int    `json:"status-code"`
+		LineNumber         int    `json:"line-number"`
+		ColumnNumber       int    `json:"column-number"`
+	} `json:"csp-report"`
+}
+
+// reportingAPIReport the Reporting API format ("application/reports+json").
+type reportingAPIReport struct {
+	Type string `json:"type"`
+	Body Report `json:"body"`
+}
+
+// ParseReports parses a CSP violation report body. Both the legacy "report-uri"
+// format and the Reporting API format (used by the "report-to" directive) are supported.
+func ParseReports(body []byte) ([]Report, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var reports []reportingAPIReport
+		if err := json.Unmarshal(body, &reports); err != nil {
+			return nil, errors.New(err)
+		}
+		result := make([]Report, 0, len(reports))
+		for _, r := range reports {
+			if r.Type != "csp-violation" {
+				continue
+			}
+			result = append(result, r.Body)
+		}
+		return result, nil
+	}
+
+	var legacy legacyReport
+	if err := json.Unmarshal(body, &legacy); err != nil {
+		return nil, errors.New(err)
+	}
+	r := legacy.Report
+	directive := r.EffectiveDirective
+	if directive == "" {
+		directive = r.ViolatedDirective
+	}
+	return []Report{{
+		DocumentURL:        r.DocumentURI,
+		Referrer:           r.Referrer,
+		BlockedURL:         r.BlockedURI,
+		EffectiveDirective: directive,
+		OriginalPolicy:     r.OriginalPolicy,
+		Disposition:        r.Disposition,
+		SourceFile:         r.SourceFile,
+		Sample:             r.ScriptSample,
+		StatusCode:         r.StatusCode,
+		LineNumber:         r.LineNumber,
+		ColumnNumber:       r.ColumnNumber,
+	}}, nil
+}
+
+// ReportController registers a route collecting the Content-Security-Policy violation
+// reports sent by browsers. Set the "secure.csp.reportURI" config entry (or `Options.ReportURI`)
+// to the URL of this route so browsers know where to send them.
+//
+// The route is exempt from CSRF protection. Reports are logged as warnings
+// unless `OnReport` is set. Malformed reports are answered with "400 Bad Request",
+// valid ones with "204 No Content".
+//
+// The body is read directly, so the route works with or without the parse middleware.
+type ReportController struct {
+	goyave.Component
+
+	// Path the path of the report route. Defaults to `DefaultReportPath`.
+	Path string
+
+	// OnReport if not nil, called for each received report instead of logging it.
+	OnReport func(request *goyave.Request, report Report)
+}
+
+// RegisterRoutes registers the report route.
+func (c *ReportController) RegisterRoutes(router *goyave.Router) {
+	path := c.Path
+	if path == "" {
+		path = DefaultReportPath
+	}
+	router.Post(path, c.Collect).SetMeta(csrf.MetaExempt, true)
+}
+
+// Collect parses the CSP violation reports contained in the request body and handles them.
+func (c *ReportController) Collect(response *goyave.Response, request *goyave.Request) {
+	body, err := c.readBody(request)
+	if err != nil {
+		response.Status(http.StatusBadRequest)
+		return
+	}
+	reports, err := ParseReports(body)
+	if err != nil {
+		response.Status(http.StatusBadRequest)
+		return
+	}
+
+	for _, report := range reports {
+		if c.OnReport != nil {
+			c.OnReport(request, report)
+			continue
+		}
+		c.Logger().WarnContext(request.Context(), "Content-Security-Policy violation",
+			"documentURL", report.DocumentURL,
+			"blockedURL", report.BlockedURL,
+			"effectiveDirective", report.EffectiveDirective,
+			"disposition", report.Disposition,
+			"sourceFile", report.SourceFile,
+			"lineNumber", report.LineNumber,
+		)
+	}
+	response.Status(http.StatusNoContent)
+}
+
+func (c *ReportController) readBody(request *goyave.Request) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(request.Body(), maxReportSize+1))
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	if len(body) > maxReportSize {
+		return nil, errors.New("secure: CSP report too large")
+	}
+	if len(body) == 0 && request.Data != nil {
+		// The body was already consumed by the parse middleware ("application/json").
+		return json.Marshal(request.Data)
+	}
+	return body, nil
+}
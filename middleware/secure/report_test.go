@@ -0,0 +1,107 @@
+package secure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/csrf"
+	"goyave.dev/goyave/v5/middleware/parse"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+const legacyReportBody = `{"csp-report":{"document-uri":"https://example.org/page","referrer":"","blocked-uri":"inline","violated-directive":"script-src-elem","effective-directive":"script-src-elem","original-policy":"script-src 'self'","disposition":"enforce","source-file":"https://example.org/page","status-code":200,"line-number":12,"column-number":3}}`
+
+const reportingAPIBody = `[
+	{"type":"csp-violation","age":10,"url":"https://example.org/page","body":{"documentURL":"https://example.org/page","blockedURL":"https://evil.example.org/x.js","effectiveDirective":"script-src-elem","originalPolicy":"script-src 'self'","disposition":"report","statusCode":200}},
+	{"type":"deprecation","age":10,"url":"https://example.org/page","body":{}}
+]`
+
+func TestParseReports(t *testing.T) {
+	reports, err := ParseReports([]byte(legacyReportBody))
+	require.NoError(t, err)
+	assert.Equal(t, []Report{{
+		DocumentURL:        "https://example.org/page",
+		BlockedURL:         "inline",
+		EffectiveDirective: "script-src-elem",
+		OriginalPolicy:     "script-src 'self'",
+		Disposition:        "enforce",
+		SourceFile:         "https://example.org/page",
+		StatusCode:         200,
+		LineNumber:         12,
+		ColumnNumber:       3,
+	}}, reports)
+
+	reports, err = ParseReports([]byte(reportingAPIBody))
+	require.NoError(t, err)
+	assert.Equal(t, []Report{{
+		DocumentURL:        "https://example.org/page",
+		BlockedURL:         "https://evil.example.org/x.js",
+		EffectiveDirective: "script-src-elem",
+		OriginalPolicy:     "script-src 'self'",
+		Disposition:        "report",
+		StatusCode:         200,
+	}}, reports)
+
+	_, err = ParseReports([]byte("not json"))
+	require.Error(t, err)
+	_, err = ParseReports([]byte("[not json"))
+	require.Error(t, err)
+}
+
+func TestReportController(t *testing.T) {
+	prepare := func(t *testing.T) (*testutil.TestServer, *[]Report) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		router := server.Router()
+		router.GlobalMiddleware(&parse.Middleware{})
+		router.Middleware(csrf.New(nil))
+		reports := &[]Report{}
+		router.Controller(&ReportController{
+			OnReport: func(_ *goyave.Request, report Report) {
+				*reports = append(*reports, report)
+			},
+		})
+		return server, reports
+	}
+
+	cases := []struct {
+		desc        string
+		contentType string
+		body        string
+		want        int
+		wantReports int
+	}{
+		{desc: "legacy", contentType: "application/csp-report", body: legacyReportBody, want: http.StatusNoContent, wantReports: 1},
+		{desc: "legacy_json", contentType: "application/json", body: legacyReportBody, want: http.StatusNoContent, wantReports: 1},
+		{desc: "reporting_api", contentType: "application/reports+json", body: reportingAPIBody, want: http.StatusNoContent, wantReports: 1},
+		{desc: "malformed", contentType: "application/csp-report", body: "{", want: http.StatusBadRequest},
+		{desc: "too_large", contentType: "application/csp-report", body: strings.Repeat(" ", maxReportSize+1), want: http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			server, reports := prepare(t)
+			req := httptest.NewRequest(http.MethodPost, DefaultReportPath, strings.NewReader(c.body))
+			req.Header.Set("Content-Type", c.contentType)
+			resp := server.TestRequest(req)
+			assert.Equal(t, c.want, resp.StatusCode)
+			assert.Len(t, *reports, c.wantReports)
+			require.NoError(t, resp.Body.Close())
+		})
+	}
+
+	t.Run("log", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		server.Router().Controller(&ReportController{Path: "/report"})
+		req := httptest.NewRequest(http.MethodPost, "/report", strings.NewReader(legacyReportBody))
+		req.Header.Set("Content-Type", "application/csp-report")
+		resp := server.TestRequest(req)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	})
+}
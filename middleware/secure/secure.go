@@ -0,0 +1,287 @@
+package secure
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// MetaOptions the meta key used to override the `*Options` of the secure middleware
+// for a router or a route. Setting this meta to a `nil` `*Options` disables the
+// middleware for the router or route.
+const MetaOptions = "goyave.secure"
+
+// NoncePlaceholder the placeholder replaced by the request nonce source
+// (e.g. `'nonce-rAnd0m'`) in the Content-Security-Policy.
+const NoncePlaceholder = "{nonce}"
+
+// nonceLength the length of the generated nonces, in bytes, before encoding.
+const nonceLength = 16
+
+func init() {
+	config.Register("secure.hsts.maxAge", config.Entry{
+		Value:            31536000,
+		Type:             reflect.Int,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("secure.hsts.includeSubDomains", config.Entry{
+		Value:            false,
+		Type:             reflect.Bool,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("secure.hsts.preload", config.Entry{
+		Value:            false,
+		Type:             reflect.Bool,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("secure.contentTypeNosniff", config.Entry{
+		Value:            true,
+		Type:             reflect.Bool,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("secure.frameOptions", config.Entry{
+		Value:            "DENY",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{"", "DENY", "SAMEORIGIN"},
+	})
+	config.Register("secure.referrerPolicy", config.Entry{
+		Value:            "strict-origin-when-cross-origin",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("secure.permissionsPolicy", config.Entry{
+		Value:            "",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("secure.crossOriginOpenerPolicy", config.Entry{
+		Value:            "same-origin",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("secure.crossOriginEmbedderPolicy", config.Entry{
+		Value:            "",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("secure.csp.policy", config.Entry{
+		Value:            "default-src 'self'; script-src 'self' {nonce}; style-src 'self' {nonce}; object-src 'none'; base-uri 'self'; frame-ancestors 'none'",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("secure.csp.reportOnly", config.Entry{
+		Value:            false,
+		Type:             reflect.Bool,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("secure.csp.reportURI", config.Entry{
+		Value:            "",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+}
+
+// Options the security headers set by the secure middleware.
+// Empty values disable the corresponding header.
+type Options struct {
+	// ContentSecurityPolicy the value of the "Content-Security-Policy" header.
+	// All occurrences of `NoncePlaceholder` are replaced with the nonce source of the request.
+	ContentSecurityPolicy string
+
+	// ReportURI the URL to which the browsers send the CSP violation reports.
+	// If not empty, the "report-uri" and "report-to" directives are added to the policy
+	// and the "Reporting-Endpoints" header is set. See `ReportController`.
+	ReportURI string
+
+	// ReferrerPolicy the value of the "Referrer-Policy" header.
+	ReferrerPolicy string
+
+	// PermissionsPolicy the value of the "Permissions-Policy" header.
+	PermissionsPolicy string
+
+	// CrossOriginOpenerPolicy the value of the "Cross-Origin-Opener-Policy" header.
+	CrossOriginOpenerPolicy string
+
+	// CrossOriginEmbedderPolicy the value of the "Cross-Origin-Embedder-Policy" header.
+	CrossOriginEmbedderPolicy string
+
+	// FrameOptions the value of the "X-Frame-Options" header ("DENY" or "SAMEORIGIN").
+	FrameOptions string
+
+	// HSTSMaxAge the "max-age" of the "Strict-Transport-Security" header, in seconds.
+	// The header is only sent for requests using HTTPS (see `goyave.Request.Scheme()`).
+	// 0 disables the header.
+	HSTSMaxAge int
+
+	// HSTSIncludeSubDomains adds the "includeSubDomains" directive to the HSTS header.
+	HSTSIncludeSubDomains bool
+
+	// HSTSPreload adds the "preload" directive to the HSTS header.
+	HSTSPreload bool
+
+	// ContentTypeNosniff sets the "X-Content-Type-Options: nosniff" header.
+	ContentTypeNosniff bool
+
+	// ReportOnly sends the policy in the "Content-Security-Policy-Report-Only" header
+	// instead: violations are reported but not enforced.
+	ReportOnly bool
+}
+
+// OptionsFromConfig creates new `Options` from the "secure.*" config entries.
+func OptionsFromConfig(cfg *config.Config) *Options {
+	return &Options{
+		ContentSecurityPolicy:     cfg.GetString("secure.csp.policy"),
+		ReportURI:                 cfg.GetString("secure.csp.reportURI"),
+		ReferrerPolicy:            cfg.GetString("secure.referrerPolicy"),
+		PermissionsPolicy:         cfg.GetString("secure.permissionsPolicy"),
+		CrossOriginOpenerPolicy:   cfg.GetString("secure.crossOriginOpenerPolicy"),
+		CrossOriginEmbedderPolicy: cfg.GetString("secure.crossOriginEmbedderPolicy"),
+		FrameOptions:              cfg.GetString("secure.frameOptions"),
+		HSTSMaxAge:                cfg.GetInt("secure.hsts.maxAge"),
+		HSTSIncludeSubDomains:     cfg.GetBool("secure.hsts.includeSubDomains"),
+		HSTSPreload:               cfg.GetBool("secure.hsts.preload"),
+		ContentTypeNosniff:        cfg.GetBool("secure.contentTypeNosniff"),
+		ReportOnly:                cfg.GetBool("secure.csp.reportOnly"),
+	}
+}
+
+// Clone returns a copy of the options. This is useful to override some of the
+// options for a specific route using the `MetaOptions` meta.
+func (o *Options) Clone() *Options {
+	clone := *o
+	return &clone
+}
+
+func (o *Options) hsts() string {
+	value := "max-age=" + strconv.Itoa(o.HSTSMaxAge)
+	if o.HSTSIncludeSubDomains {
+		value += "; includeSubDomains"
+	}
+	if o.HSTSPreload {
+		value += "; preload"
+	}
+	return value
+}
+
+func (o *Options) csp(nonce string) string {
+	policy := strings.ReplaceAll(o.ContentSecurityPolicy, NoncePlaceholder, "'nonce-"+nonce+"'")
+	if o.ReportURI != "" {
+		policy = strings.TrimRight(strings.TrimSpace(policy), ";") + "; report-uri " + o.ReportURI + "; report-to " + reportingGroup
+	}
+	return policy
+}
+
+// reportingGroup the name of the reporting endpoint used in the "report-to" directive.
+const reportingGroup = "csp-endpoint"
+
+// Middleware sets security-related response headers: HSTS, "X-Content-Type-Options",
+// "Referrer-Policy", "Permissions-Policy", "Cross-Origin-Opener-Policy",
+// "Cross-Origin-Embedder-Policy", "X-Frame-Options" and "Content-Security-Policy".
+//
+// If the Content-Security-Policy is not empty, a random nonce is generated for each request
+// and stored in the request's `Extra` (key `goyave.ExtraCSPNonce{}`). It can be retrieved with
+// `secure.Nonce()` and is available in templates with the "csp_nonce" function.
+//
+// The options used are the ones defined by the `MetaOptions` meta of the matched route
+// (or its parent routers), or the middleware's `Options` if there is no such meta.
+type Middleware struct {
+	goyave.Component
+
+	// Options the default options. If `nil`, the options are
+	// created from the config using `OptionsFromConfig()`.
+	Options *Options
+}
+
+// Init the middleware. If the options are `nil`, creates them from the config.
+func (m *Middleware) Init(server *goyave.Server) {
+	m.Component.Init(server)
+	if m.Options == nil {
+		m.Options = OptionsFromConfig(server.Config())
+	}
+}
+
+// Handle sets the security headers.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		options := m.Options
+		if o, ok := request.Route.LookupMeta(MetaOptions); ok {
+			options, _ = o.(*Options)
+		}
+		if options == nil {
+			next(response, request)
+			return
+		}
+
+		header := response.Header()
+		if options.HSTSMaxAge > 0 && request.Scheme() == "https" {
+			header.Set("Strict-Transport-Security", options.hsts())
+		}
+		if options.ContentTypeNosniff {
+			header.Set("X-Content-Type-Options", "nosniff")
+		}
+		setIfNotEmpty(header, "Referrer-Policy", options.ReferrerPolicy)
+		setIfNotEmpty(header, "Permissions-Policy", options.PermissionsPolicy)
+		setIfNotEmpty(header, "Cross-Origin-Opener-Policy", options.CrossOriginOpenerPolicy)
+		setIfNotEmpty(header, "Cross-Origin-Embedder-Policy", options.CrossOriginEmbedderPolicy)
+		setIfNotEmpty(header, "X-Frame-Options", options.FrameOptions)
+
+		if options.ContentSecurityPolicy != "" {
+			nonce, err := generateNonce()
+			if err != nil {
+				panic(err)
+			}
+			request.Extra[goyave.ExtraCSPNonce{}] = nonce
+
+			headerName := "Content-Security-Policy"
+			if options.ReportOnly {
+				headerName = "Content-Security-Policy-Report-Only"
+			}
+			header.Set(headerName, options.csp(nonce))
+			if options.ReportURI != "" {
+				header.Set("Reporting-Endpoints", reportingGroup+`="`+options.ReportURI+`"`)
+			}
+		}
+
+		next(response, request)
+	}
+}
+
+func setIfNotEmpty(header http.Header, key, value string) {
+	if value != "" {
+		header.Set(key, value)
+	}
+}
+
+func generateNonce() (string, error) {
+	b := make([]byte, nonceLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.New(err)
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Nonce returns the Content-Security-Policy nonce of the given request set by the
+// secure middleware. Returns an empty string if there is none.
+func Nonce(request *goyave.Request) string {
+	nonce, _ := request.Extra[goyave.ExtraCSPNonce{}].(string)
+	return nonce
+}
@@ -0,0 +1,156 @@
+package secure
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func prepareSecureTest(t *testing.T, cfg *config.Config, middleware *Middleware) (*testutil.TestServer, *goyave.Router) {
+	if cfg == nil {
+		cfg = config.LoadDefault()
+	}
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+	router := server.Router()
+	router.GlobalMiddleware(middleware)
+	router.Get("/", func(response *goyave.Response, request *goyave.Request) {
+		response.String(http.StatusOK, Nonce(request))
+	})
+	return server, router
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return string(body)
+}
+
+func TestOptionsFromConfig(t *testing.T) {
+	cfg := config.LoadDefault()
+	cfg.Set("secure.hsts.maxAge", 60)
+	cfg.Set("secure.hsts.preload", true)
+	cfg.Set("secure.csp.reportOnly", true)
+	cfg.Set("secure.csp.reportURI", "/csp-report")
+	cfg.Set("secure.permissionsPolicy", "camera=()")
+
+	options := OptionsFromConfig(cfg)
+	assert.Equal(t, &Options{
+		ContentSecurityPolicy:     "default-src 'self'; script-src 'self' {nonce}; style-src 'self' {nonce}; object-src 'none'; base-uri 'self'; frame-ancestors 'none'",
+		ReportURI:                 "/csp-report",
+		ReferrerPolicy:            "strict-origin-when-cross-origin",
+		PermissionsPolicy:         "camera=()",
+		CrossOriginOpenerPolicy:   "same-origin",
+		CrossOriginEmbedderPolicy: "",
+		FrameOptions:              "DENY",
+		HSTSMaxAge:                60,
+		HSTSIncludeSubDomains:     false,
+		HSTSPreload:               true,
+		ContentTypeNosniff:        true,
+		ReportOnly:                true,
+	}, options)
+
+	clone := options.Clone()
+	assert.Equal(t, options, clone)
+	assert.NotSame(t, options, clone)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		server, _ := prepareSecureTest(t, nil, &Middleware{})
+		resp := server.TestRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		nonce := readBody(t, resp)
+		require.NotEmpty(t, nonce)
+		raw, err := base64.StdEncoding.DecodeString(nonce)
+		require.NoError(t, err)
+		assert.Len(t, raw, nonceLength)
+
+		assert.Empty(t, resp.Header.Get("Strict-Transport-Security"))
+		assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+		assert.Equal(t, "DENY", resp.Header.Get("X-Frame-Options"))
+		assert.Equal(t, "strict-origin-when-cross-origin", resp.Header.Get("Referrer-Policy"))
+		assert.Equal(t, "same-origin", resp.Header.Get("Cross-Origin-Opener-Policy"))
+		assert.Empty(t, resp.Header.Get("Cross-Origin-Embedder-Policy"))
+		assert.Empty(t, resp.Header.Get("Permissions-Policy"))
+		assert.Empty(t, resp.Header.Get("Content-Security-Policy-Report-Only"))
+		assert.Empty(t, resp.Header.Get("Reporting-Endpoints"))
+		assert.Equal(t,
+			"default-src 'self'; script-src 'self' 'nonce-"+nonce+"'; style-src 'self' 'nonce-"+nonce+"'; object-src 'none'; base-uri 'self'; frame-ancestors 'none'",
+			resp.Header.Get("Content-Security-Policy"),
+		)
+
+		// A new nonce is generated for each request
+		resp = server.TestRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.NotEqual(t, nonce, readBody(t, resp))
+	})
+
+	t.Run("hsts", func(t *testing.T) {
+		server, _ := prepareSecureTest(t, nil, &Middleware{Options: &Options{
+			HSTSMaxAge:            3600,
+			HSTSIncludeSubDomains: true,
+			HSTSPreload:           true,
+		}})
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.TLS = &tls.ConnectionState{}
+		resp := server.TestRequest(req)
+		assert.Equal(t, "max-age=3600; includeSubDomains; preload", resp.Header.Get("Strict-Transport-Security"))
+		assert.Empty(t, readBody(t, resp)) // No CSP, no nonce
+		assert.Empty(t, resp.Header.Get("Content-Security-Policy"))
+		assert.Empty(t, resp.Header.Get("X-Content-Type-Options"))
+
+		// Not sent over plain HTTP
+		resp = server.TestRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Empty(t, resp.Header.Get("Strict-Transport-Security"))
+	})
+
+	t.Run("report_only", func(t *testing.T) {
+		server, _ := prepareSecureTest(t, nil, &Middleware{Options: &Options{
+			ContentSecurityPolicy: "script-src {nonce};",
+			ReportOnly:            true,
+			ReportURI:             "/csp-report",
+		}})
+		resp := server.TestRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		nonce := readBody(t, resp)
+		assert.Empty(t, resp.Header.Get("Content-Security-Policy"))
+		assert.Equal(t,
+			"script-src 'nonce-"+nonce+"'; report-uri /csp-report; report-to csp-endpoint",
+			resp.Header.Get("Content-Security-Policy-Report-Only"),
+		)
+		assert.Equal(t, `csp-endpoint="/csp-report"`, resp.Header.Get("Reporting-Endpoints"))
+	})
+
+	t.Run("meta_override", func(t *testing.T) {
+		server, router := prepareSecureTest(t, nil, &Middleware{})
+		options := OptionsFromConfig(server.Config())
+		options.FrameOptions = "SAMEORIGIN"
+		options.ContentSecurityPolicy = ""
+		router.Get("/override", func(response *goyave.Response, _ *goyave.Request) {
+			response.Status(http.StatusOK)
+		}).SetMeta(MetaOptions, options)
+		router.Get("/disabled", func(response *goyave.Response, _ *goyave.Request) {
+			response.Status(http.StatusOK)
+		}).SetMeta(MetaOptions, (*Options)(nil))
+
+		resp := server.TestRequest(httptest.NewRequest(http.MethodGet, "/override", nil))
+		assert.Equal(t, "SAMEORIGIN", resp.Header.Get("X-Frame-Options"))
+		assert.Empty(t, resp.Header.Get("Content-Security-Policy"))
+		assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+
+		resp = server.TestRequest(httptest.NewRequest(http.MethodGet, "/disabled", nil))
+		for key := range resp.Header {
+			assert.False(t, strings.HasPrefix(key, "X-"), key)
+		}
+		assert.Empty(t, resp.Header.Get("Content-Security-Policy"))
+		assert.Empty(t, resp.Header.Get("Referrer-Policy"))
+	})
+}
@@ -292,7 +292,10 @@ func TestValidateMiddleware(t *testing.T) {
 			expectPass:   false,
 			expectStatus: http.StatusUnprocessableEntity,
 			expectQueryErrors: &validation.Errors{Fields: validation.FieldsErrors{
-				"param": &validation.Errors{Errors: []string{"The param must be at least 5 characters."}},
+				"param": &validation.Errors{
+					Errors:     []string{"The param must be at least 5 characters."},
+					Violations: []*validation.Violation{{Rule: "min", Message: "The param must be at least 5 characters.", Params: map[string]string{"min": "5"}}},
+				},
 			}},
 		},
 		{
@@ -329,7 +332,10 @@ func TestValidateMiddleware(t *testing.T) {
 			expectPass:   false,
 			expectStatus: http.StatusUnprocessableEntity,
 			expectQueryErrors: &validation.Errors{Fields: validation.FieldsErrors{
-				"param": &validation.Errors{Errors: []string{"validation.rules.test_validator"}},
+				"param": &validation.Errors{
+					Errors:     []string{"validation.rules.test_validator"},
+					Violations: []*validation.Violation{{Rule: "test_validator", Message: "validation.rules.test_validator"}},
+				},
 			}},
 		},
 		{
@@ -367,7 +373,10 @@ func TestValidateMiddleware(t *testing.T) {
 			expectPass:   false,
 			expectStatus: http.StatusUnprocessableEntity,
 			expectBodyErrors: &validation.Errors{Fields: validation.FieldsErrors{
-				"param": &validation.Errors{Errors: []string{"The param must be at least 5 characters."}},
+				"param": &validation.Errors{
+					Errors:     []string{"The param must be at least 5 characters."},
+					Violations: []*validation.Violation{{Rule: "min", Message: "The param must be at least 5 characters.", Params: map[string]string{"min": "5"}}},
+				},
 			}},
 		},
 		{
@@ -404,7 +413,10 @@ func TestValidateMiddleware(t *testing.T) {
 			expectPass:   false,
 			expectStatus: http.StatusUnprocessableEntity,
 			expectBodyErrors: &validation.Errors{Fields: validation.FieldsErrors{
-				"param": &validation.Errors{Errors: []string{"validation.rules.test_validator"}},
+				"param": &validation.Errors{
+					Errors:     []string{"validation.rules.test_validator"},
+					Violations: []*validation.Violation{{Rule: "test_validator", Message: "validation.rules.test_validator"}},
+				},
 			}},
 		},
 		{
@@ -430,7 +442,10 @@ func TestValidateMiddleware(t *testing.T) {
 			expectPass:   false,
 			expectStatus: http.StatusUnprocessableEntity,
 			expectBodyErrors: &validation.Errors{Fields: validation.FieldsErrors{
-				"param": &validation.Errors{Errors: []string{"The param must be an array."}},
+				"param": &validation.Errors{
+					Errors:     []string{"The param must be an array."},
+					Violations: []*validation.Violation{{Rule: "array", Message: "The param must be an array."}},
+				},
 			}},
 		},
 		{
@@ -545,6 +560,94 @@ func TestValidateMiddleware(t *testing.T) {
 	}
 }
 
+func TestValidateMiddlewareStatic(t *testing.T) {
+	server, err := New(Options{Config: config.LoadDefault(), Logger: slog.New(slog.NewHandler(false, &bytes.Buffer{}))})
+	if err != nil {
+		panic(err)
+	}
+
+	calls := 0
+	bodyRules := func(_ *Request) validation.RuleSet {
+		calls++
+		return validation.RuleSet{
+			{Path: "name", Rules: validation.List{validation.Required(), validation.String(), validation.Trim(), validation.Max(5)}},
+		}
+	}
+	queryCalls := 0
+	queryRules := func(_ *Request) validation.RuleSet {
+		queryCalls++
+		return validation.RuleSet{
+			{Path: "page", Rules: validation.List{validation.Int(), validation.Default(1)}},
+		}
+	}
+
+	m := &validateRequestMiddleware{
+		BodyRules:  bodyRules,
+		QueryRules: queryRules,
+		Static:     true,
+	}
+	m.Init(server)
+
+	handle := func(data map[string]any) (*Request, bool) {
+		request := NewRequest(httptest.NewRequest(http.MethodPost, "/test", nil))
+		request.Lang = server.Lang.GetDefault()
+		request.Query = map[string]any{}
+		request.Data = data
+		response := NewResponse(server, request, httptest.NewRecorder())
+		pass := false
+		m.Handle(func(_ *Response, _ *Request) {
+			pass = true
+		})(response, request)
+		return request, pass
+	}
+
+	request, pass := handle(map[string]any{"name": " john "})
+	assert.True(t, pass)
+	assert.Equal(t, map[string]any{"name": "john"}, request.Data)
+	assert.Equal(t, map[string]any{"page": 1}, request.Query)
+
+	request, pass = handle(map[string]any{"name": "abcdefgh"})
+	assert.False(t, pass)
+	assert.Equal(t, []string{"The name may not have more than 5 characters."}, request.Extra[ExtraValidationError{}].(*validation.Errors).Fields["name"].Errors)
+	assert.Equal(t, map[string]any{"page": 1}, request.Query)
+
+	request, pass = handle(map[string]any{"name": "doe"})
+	assert.True(t, pass)
+	assert.Equal(t, map[string]any{"name": "doe"}, request.Data)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, queryCalls)
+
+	t.Run("panic", func(t *testing.T) {
+		calls := 0
+		m := &validateRequestMiddleware{
+			BodyRules: func(_ *Request) validation.RuleSet {
+				calls++
+				if calls == 1 {
+					panic("test panic")
+				}
+				return validation.RuleSet{
+					{Path: "name", Rules: validation.List{validation.Required(), validation.String()}},
+				}
+			},
+			Static: true,
+		}
+		m.Init(server)
+		request := NewRequest(httptest.NewRequest(http.MethodPost, "/test", nil))
+		assert.Panics(t, func() {
+			m.rules(&m.bodyCache, m.BodyRules, request)
+		})
+		assert.Nil(t, m.bodyCache.rules.Load())
+
+		// The rules are compiled again on the next request
+		rules := m.rules(&m.bodyCache, m.BodyRules, request)
+		assert.NotNil(t, rules)
+		assert.NotNil(t, m.bodyCache.rules.Load())
+		m.rules(&m.bodyCache, m.BodyRules, request)
+		assert.Equal(t, 2, calls)
+	})
+}
+
 func TestCORSMiddleware(t *testing.T) {
 	cases := []struct {
 		options            func() *cors.Options
@@ -0,0 +1,213 @@
+package goyave
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"goyave.dev/goyave/v5/lang"
+	errorutil "goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/validation"
+)
+
+// ContentTypeProblemJSON the media type of RFC 9457 problem details documents.
+const ContentTypeProblemJSON = "application/problem+json"
+
+// ProblemTypeBlank the default problem type. When used, the problem has no additional
+// semantics beyond that of the HTTP status code.
+const ProblemTypeBlank = "about:blank"
+
+// Problem RFC 9457 "Problem Details for HTTP APIs" document.
+//
+// Extension members are serialized at the top level of the document, next to
+// the standard members. Extension members cannot override standard members.
+type Problem struct {
+	// Extensions additional members of the problem details document.
+	Extensions map[string]any
+
+	// Type a URI reference identifying the problem type.
+	// Defaults to "about:blank" when written with `Response.Problem()`.
+	Type string
+
+	// Title short, human-readable summary of the problem type.
+	// If empty when written with `Response.Problem()`, the title is
+	// localized using the language entry "problem.<status>" (e.g. "problem.404")
+	// and falls back to the standard status text.
+	Title string
+
+	// Detail human-readable explanation specific to this occurrence of the problem.
+	Detail string
+
+	// Instance a URI reference identifying the specific occurrence of the problem.
+	// Defaults to the request URI when written with `Response.Problem()`.
+	Instance string
+
+	// Status the HTTP status code generated by the origin server for this occurrence of the problem.
+	Status int
+}
+
+// NewProblem create a new `Problem` for the given status.
+func NewProblem(status int) *Problem {
+	return &Problem{Status: status}
+}
+
+// WithDetail set the problem detail and returns itself.
+func (p *Problem) WithDetail(detail string) *Problem {
+	p.Detail = detail
+	return p
+}
+
+// WithType set the problem type and returns itself.
+func (p *Problem) WithType(problemType string) *Problem {
+	p.Type = problemType
+	return p
+}
+
+// WithExtension add an extension member to the problem and returns itself.
+func (p *Problem) WithExtension(name string, value any) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any, 1)
+	}
+	p.Extensions[name] = value
+	return p
+}
+
+// MarshalJSON implementation of `json.Marshaler`.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	document := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		document[k] = v
+	}
+	document["type"] = p.Type
+	document["title"] = p.Title
+	document["status"] = p.Status
+	if p.Detail != "" {
+		document["detail"] = p.Detail
+	} else {
+		delete(document, "detail")
+	}
+	if p.Instance != "" {
+		document["instance"] = p.Instance
+	} else {
+		delete(document, "instance")
+	}
+	return json.Marshal(document)
+}
+
+// ProblemTitle returns the localized title for the given status code.
+// The language entry "problem.<status>" is used. If it doesn't exist,
+// the standard status text is returned.
+func ProblemTitle(language *lang.Language, status int) string {
+	entry := "problem." + strconv.Itoa(status)
+	if language != nil {
+		if title := language.Get(entry); title != entry {
+			return title
+		}
+	}
+	return http.StatusText(status)
+}
+
+// Problem write the given problem details as a response using the
+// "application/problem+json" content type.
+//
+// If the problem's status is 0, uses the status previously set with `Status()`,
+// or 500 if there is none. Missing `Type`, `Title` and `Instance` are
+// completed automatically (see `Problem`) on a copy: the given problem is not modified.
+func (r *Response) Problem(p *Problem) {
+	problem := *p
+	if problem.Status == 0 {
+		problem.Status = http.StatusInternalServerError
+		if r.status != 0 {
+			problem.Status = r.status
+		}
+	}
+	if problem.Type == "" {
+		problem.Type = ProblemTypeBlank
+	}
+	if problem.Title == "" {
+		var language *lang.Language
+		if r.request != nil {
+			language = r.request.Lang
+		}
+		if language == nil {
+			language = r.server.Lang.GetDefault()
+		}
+		problem.Title = ProblemTitle(language, problem.Status)
+	}
+	if problem.Instance == "" && r.request != nil {
+		problem.Instance = r.request.URL().RequestURI()
+	}
+
+	r.responseWriter.Header().Set("Content-Type", ContentTypeProblemJSON)
+	r.status = problem.Status
+	if err := json.NewEncoder(r).Encode(&problem); err != nil {
+		panic(errorutil.NewSkip(err, 3))
+	}
+}
+
+// ProblemPanicStatusHandler RFC 9457 alternative to `PanicStatusHandler`.
+// If debugging is enabled, the error message is written in the problem detail.
+type ProblemPanicStatusHandler struct {
+	Component
+}
+
+// Handle internal server error responses.
+func (h *ProblemPanicStatusHandler) Handle(response *Response, _ *Request) {
+	if response.Hijacked() {
+		return
+	}
+	problem := NewProblem(response.GetStatus())
+	if err := response.GetError(); err != nil && h.Config().GetBool("app.debug") {
+		problem.Detail = err.Error()
+	}
+	response.Problem(problem)
+}
+
+// ProblemStatusHandler RFC 9457 alternative to `ErrorStatusHandler`.
+// Writes a problem details document with a localized title. If the server is in
+// maintenance mode, the localized maintenance message is written in the problem detail.
+type ProblemStatusHandler struct {
+	Component
+}
+
+// Handle generic error responses.
+func (*ProblemStatusHandler) Handle(response *Response, request *Request) {
+	problem := NewProblem(response.GetStatus())
+	if m, ok := maintenanceMessage(request); ok {
+		problem.Detail = m
+	}
+	response.Problem(problem)
+}
+
+// ProblemParseErrorStatusHandler RFC 9457 alternative to `ParseErrorStatusHandler`.
+// The localized parse error message is written in the problem detail.
+type ProblemParseErrorStatusHandler struct {
+	Component
+}
+
+// Handle generic request (error) responses.
+func (*ProblemParseErrorStatusHandler) Handle(response *Response, request *Request) {
+	problem := NewProblem(response.GetStatus())
+	if _, ok := request.Extra[ExtraParseError{}]; ok {
+		problem.Detail = parseErrorMessage(response, request)
+	}
+	response.Problem(problem)
+}
+
+// ProblemValidationStatusHandler RFC 9457 alternative to `ValidationStatusHandler`.
+// The validation errors are written in the "errors" extension member, using
+// the same structure as `validation.ErrorResponse`, or in the given `Format`.
+type ProblemValidationStatusHandler struct {
+	Component
+
+	// Format the format of the validation errors written to the "errors" extension member.
+	// Defaults to `validation.ErrorFormatNested`.
+	Format validation.ErrorFormat
+}
+
+// Handle validation error responses.
+func (h *ProblemValidationStatusHandler) Handle(response *Response, request *Request) {
+	problem := NewProblem(response.GetStatus()).
+		WithExtension("errors", validationErrorResponse(request).Format(h.Format))
+	response.Problem(problem)
+}
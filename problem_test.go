@@ -0,0 +1,216 @@
+package goyave
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/lang"
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/validation"
+)
+
+func readProblem(t *testing.T, recorder *httptest.ResponseRecorder) (*http.Response, map[string]any) {
+	res := recorder.Result()
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, res.Body.Close())
+	require.NoError(t, err)
+
+	document := map[string]any{}
+	require.NoError(t, json.Unmarshal(body, &document))
+	return res, document
+}
+
+func TestProblem(t *testing.T) {
+	t.Run("MarshalJSON", func(t *testing.T) {
+		p := NewProblem(http.StatusConflict).
+			WithType("https://example.org/conflict").
+			WithDetail("detail").
+			WithExtension("balance", 30).
+			WithExtension("status", 999)
+		p.Title = "title"
+
+		res, err := json.Marshal(p)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"https://example.org/conflict","title":"title","status":409,"detail":"detail","balance":30}`, string(res))
+	})
+
+	t.Run("MarshalJSON_omit_empty", func(t *testing.T) {
+		p := &Problem{Type: ProblemTypeBlank, Title: "Not Found", Status: http.StatusNotFound}
+		res, err := json.Marshal(p)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"about:blank","title":"Not Found","status":404}`, string(res))
+	})
+
+	t.Run("Response", func(t *testing.T) {
+		req, resp, recorder := prepareStatusHandlerTest()
+		req.httpRequest = httptest.NewRequest(http.MethodGet, "/test?q=1", nil)
+		resp.Problem(NewProblem(http.StatusForbidden).WithDetail("detail"))
+
+		res, document := readProblem(t, recorder)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		assert.Equal(t, ContentTypeProblemJSON, res.Header.Get("Content-Type"))
+		assert.Equal(t, map[string]any{
+			"type":     "about:blank",
+			"title":    "Forbidden",
+			"status":   float64(http.StatusForbidden),
+			"detail":   "detail",
+			"instance": "/test?q=1",
+		}, document)
+	})
+
+	t.Run("Response_status_fallback", func(t *testing.T) {
+		_, resp, recorder := prepareStatusHandlerTest()
+		resp.Status(http.StatusTeapot)
+		resp.Problem(&Problem{})
+
+		res, document := readProblem(t, recorder)
+		assert.Equal(t, http.StatusTeapot, res.StatusCode)
+		assert.Equal(t, float64(http.StatusTeapot), document["status"])
+	})
+
+	t.Run("Response_does_not_modify_problem", func(t *testing.T) {
+		_, resp, recorder := prepareStatusHandlerTest()
+		problem := NewProblem(http.StatusNotFound)
+		resp.Problem(problem)
+
+		_, document := readProblem(t, recorder)
+		assert.Equal(t, "Not Found", document["title"])
+		assert.Equal(t, &Problem{Status: http.StatusNotFound}, problem)
+	})
+
+	t.Run("ProblemTitle", func(t *testing.T) {
+		req, _, _ := prepareStatusHandlerTest()
+		assert.Equal(t, "Not Found", ProblemTitle(req.Lang, http.StatusNotFound))
+		assert.Equal(t, "Not Found", ProblemTitle(nil, http.StatusNotFound))
+		assert.Equal(t, "Not Found", req.Lang.Get("problem.404"))
+		assert.Equal(t, "Network Authentication Required", req.Lang.Get("problem.511"))
+	})
+
+	t.Run("ProblemTitle_localized", func(t *testing.T) {
+		req, resp, recorder := prepareStatusHandlerTest()
+		languages := lang.New()
+		require.NoError(t, languages.Load(fstest.MapFS{
+			"fr-FR/locale.json": &fstest.MapFile{Data: []byte(`{"problem.404": "Ressource introuvable"}`)},
+		}, "fr-FR", "fr-FR"))
+		french := languages.GetLanguage("fr-FR")
+		assert.Equal(t, "Ressource introuvable", ProblemTitle(french, http.StatusNotFound))
+		assert.Equal(t, "Forbidden", ProblemTitle(french, http.StatusForbidden)) // Falls back to the status text
+
+		req.Lang = french
+		resp.Problem(NewProblem(http.StatusNotFound))
+		_, document := readProblem(t, recorder)
+		assert.Equal(t, "Ressource introuvable", document["title"])
+	})
+}
+
+func TestProblemStatusHandlers(t *testing.T) {
+	t.Run("ProblemStatusHandler", func(t *testing.T) {
+		req, resp, recorder := prepareStatusHandlerTest()
+		handler := &ProblemStatusHandler{}
+		handler.Init(resp.server)
+
+		resp.Status(http.StatusNotFound)
+		handler.Handle(resp, req)
+
+		res, document := readProblem(t, recorder)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		assert.Equal(t, map[string]any{
+			"type":     "about:blank",
+			"title":    "Not Found",
+			"status":   float64(http.StatusNotFound),
+			"instance": "/test",
+		}, document)
+	})
+
+	t.Run("ProblemPanicStatusHandler_debug", func(t *testing.T) {
+		req, resp, recorder := prepareStatusHandlerTest()
+		resp.server.config.Set("app.debug", true)
+		handler := &ProblemPanicStatusHandler{}
+		handler.Init(resp.server)
+
+		resp.err = errors.New("test error").(*errors.Error)
+		resp.Status(http.StatusInternalServerError)
+		handler.Handle(resp, req)
+
+		_, document := readProblem(t, recorder)
+		assert.Equal(t, "test error", document["detail"])
+		assert.Equal(t, "Internal Server Error", document["title"])
+	})
+
+	t.Run("ProblemPanicStatusHandler_no_debug", func(t *testing.T) {
+		req, resp, recorder := prepareStatusHandlerTest()
+		resp.server.config.Set("app.debug", false)
+		handler := &ProblemPanicStatusHandler{}
+		handler.Init(resp.server)
+
+		resp.err = errors.New("test error").(*errors.Error)
+		resp.Status(http.StatusInternalServerError)
+		handler.Handle(resp, req)
+
+		_, document := readProblem(t, recorder)
+		assert.NotContains(t, document, "detail")
+	})
+
+	t.Run("ProblemParseErrorStatusHandler", func(t *testing.T) {
+		req, resp, recorder := prepareStatusHandlerTest()
+		handler := &ProblemParseErrorStatusHandler{}
+		handler.Init(resp.server)
+
+		req.Extra[ExtraParseError{}] = ErrInvalidJSONBody
+		resp.Status(http.StatusBadRequest)
+		handler.Handle(resp, req)
+
+		_, document := readProblem(t, recorder)
+		assert.Equal(t, "The request Content-Type indicates JSON, but the request body is empty or invalid.", document["detail"])
+		assert.Equal(t, "Bad Request", document["title"])
+	})
+
+	t.Run("ProblemValidationStatusHandler", func(t *testing.T) {
+		req, resp, recorder := prepareStatusHandlerTest()
+		handler := &ProblemValidationStatusHandler{}
+		handler.Init(resp.server)
+
+		req.Extra[ExtraValidationError{}] = &validation.Errors{
+			Fields: validation.FieldsErrors{
+				"field": &validation.Errors{Errors: []string{"The field is required"}},
+			},
+		}
+		resp.Status(http.StatusUnprocessableEntity)
+		handler.Handle(resp, req)
+
+		res := recorder.Result()
+		body, err := io.ReadAll(res.Body)
+		assert.NoError(t, res.Body.Close())
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"about:blank","title":"Unprocessable Entity","status":422,"instance":"/test","errors":{"body":{"fields":{"field":{"errors":["The field is required"]}}}}}`, string(body))
+	})
+}
+
+func TestRouterProblemDetails(t *testing.T) {
+	server, err := New(Options{Config: config.LoadDefault()})
+	require.NoError(t, err)
+	router := server.Router()
+	router.ProblemDetails()
+	router.Get("/test", func(response *Response, _ *Request) {
+		response.Status(http.StatusForbidden)
+	})
+
+	assert.IsType(t, &ProblemPanicStatusHandler{}, router.statusHandlers[http.StatusInternalServerError])
+	assert.IsType(t, &ProblemParseErrorStatusHandler{}, router.statusHandlers[http.StatusBadRequest])
+	assert.IsType(t, &ProblemValidationStatusHandler{}, router.statusHandlers[http.StatusUnprocessableEntity])
+	assert.IsType(t, &ProblemStatusHandler{}, router.statusHandlers[http.StatusNotFound])
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test", nil))
+	res, document := readProblem(t, recorder)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	assert.Equal(t, ContentTypeProblemJSON, res.Header.Get("Content-Type"))
+	assert.Equal(t, "Forbidden", document["title"])
+}
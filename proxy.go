@@ -0,0 +1,238 @@
+package goyave
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// forwardedInfo the client information forwarded by trusted proxies.
+type forwardedInfo struct {
+	remoteAddress string
+	scheme        string
+	host          string
+}
+
+// forwardedHop a single hop of the proxy chain.
+type forwardedHop struct {
+	addr   string
+	scheme string
+	host   string
+}
+
+// parsePrefixes parses the given list of CIDRs. Single IP addresses are also accepted.
+func parsePrefixes(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			addr, err := netip.ParseAddr(cidr)
+			if err != nil {
+				return nil, errors.Errorf("invalid CIDR %q: %w", cidr, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, errors.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		if prefix.Addr().Is4In6() {
+			prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// isTrustedProxy returns true if the given address (with or without port)
+// is contained in one of the server's trusted proxies CIDRs.
+func (s *Server) isTrustedProxy(address string) bool {
+	if len(s.trustedProxies) == 0 {
+		return false
+	}
+	return prefixesContain(s.trustedProxies, address)
+}
+
+// prefixesContain returns true if the given address (with or without port)
+// is contained in one of the given prefixes.
+func prefixesContain(prefixes []netip.Prefix, address string) bool {
+	addr, ok := parseAddr(address)
+	if !ok {
+		return false
+	}
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAddr parses an IP address with or without port. IPv6 addresses can be enclosed in brackets.
+func parseAddr(address string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+	address = strings.TrimSuffix(strings.TrimPrefix(address, "["), "]")
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// resolveForwarded returns the client information forwarded by the proxies if the
+// request was received from a trusted proxy. The "Forwarded" header takes precedence
+// over the "X-Forwarded-*" headers.
+//
+// The proxy chain is walked from the closest hop to the furthest, and stops at the first
+// address that is not a trusted proxy: this address is the client address. Values
+// added by the client itself or by untrusted proxies are therefore never used.
+func (s *Server) resolveForwarded(req *http.Request) (forwardedInfo, bool) {
+	if !s.isTrustedProxy(req.RemoteAddr) {
+		return forwardedInfo{}, false
+	}
+
+	var hops []forwardedHop
+	if values := req.Header.Values("Forwarded"); len(values) > 0 {
+		hops = parseForwarded(values)
+	} else {
+		hops = parseXForwarded(req.Header)
+	}
+
+	info := forwardedInfo{}
+	client := -1
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, ok := parseAddr(hops[i].addr)
+		if !ok {
+			// Obfuscated or unknown identifier: the closest
+			// trusted proxy is the best known client address.
+			break
+		}
+		client = i
+		if !s.isTrustedProxy(addr.String()) {
+			break
+		}
+	}
+	if client == -1 {
+		if len(hops) > 0 {
+			info.scheme = hops[len(hops)-1].scheme
+			info.host = hops[len(hops)-1].host
+		}
+		return info, true
+	}
+
+	hop := hops[client]
+	if addr, ok := parseAddr(hop.addr); ok {
+		info.remoteAddress = addr.String()
+	}
+	info.scheme = hop.scheme
+	info.host = hop.host
+	return info, true
+}
+
+// parseForwarded parses the RFC 7239 "Forwarded" header values.
+func parseForwarded(values []string) []forwardedHop {
+	hops := []forwardedHop{}
+	for _, value := range values {
+		for _, element := range splitQuoted(value, ',') {
+			hop := forwardedHop{}
+			for _, pair := range splitQuoted(element, ';') {
+				key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if !ok {
+					continue
+				}
+				val = strings.Trim(strings.TrimSpace(val), `"`)
+				switch strings.ToLower(key) {
+				case "for":
+					hop.addr = val
+				case "proto":
+					hop.scheme = strings.ToLower(val)
+				case "host":
+					hop.host = val
+				}
+			}
+			hops = append(hops, hop)
+		}
+	}
+	return hops
+}
+
+// parseXForwarded parses the "X-Forwarded-For", "X-Forwarded-Proto" and "X-Forwarded-Host"
+// headers. Only the last (closest) values of "X-Forwarded-Proto" and "X-Forwarded-Host"
+// are used, and apply to the client hop.
+func parseXForwarded(header http.Header) []forwardedHop {
+	addrs := splitList(header.Values("X-Forwarded-For"))
+	hops := make([]forwardedHop, 0, len(addrs))
+	for _, addr := range addrs {
+		hops = append(hops, forwardedHop{addr: addr})
+	}
+
+	scheme := lastValue(header.Values("X-Forwarded-Proto"))
+	host := lastValue(header.Values("X-Forwarded-Host"))
+	if len(hops) == 0 && (scheme != "" || host != "") {
+		// The client address is unknown but the scheme and host are still relevant.
+		hops = append(hops, forwardedHop{})
+	}
+	for i := range hops {
+		hops[i].scheme = strings.ToLower(scheme)
+		hops[i].host = host
+	}
+	return hops
+}
+
+func splitList(values []string) []string {
+	result := []string{}
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
+func lastValue(values []string) string {
+	list := splitList(values)
+	if len(list) == 0 {
+		return ""
+	}
+	return list[len(list)-1]
+}
+
+// splitQuoted splits the given string using the given separator,
+// ignoring separators inside quoted strings.
+func splitQuoted(s string, sep rune) []string {
+	parts := []string{}
+	quoted := false
+	start := 0
+	for i, c := range s {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// RequestBaseURL returns the base URL of the application as seen by the client
+// of the given request. If the request was received from a trusted proxy that
+// forwarded the original host, the URL is built from the forwarded scheme and host,
+// followed by the "server.proxy.base" config entry.
+// Otherwise, returns the same value as "ProxyBaseURL()".
+//
+// The raw "Host" header of requests not forwarded by a trusted proxy is never used
+// so clients cannot inject an arbitrary host in generated URLs.
+func (s *Server) RequestBaseURL(request *Request) string {
+	if request == nil || request.forwarded == nil || request.forwarded.host == "" {
+		return s.ProxyBaseURL()
+	}
+	return request.Scheme() + "://" + request.forwarded.host + s.config.GetString("server.proxy.base")
+}
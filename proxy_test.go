@@ -0,0 +1,228 @@
+package goyave
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/config"
+)
+
+func newTrustedProxiesServer(t *testing.T, proxies ...string) *Server {
+	cfg := config.LoadDefault()
+	cfg.Set("server.trustedProxies", proxies)
+	server, err := New(Options{Config: cfg})
+	require.NoError(t, err)
+	return server
+}
+
+func TestParsePrefixes(t *testing.T) {
+	prefixes, err := parsePrefixes([]string{"10.0.0.0/8", "192.0.2.1", "2001:db8::/32", "::ffff:172.16.0.0/108", "10.1.2.3/16"})
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.0.2.1/32"),
+		netip.MustParsePrefix("2001:db8::/32"),
+		netip.MustParsePrefix("172.16.0.0/12"),
+		netip.MustParsePrefix("10.1.0.0/16"),
+	}, prefixes)
+
+	_, err = parsePrefixes([]string{"not an ip"})
+	require.Error(t, err)
+	_, err = parsePrefixes([]string{"10.0.0.0/64"})
+	require.Error(t, err)
+
+	cfg := config.LoadDefault()
+	cfg.Set("server.trustedProxies", []string{"invalid/8"})
+	_, err = New(Options{Config: cfg})
+	require.Error(t, err)
+}
+
+func TestResolveForwarded(t *testing.T) {
+	server := newTrustedProxiesServer(t, "10.0.0.0/8", "2001:db8::/32")
+
+	cases := []struct {
+		header     http.Header
+		desc       string
+		remoteAddr string
+		expected   forwardedInfo
+		trusted    bool
+	}{
+		{
+			desc:       "untrusted_peer",
+			remoteAddr: "192.0.2.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1"}, "X-Forwarded-Proto": {"https"}, "X-Forwarded-Host": {"evil.org"}},
+			trusted:    false,
+		},
+		{
+			desc:       "no_headers",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{},
+			trusted:    true,
+		},
+		{
+			desc:       "x_forwarded",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1"}, "X-Forwarded-Proto": {"HTTPS"}, "X-Forwarded-Host": {"example.org"}},
+			expected:   forwardedInfo{remoteAddress: "198.51.100.1", scheme: "https", host: "example.org"},
+			trusted:    true,
+		},
+		{
+			desc:       "x_forwarded_chain",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"203.0.113.5, 198.51.100.1", "10.0.0.2"}, "X-Forwarded-Proto": {"http, https"}},
+			expected:   forwardedInfo{remoteAddress: "198.51.100.1", scheme: "https"},
+			trusted:    true,
+		},
+		{
+			desc:       "x_forwarded_all_trusted",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"10.0.0.3, 10.0.0.2"}},
+			expected:   forwardedInfo{remoteAddress: "10.0.0.3"},
+			trusted:    true,
+		},
+		{
+			desc:       "x_forwarded_unknown",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1, unknown"}, "X-Forwarded-Proto": {"https"}},
+			expected:   forwardedInfo{scheme: "https"},
+			trusted:    true,
+		},
+		{
+			desc:       "x_forwarded_proto_only",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-Proto": {"https"}},
+			expected:   forwardedInfo{scheme: "https"},
+			trusted:    true,
+		},
+		{
+			desc:       "forwarded",
+			remoteAddr: "[2001:db8::1]:1234",
+			header:     http.Header{"Forwarded": {`for="[2001:db8:cafe::17]:4711";proto=https;host="example.org", for=10.0.0.2`}},
+			expected:   forwardedInfo{remoteAddress: "2001:db8:cafe::17", scheme: "https", host: "example.org"},
+			trusted:    true,
+		},
+		{
+			desc:       "forwarded_spoof",
+			remoteAddr: "10.0.0.1:1234",
+			header: http.Header{
+				"Forwarded":       {`for=203.0.113.5;host=evil.org;proto=http`, `for=198.51.100.1;proto=https;host=example.org`},
+				"X-Forwarded-For": {"192.0.2.1"},
+			},
+			expected: forwardedInfo{remoteAddress: "198.51.100.1", scheme: "https", host: "example.org"},
+			trusted:  true,
+		},
+		{
+			desc:       "forwarded_obfuscated",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"Forwarded": {`for=_hidden;proto=https;host=example.org`}},
+			expected:   forwardedInfo{scheme: "https", host: "example.org"},
+			trusted:    true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = c.remoteAddr
+			req.Header = c.header
+			info, ok := server.resolveForwarded(req)
+			assert.Equal(t, c.trusted, ok)
+			assert.Equal(t, c.expected, info)
+		})
+	}
+
+	t.Run("no_trusted_proxies", func(t *testing.T) {
+		server := newTrustedProxiesServer(t)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Forwarded-For", "198.51.100.1")
+		_, ok := server.resolveForwarded(req)
+		assert.False(t, ok)
+	})
+}
+
+func TestForwardedRequest(t *testing.T) {
+	server := newTrustedProxiesServer(t, "10.0.0.0/8")
+	server.config.Set("server.proxy.base", "/app")
+
+	var request *Request
+	var url string
+	server.Router().Get("/test", func(response *Response, r *Request) {
+		request = r
+		url = r.Route.BuildRequestURL(r)
+		response.Status(http.StatusNoContent)
+	}).Name("test")
+
+	t.Run("trusted", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Forwarded-For", "198.51.100.1")
+		req.Header.Set("X-Forwarded-Proto", "https")
+		req.Header.Set("X-Forwarded-Host", "example.org")
+		recorder := httptest.NewRecorder()
+		server.Router().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Equal(t, "198.51.100.1", request.RemoteAddress())
+		assert.Equal(t, "https", request.Scheme())
+		assert.Equal(t, "example.org", request.Host())
+		assert.Equal(t, "https://example.org/app/test", url)
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Host = "evil.org"
+		req.Header.Set("X-Forwarded-For", "198.51.100.1")
+		req.Header.Set("X-Forwarded-Proto", "https")
+		req.Header.Set("X-Forwarded-Host", "example.org")
+		recorder := httptest.NewRecorder()
+		server.Router().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Equal(t, "192.0.2.1:1234", request.RemoteAddress())
+		assert.Equal(t, "http", request.Scheme())
+		assert.Equal(t, "evil.org", request.Host())
+		assert.Equal(t, server.ProxyBaseURL()+"/test", url)
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.TLS = &tls.ConnectionState{}
+		r := NewRequest(req)
+		assert.Equal(t, "https", r.Scheme())
+	})
+
+	t.Run("scheme_redirect", func(t *testing.T) {
+		server := newTrustedProxiesServer(t, "10.0.0.0/8")
+		server.config.Set("server.proxy.host", "example.org")
+		server.config.Set("server.proxy.protocol", "https")
+		server.config.Set("server.proxy.port", 443)
+		server.Router().Get("/test", func(response *Response, _ *Request) {
+			response.Status(http.StatusNoContent)
+		})
+
+		serve := func(scheme, remoteAddr string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/test?a=b", nil)
+			req.RemoteAddr = remoteAddr
+			if scheme != "" {
+				req.Header.Set("X-Forwarded-Proto", scheme)
+			}
+			recorder := httptest.NewRecorder()
+			server.Router().ServeHTTP(recorder, req)
+			return recorder
+		}
+
+		recorder := serve("http", "10.0.0.1:1234")
+		assert.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+		assert.Equal(t, "https://example.org/test?a=b", recorder.Header().Get("Location"))
+
+		assert.Equal(t, http.StatusNoContent, serve("https", "10.0.0.1:1234").Code)
+		assert.Equal(t, http.StatusNoContent, serve("", "10.0.0.1:1234").Code)
+		// Untrusted proxies cannot trigger the redirect
+		assert.Equal(t, http.StatusNoContent, serve("http", "192.0.2.1:1234").Code)
+	})
+}
@@ -0,0 +1,242 @@
+package goyave
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+var (
+	proxyProtocolV1Prefix    = []byte("PROXY ")
+	proxyProtocolV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+const (
+	// proxyProtocolV1MaxLength the maximum length of a v1 header, including the CRLF.
+	proxyProtocolV1MaxLength = 107
+
+	proxyProtocolV2HeaderLength = 16
+)
+
+// proxyProtocolListener wraps a listener to decode the PROXY protocol (v1 and v2)
+// header sent by load balancers at the start of each connection.
+//
+// Headers are only accepted from connections whose peer address is contained in one
+// of the allowed sources. Other connections are left untouched, so a client
+// cannot spoof its address by sending a header itself.
+type proxyProtocolListener struct {
+	net.Listener
+	allowedSources []netip.Prefix
+	headerTimeout  time.Duration
+}
+
+// Accept waits for and returns the next connection. The PROXY header is not
+// read here so a slow client cannot block the accept loop. It is read
+// on the first call to `RemoteAddr()`, `LocalAddr()` or `Read()`.
+func (l *proxyProtocolListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if !prefixesContain(l.allowedSources, conn.RemoteAddr().String()) {
+		return conn, nil
+	}
+	return &proxyProtocolConn{
+		Conn:          conn,
+		reader:        bufio.NewReader(conn),
+		headerTimeout: l.headerTimeout,
+	}, nil
+}
+
+// proxyProtocolConn a connection from an allowed source, which may start with a PROXY header.
+type proxyProtocolConn struct {
+	net.Conn
+	reader        *bufio.Reader
+	remoteAddr    net.Addr
+	localAddr     net.Addr
+	err           error
+	once          sync.Once
+	headerTimeout time.Duration
+}
+
+func (c *proxyProtocolConn) readHeader() {
+	c.once.Do(func() {
+		if c.headerTimeout > 0 {
+			_ = c.Conn.SetReadDeadline(time.Now().Add(c.headerTimeout))
+			defer func() { _ = c.Conn.SetReadDeadline(time.Time{}) }()
+		}
+		c.remoteAddr, c.localAddr, c.err = readProxyProtocolHeader(c.reader)
+		if c.err != nil {
+			// The connection cannot be used anymore as the header was malformed.
+			_ = c.Conn.Close()
+		}
+	})
+}
+
+// Read reads data from the connection, after the PROXY header.
+func (c *proxyProtocolConn) Read(b []byte) (int, error) {
+	c.readHeader()
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.reader.Read(b)
+}
+
+// RemoteAddr returns the client address decoded from the PROXY header, or
+// the address of the peer if there is no header or if it doesn't contain an address.
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	c.readHeader()
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr returns the destination address decoded from the PROXY header, or
+// the local address if there is no header or if it doesn't contain an address.
+func (c *proxyProtocolConn) LocalAddr() net.Addr {
+	c.readHeader()
+	if c.localAddr != nil {
+		return c.localAddr
+	}
+	return c.Conn.LocalAddr()
+}
+
+// readProxyProtocolHeader reads a PROXY protocol v1 or v2 header if there is one.
+// Returns nil addresses if there is no header or if it doesn't contain addresses
+// ("UNKNOWN" in v1, "LOCAL" command or unsupported family in v2).
+func readProxyProtocolHeader(r *bufio.Reader) (net.Addr, net.Addr, error) {
+	first, err := r.Peek(1)
+	if err != nil {
+		return nil, nil, nil // Let the HTTP server handle the read error
+	}
+	switch first[0] {
+	case proxyProtocolV1Prefix[0]:
+		prefix, err := r.Peek(len(proxyProtocolV1Prefix))
+		if err != nil || !bytes.Equal(prefix, proxyProtocolV1Prefix) {
+			return nil, nil, nil
+		}
+		return readProxyProtocolV1(r)
+	case proxyProtocolV2Signature[0]:
+		signature, err := r.Peek(len(proxyProtocolV2Signature))
+		if err != nil || !bytes.Equal(signature, proxyProtocolV2Signature) {
+			return nil, nil, nil
+		}
+		return readProxyProtocolV2(r)
+	}
+	return nil, nil, nil
+}
+
+func readProxyProtocolV1(r *bufio.Reader) (net.Addr, net.Addr, error) {
+	line := make([]byte, 0, proxyProtocolV1MaxLength)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, errors.Errorf("proxy protocol: could not read v1 header: %w", err)
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+		if len(line) >= proxyProtocolV1MaxLength {
+			return nil, nil, errors.New("proxy protocol: v1 header too long")
+		}
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, nil, errors.New("proxy protocol: v1 header must end with CRLF")
+	}
+
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, nil, errors.Errorf("proxy protocol: malformed v1 header %q", string(line))
+	}
+
+	src, err := parseProxyProtocolV1Addr(fields[2], fields[4], fields[1] == "TCP4")
+	if err != nil {
+		return nil, nil, err
+	}
+	dst, err := parseProxyProtocolV1Addr(fields[3], fields[5], fields[1] == "TCP4")
+	if err != nil {
+		return nil, nil, err
+	}
+	return src, dst, nil
+}
+
+func parseProxyProtocolV1Addr(ip, port string, v4 bool) (*net.TCPAddr, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil || addr.Is4() != v4 {
+		return nil, errors.Errorf("proxy protocol: invalid v1 address %q", ip)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, errors.Errorf("proxy protocol: invalid v1 port %q", port)
+	}
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(addr, uint16(p))), nil
+}
+
+func readProxyProtocolV2(r *bufio.Reader) (net.Addr, net.Addr, error) {
+	header := make([]byte, proxyProtocolV2HeaderLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, nil, errors.Errorf("proxy protocol: could not read v2 header: %w", err)
+	}
+	versionCommand := header[12]
+	family := header[13]
+	length := binary.BigEndian.Uint16(header[14:16])
+
+	if versionCommand>>4 != 2 {
+		return nil, nil, errors.Errorf("proxy protocol: unsupported version %d", versionCommand>>4)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, nil, errors.Errorf("proxy protocol: could not read v2 addresses: %w", err)
+	}
+
+	switch versionCommand & 0x0F {
+	case 0x0: // LOCAL: health checks from the proxy itself
+		return nil, nil, nil
+	case 0x1: // PROXY
+	default:
+		return nil, nil, errors.Errorf("proxy protocol: unsupported command %d", versionCommand&0x0F)
+	}
+
+	var ipLength int
+	switch family >> 4 {
+	case 0x1: // AF_INET
+		ipLength = 4
+	case 0x2: // AF_INET6
+		ipLength = 16
+	default: // AF_UNSPEC, AF_UNIX
+		return nil, nil, nil
+	}
+	if len(payload) < ipLength*2+4 {
+		return nil, nil, errors.New("proxy protocol: v2 address block too short")
+	}
+
+	srcIP, _ := netip.AddrFromSlice(payload[:ipLength])
+	dstIP, _ := netip.AddrFromSlice(payload[ipLength : ipLength*2])
+	srcPort := binary.BigEndian.Uint16(payload[ipLength*2:])
+	dstPort := binary.BigEndian.Uint16(payload[ipLength*2+2:])
+
+	var src, dst net.Addr
+	if family&0x0F == 0x2 { // DGRAM
+		src = net.UDPAddrFromAddrPort(netip.AddrPortFrom(srcIP.Unmap(), srcPort))
+		dst = net.UDPAddrFromAddrPort(netip.AddrPortFrom(dstIP.Unmap(), dstPort))
+	} else {
+		src = net.TCPAddrFromAddrPort(netip.AddrPortFrom(srcIP.Unmap(), srcPort))
+		dst = net.TCPAddrFromAddrPort(netip.AddrPortFrom(dstIP.Unmap(), dstPort))
+	}
+	return src, dst, nil
+}
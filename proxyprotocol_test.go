@@ -0,0 +1,221 @@
+package goyave
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/config"
+)
+
+func proxyProtocolV2Header(command, family byte, addresses []byte) []byte {
+	header := append([]byte{}, proxyProtocolV2Signature...)
+	header = append(header, 0x20|command, family)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(addresses)))
+	return append(header, addresses...)
+}
+
+func TestReadProxyProtocolHeader(t *testing.T) {
+	ipv4Addresses := []byte{198, 51, 100, 1, 192, 0, 2, 1, 0x30, 0x39, 0x00, 0x50}
+	ipv6Addresses := append(append(netip.MustParseAddr("2001:db8::1").AsSlice(), netip.MustParseAddr("2001:db8::2").AsSlice()...), 0x30, 0x39, 0x01, 0xbb)
+
+	cases := []struct {
+		desc        string
+		input       []byte
+		wantSrc     string
+		wantDst     string
+		wantRemains string
+		wantErr     bool
+	}{
+		{desc: "no_header", input: []byte("GET / HTTP/1.1\r\n"), wantRemains: "GET / HTTP/1.1\r\n"},
+		{desc: "no_header_P", input: []byte("POST / HTTP/1.1\r\n"), wantRemains: "POST / HTTP/1.1\r\n"},
+		{desc: "empty", input: []byte{}},
+		{desc: "v1_tcp4", input: []byte("PROXY TCP4 198.51.100.1 192.0.2.1 12345 80\r\nGET"), wantSrc: "198.51.100.1:12345", wantDst: "192.0.2.1:80", wantRemains: "GET"},
+		{desc: "v1_tcp6", input: []byte("PROXY TCP6 2001:db8::1 2001:db8::2 12345 443\r\nGET"), wantSrc: "[2001:db8::1]:12345", wantDst: "[2001:db8::2]:443", wantRemains: "GET"},
+		{desc: "v1_unknown", input: []byte("PROXY UNKNOWN\r\nGET"), wantRemains: "GET"},
+		{desc: "v1_family_mismatch", input: []byte("PROXY TCP4 2001:db8::1 192.0.2.1 12345 80\r\n"), wantErr: true},
+		{desc: "v1_invalid_port", input: []byte("PROXY TCP4 198.51.100.1 192.0.2.1 99999 80\r\n"), wantErr: true},
+		{desc: "v1_malformed", input: []byte("PROXY TCP4 198.51.100.1\r\n"), wantErr: true},
+		{desc: "v1_no_crlf", input: []byte("PROXY TCP4 198.51.100.1 192.0.2.1 12345 80\n"), wantErr: true},
+		{desc: "v1_too_long", input: []byte("PROXY " + strings.Repeat("A", 200)), wantErr: true},
+		{desc: "v1_truncated", input: []byte("PROXY TCP4"), wantErr: true},
+		{desc: "v2_tcp4", input: append(proxyProtocolV2Header(0x1, 0x11, ipv4Addresses), "GET"...), wantSrc: "198.51.100.1:12345", wantDst: "192.0.2.1:80", wantRemains: "GET"},
+		{desc: "v2_tcp6", input: append(proxyProtocolV2Header(0x1, 0x21, ipv6Addresses), "GET"...), wantSrc: "[2001:db8::1]:12345", wantDst: "[2001:db8::2]:443", wantRemains: "GET"},
+		{desc: "v2_udp4", input: proxyProtocolV2Header(0x1, 0x12, ipv4Addresses), wantSrc: "198.51.100.1:12345", wantDst: "192.0.2.1:80"},
+		{desc: "v2_tlvs", input: append(proxyProtocolV2Header(0x1, 0x11, append(append([]byte{}, ipv4Addresses...), 0x01, 0x00, 0x02, 'h', '2')), "GET"...), wantSrc: "198.51.100.1:12345", wantDst: "192.0.2.1:80", wantRemains: "GET"},
+		{desc: "v2_local", input: append(proxyProtocolV2Header(0x0, 0x00, nil), "GET"...), wantRemains: "GET"},
+		{desc: "v2_unspec", input: append(proxyProtocolV2Header(0x1, 0x00, nil), "GET"...), wantRemains: "GET"},
+		{desc: "v2_unsupported_command", input: proxyProtocolV2Header(0x2, 0x11, ipv4Addresses), wantErr: true},
+		{desc: "v2_unsupported_version", input: append(append([]byte{}, proxyProtocolV2Signature...), 0x31, 0x11, 0, 0), wantErr: true},
+		{desc: "v2_short_addresses", input: proxyProtocolV2Header(0x1, 0x11, ipv4Addresses[:6]), wantErr: true},
+		{desc: "v2_truncated", input: proxyProtocolV2Header(0x1, 0x11, ipv4Addresses)[:20], wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			r := bufio.NewReader(bytes.NewReader(c.input))
+			src, dst, err := readProxyProtocolHeader(r)
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if c.wantSrc == "" {
+				assert.Nil(t, src)
+				assert.Nil(t, dst)
+			} else {
+				require.NotNil(t, src)
+				require.NotNil(t, dst)
+				assert.Equal(t, c.wantSrc, src.String())
+				assert.Equal(t, c.wantDst, dst.String())
+			}
+			remains, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, c.wantRemains, string(remains))
+		})
+	}
+}
+
+func TestProxyProtocolListener(t *testing.T) {
+	listen := func(t *testing.T, allowed ...string) *proxyProtocolListener {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+		prefixes, err := parsePrefixes(allowed)
+		require.NoError(t, err)
+		return &proxyProtocolListener{Listener: ln, allowedSources: prefixes, headerTimeout: time.Second}
+	}
+
+	exchange := func(t *testing.T, ln net.Listener, payload string) (net.Conn, string) {
+		client, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = client.Close() })
+		_, err = client.Write([]byte(payload))
+		require.NoError(t, err)
+
+		conn, err := ln.Accept()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close() })
+		remoteAddr := conn.RemoteAddr().String()
+		buf := make([]byte, 3)
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "GET", string(buf))
+		return conn, remoteAddr
+	}
+
+	t.Run("allowed_source", func(t *testing.T) {
+		ln := listen(t, "127.0.0.1")
+		conn, remoteAddr := exchange(t, ln, "PROXY TCP4 198.51.100.1 192.0.2.1 12345 80\r\nGET")
+		assert.Equal(t, "198.51.100.1:12345", remoteAddr)
+		assert.Equal(t, "192.0.2.1:80", conn.LocalAddr().String())
+	})
+
+	t.Run("allowed_source_without_header", func(t *testing.T) {
+		ln := listen(t, "127.0.0.1")
+		_, remoteAddr := exchange(t, ln, "GET")
+		assert.True(t, strings.HasPrefix(remoteAddr, "127.0.0.1:"))
+	})
+
+	t.Run("untrusted_source", func(t *testing.T) {
+		ln := listen(t, "10.0.0.0/8")
+		client, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		defer func() { _ = client.Close() }()
+		_, err = client.Write([]byte("PROXY TCP4 198.51.100.1 192.0.2.1 12345 80\r\n"))
+		require.NoError(t, err)
+
+		conn, err := ln.Accept()
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+		assert.True(t, strings.HasPrefix(conn.RemoteAddr().String(), "127.0.0.1:"))
+		buf := make([]byte, 6)
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "PROXY ", string(buf)) // Header left untouched
+	})
+
+	t.Run("malformed_header", func(t *testing.T) {
+		ln := listen(t, "127.0.0.1")
+		client, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		defer func() { _ = client.Close() }()
+		_, err = client.Write([]byte("PROXY TCP4 invalid\r\nGET"))
+		require.NoError(t, err)
+
+		conn, err := ln.Accept()
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(conn.RemoteAddr().String(), "127.0.0.1:"))
+		_, err = conn.Read(make([]byte, 3))
+		require.Error(t, err)
+	})
+
+	t.Run("header_timeout", func(t *testing.T) {
+		ln := listen(t, "127.0.0.1")
+		ln.headerTimeout = 50 * time.Millisecond
+		client, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		defer func() { _ = client.Close() }()
+		_, err = client.Write([]byte("PROXY TCP4"))
+		require.NoError(t, err)
+
+		conn, err := ln.Accept()
+		require.NoError(t, err)
+		_, err = conn.Read(make([]byte, 3))
+		require.Error(t, err)
+	})
+}
+
+func TestServerProxyProtocol(t *testing.T) {
+	cfg := config.LoadDefault()
+	cfg.Set("server.port", 0)
+	cfg.Set("server.proxyProtocol.enabled", true)
+	cfg.Set("server.proxyProtocol.allowedSources", []string{"127.0.0.1/32"})
+	server, err := New(Options{Config: cfg})
+	require.NoError(t, err)
+
+	server.RegisterRoutes(func(_ *Server, router *Router) {
+		router.Get("/", func(response *Response, request *Request) {
+			response.String(http.StatusOK, request.RemoteAddress())
+		})
+	})
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	server.RegisterStartupHook(func(s *Server) {
+		defer wg.Done()
+		defer s.Stop()
+		conn, err := net.Dial("tcp", "127.0.0.1:"+strconv.Itoa(s.Port()))
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_, err = conn.Write([]byte("PROXY TCP4 198.51.100.1 192.0.2.1 12345 80\r\nGET / HTTP/1.1\r\nHost: example.org\r\nConnection: close\r\n\r\n"))
+		assert.NoError(t, err)
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+		assert.Equal(t, "198.51.100.1:12345", string(body))
+	})
+
+	go func() {
+		assert.NoError(t, server.Start())
+		wg.Done()
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,147 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// State the persisted state of a rate limit for a single key.
+// Its meaning depends on the `Algorithm`.
+type State struct {
+	// Time the last refill for the token bucket, the start of
+	// the current window for the sliding window.
+	Time time.Time
+
+	// Value the number of available tokens for the token bucket,
+	// the number of requests in the current window for the sliding window.
+	Value float64
+
+	// Previous the number of requests in the previous window for the sliding window.
+	// Unused by the token bucket.
+	Previous float64
+}
+
+// Result the outcome of an attempt to consume a request from a rate limit.
+type Result struct {
+	// Limit the maximum number of requests in the limit's window.
+	Limit int
+
+	// Remaining the number of requests that can still be made.
+	Remaining int
+
+	// Reset the time after which the quota is fully restored.
+	Reset time.Duration
+
+	// RetryAfter the time after which the client can retry if the request is not allowed.
+	RetryAfter time.Duration
+
+	// Allowed true if the request is allowed.
+	Allowed bool
+}
+
+// Algorithm a rate limiting algorithm. Implementations update the given
+// state to consume one request and must not keep any other state.
+type Algorithm interface {
+	// Take attempts to consume one request. The state is zero-valued if
+	// the key has never been seen before. The state is updated in-place.
+	Take(state *State, limit *Limit, now time.Time) Result
+
+	// Name returns a unique name identifying the algorithm.
+	Name() string
+}
+
+// TokenBucket the token bucket algorithm. The bucket holds at most `Limit.Requests` tokens
+// and is refilled continuously at a rate of `Limit.Requests` tokens per `Limit.Window`.
+// Each request consumes one token. This algorithm allows bursts of requests
+// up to the capacity of the bucket.
+type TokenBucket struct{}
+
+// Name returns "token-bucket".
+func (TokenBucket) Name() string {
+	return "token-bucket"
+}
+
+// Take consumes a token from the bucket.
+func (TokenBucket) Take(state *State, limit *Limit, now time.Time) Result {
+	capacity := float64(limit.Requests)
+	rate := capacity / float64(limit.Window) // Tokens per nanosecond
+
+	if state.Time.IsZero() {
+		state.Value = capacity
+	} else if elapsed := now.Sub(state.Time); elapsed > 0 {
+		state.Value = math.Min(capacity, state.Value+float64(elapsed)*rate)
+	}
+	state.Time = now
+
+	result := Result{Limit: limit.Requests}
+	if state.Value >= 1 {
+		state.Value--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - state.Value) / rate))
+	}
+	result.Remaining = int(state.Value)
+	result.Reset = time.Duration(math.Ceil((capacity - state.Value) / rate))
+	return result
+}
+
+// SlidingWindow the sliding window counter algorithm. At most `Limit.Requests` requests
+// are allowed in any period of `Limit.Window`. The number of requests in the sliding window
+// is approximated by weighting the count of the previous fixed window by the portion of
+// it still covered by the sliding window.
+type SlidingWindow struct{}
+
+// Name returns "sliding-window".
+func (SlidingWindow) Name() string {
+	return "sliding-window"
+}
+
+// Take counts a request in the current window.
+func (SlidingWindow) Take(state *State, limit *Limit, now time.Time) Result {
+	windowStart := now.Truncate(limit.Window)
+	switch {
+	case state.Time.Equal(windowStart):
+	case state.Time.Add(limit.Window).Equal(windowStart):
+		state.Previous = state.Value
+		state.Value = 0
+	default:
+		state.Previous = 0
+		state.Value = 0
+	}
+	state.Time = windowStart
+
+	elapsed := now.Sub(windowStart)
+	weight := float64(limit.Window-elapsed) / float64(limit.Window)
+	count := state.Previous*weight + state.Value
+
+	result := Result{Limit: limit.Requests}
+	if count+1 <= float64(limit.Requests) {
+		state.Value++
+		count++
+		result.Allowed = true
+	} else {
+		result.RetryAfter = slidingWindowRetryAfter(state, limit, elapsed)
+	}
+	result.Remaining = max(0, int(float64(limit.Requests)-count))
+	result.Reset = limit.Window - elapsed
+	if state.Value > 0 {
+		// Requests of the current window still count until the end of the next one.
+		result.Reset += limit.Window
+	}
+	return result
+}
+
+// slidingWindowRetryAfter computes the time after which the weighted count will
+// be low enough to allow a new request.
+func slidingWindowRetryAfter(state *State, limit *Limit, elapsed time.Duration) time.Duration {
+	window := float64(limit.Window)
+	requests := float64(limit.Requests)
+	if state.Previous > 0 && state.Value+1 <= requests {
+		// Waiting in the current window: Previous * (Window - t) / Window + Value + 1 <= Requests
+		t := window * (1 - (requests-state.Value-1)/state.Previous)
+		return time.Duration(math.Ceil(t)) - elapsed
+	}
+	// Waiting in the next window, where the current window becomes the previous one.
+	t := window * (1 - (requests-1)/state.Value)
+	return limit.Window - elapsed + time.Duration(math.Ceil(math.Max(t, 0)))
+}
@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	limit := &Limit{Requests: 3, Window: 3 * time.Second}
+	state := &State{}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	algo := TokenBucket{}
+	assert.Equal(t, "token-bucket", algo.Name())
+
+	for i := 2; i >= 0; i-- {
+		result := algo.Take(state, limit, now)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, i, result.Remaining)
+		assert.Equal(t, time.Duration(3-i)*time.Second, result.Reset)
+	}
+
+	result := algo.Take(state, limit, now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.Reset)
+
+	// Refill
+	now = now.Add(1500 * time.Millisecond)
+	result = algo.Take(state, limit, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.InDelta(t, 0.5, state.Value, 0.0001)
+
+	result = algo.Take(state, limit, now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	// Never exceeds capacity
+	now = now.Add(time.Hour)
+	result = algo.Take(state, limit, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	limit := &Limit{Requests: 4, Window: 10 * time.Second}
+	state := &State{}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	algo := SlidingWindow{}
+	assert.Equal(t, "sliding-window", algo.Name())
+
+	now := start.Add(5 * time.Second)
+	for i := 3; i >= 0; i-- {
+		result := algo.Take(state, limit, now)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 4, result.Limit)
+		assert.Equal(t, i, result.Remaining)
+		assert.Equal(t, 15*time.Second, result.Reset)
+	}
+
+	result := algo.Take(state, limit, now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	// Next window, count = 4 * (10 - t) / 10 <= 3 => t >= 2.5s
+	assert.Equal(t, 7500*time.Millisecond, result.RetryAfter)
+
+	// Next window: previous window still weighs 4 * 0.75 = 3
+	now = start.Add(12500 * time.Millisecond)
+	result = algo.Take(state, limit, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.InDelta(t, 4, state.Previous, 0)
+	assert.InDelta(t, 1, state.Value, 0)
+
+	// 4 * (10 - t) / 10 + 1 + 1 <= 4 => t >= 5s
+	result = algo.Take(state, limit, now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 2500*time.Millisecond, result.RetryAfter)
+
+	// Windows without requests reset the state
+	now = start.Add(time.Minute)
+	result = algo.Take(state, limit, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 3, result.Remaining)
+	assert.InDelta(t, 0, state.Previous, 0)
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// Model the database representation of a rate limit state used by the `GormStore`.
+type Model struct {
+	Time      time.Time
+	ExpiresAt time.Time `gorm:"index"`
+	ID        string    `gorm:"primaryKey;size:255"`
+	Value     float64
+	Previous  float64
+}
+
+// TableName returns the default table name for the rate limits: "rate_limits".
+func (Model) TableName() string {
+	return "rate_limits"
+}
+
+// GormStore a rate limit `Store` keeping the states in a database table, allowing
+// multiple instances of the application to share the same budget.
+// Each `Take` is executed in a transaction locking the row of the key.
+//
+// The table can be created using the `Model` structure with auto-migrations.
+type GormStore struct {
+	DB *gorm.DB
+
+	// Table the name of the table storing the states.
+	// If empty, the default "rate_limits" is used.
+	Table string
+}
+
+// NewGormStore create a new `GormStore` using the given database.
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{DB: db}
+}
+
+func (s *GormStore) table(db *gorm.DB) *gorm.DB {
+	if s.Table != "" {
+		return db.Table(s.Table)
+	}
+	return db.Model(&Model{})
+}
+
+// Take attempts to consume one request for the given key.
+//
+// The row of the key is created first if it doesn't exist yet, so it can be locked
+// even for the first request: concurrent requests on a new key cannot all see
+// an empty state.
+func (s *GormStore) Take(ctx context.Context, key string, limit *Limit, now time.Time) (Result, error) {
+	var result Result
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The zero state is expired, it is reset below.
+		err := s.table(tx).Clauses(clause.OnConflict{DoNothing: true}).Create(&Model{ID: key}).Error
+		if err != nil {
+			return err
+		}
+
+		m := &Model{}
+		if err := s.table(tx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", key).Take(m).Error; err != nil {
+			return err
+		}
+		if !m.ExpiresAt.After(now) {
+			m = &Model{ID: key}
+		}
+
+		state := State{Time: m.Time, Value: m.Value, Previous: m.Previous}
+		result = limit.algorithm().Take(&state, limit, now)
+
+		return s.table(tx).Where("id = ?", key).Updates(map[string]any{
+			"time":       state.Time,
+			"expires_at": now.Add(result.Reset),
+			"value":      state.Value,
+			"previous":   state.Previous,
+		}).Error
+	})
+	return result, errors.New(err)
+}
+
+// GC deletes the states of all keys having their quota fully restored.
+func (s *GormStore) GC(ctx context.Context) error {
+	return errors.New(s.table(s.DB.WithContext(ctx)).Where("expires_at <= ?", time.Now()).Delete(&Model{}).Error)
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+
+	_ "goyave.dev/goyave/v5/database/dialect/sqlite"
+)
+
+func TestGormStore(t *testing.T) {
+	cfg := config.LoadDefault()
+	cfg.Set("database.connection", "sqlite3")
+	cfg.Set("database.name", "testratelimitgormstore.db")
+	cfg.Set("database.options", "mode=memory")
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+	require.NoError(t, server.DB().AutoMigrate(&Model{}))
+
+	store := NewGormStore(server.DB())
+	testStore(t, store)
+
+	t.Run("GC", func(t *testing.T) {
+		limit := &Limit{Requests: 2, Window: time.Minute}
+		_, err := store.Take(context.Background(), "expired", limit, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, store.GC(context.Background()))
+
+		var count int64
+		require.NoError(t, server.DB().Model(&Model{}).Where("id = ?", "expired").Count(&count).Error)
+		assert.Zero(t, count)
+		require.NoError(t, server.DB().Model(&Model{}).Where("id = ?", "a").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("concurrent_new_key", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("database.connection", "sqlite3")
+		cfg.Set("database.name", filepath.Join(t.TempDir(), "concurrent.db"))
+		cfg.Set("database.options", "_busy_timeout=10000")
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+		require.NoError(t, server.DB().AutoMigrate(&Model{}))
+		store := NewGormStore(server.DB())
+
+		limit := &Limit{Requests: 5, Window: time.Hour}
+		now := time.Now()
+		allowed := 0
+		mu := sync.Mutex{}
+		wg := sync.WaitGroup{}
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := store.Take(context.Background(), "new_key", limit, now)
+				assert.NoError(t, err)
+				if result.Allowed {
+					mu.Lock()
+					allowed++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 5, allowed)
+
+		result, err := store.Take(context.Background(), "new_key", limit, now)
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+	})
+
+	t.Run("custom_table", func(t *testing.T) {
+		require.NoError(t, server.DB().Table("custom_rate_limits").AutoMigrate(&Model{}))
+		store := &GormStore{DB: server.DB(), Table: "custom_rate_limits"}
+		_, err := store.Take(context.Background(), "key", &Limit{Requests: 2, Window: time.Minute}, time.Now())
+		require.NoError(t, err)
+
+		var count int64
+		require.NoError(t, server.DB().Table("custom_rate_limits").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+}
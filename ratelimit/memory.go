@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// DefaultShards the default number of shards of the `MemoryStore`.
+const DefaultShards = 32
+
+type memoryEntry struct {
+	state     State
+	expiresAt time.Time
+}
+
+type shard struct {
+	entries map[string]*memoryEntry
+	mu      sync.Mutex
+}
+
+// MemoryStore a rate limit `Store` keeping the states in memory. The keys are spread
+// in multiple shards, each protected by its own lock to reduce contention.
+// The states are not shared between multiple instances of the application.
+//
+// Idle states are removed when calling `GC`.
+type MemoryStore struct {
+	shards []*shard
+}
+
+// NewMemoryStore create a new `MemoryStore` with the given number of shards.
+// If the number of shards is lower than 1, `DefaultShards` is used.
+func NewMemoryStore(shards int) *MemoryStore {
+	if shards < 1 {
+		shards = DefaultShards
+	}
+	s := &MemoryStore{shards: make([]*shard, shards)}
+	for i := range s.shards {
+		s.shards[i] = &shard{entries: make(map[string]*memoryEntry)}
+	}
+	return s
+}
+
+func (s *MemoryStore) shard(key string) *shard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// Take attempts to consume one request for the given key.
+func (s *MemoryStore) Take(_ context.Context, key string, limit *Limit, now time.Time) (Result, error) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	entry, ok := sh.entries[key]
+	if !ok || !entry.expiresAt.After(now) {
+		entry = &memoryEntry{}
+		sh.entries[key] = entry
+	}
+	result := limit.algorithm().Take(&entry.state, limit, now)
+	entry.expiresAt = now.Add(result.Reset)
+	return result, nil
+}
+
+// GC removes the states of all keys having their quota fully restored.
+func (s *MemoryStore) GC(_ context.Context) error {
+	now := time.Now()
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		for key, entry := range sh.entries {
+			if !entry.expiresAt.After(now) {
+				delete(sh.entries, key)
+			}
+		}
+		sh.mu.Unlock()
+	}
+	return nil
+}
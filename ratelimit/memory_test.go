@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	limit := &Limit{Requests: 2, Window: time.Minute}
+	now := time.Now()
+
+	for i := 1; i >= 0; i-- {
+		result, err := store.Take(ctx, "a", limit, now)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+	result, err := store.Take(ctx, "a", limit, now)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+
+	// Other keys are independent
+	result, err = store.Take(ctx, "b", limit, now)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// Expired state is reset
+	result, err = store.Take(ctx, "a", limit, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+}
+
+func TestMemoryStore(t *testing.T) {
+	t.Run("Take", func(t *testing.T) {
+		testStore(t, NewMemoryStore(4))
+	})
+
+	t.Run("shards", func(t *testing.T) {
+		assert.Len(t, NewMemoryStore(0).shards, DefaultShards)
+		assert.Len(t, NewMemoryStore(8).shards, 8)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		store := NewMemoryStore(4)
+		limit := &Limit{Requests: 50, Window: time.Hour}
+		now := time.Now()
+		allowed := 0
+		mu := sync.Mutex{}
+		wg := sync.WaitGroup{}
+		for range 100 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := store.Take(context.Background(), "key", limit, now)
+				assert.NoError(t, err)
+				if result.Allowed {
+					mu.Lock()
+					allowed++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 50, allowed)
+	})
+
+	t.Run("GC", func(t *testing.T) {
+		store := NewMemoryStore(1)
+		limit := &Limit{Requests: 2, Window: time.Minute}
+		_, err := store.Take(context.Background(), "expired", limit, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		_, err = store.Take(context.Background(), "valid", limit, time.Now())
+		require.NoError(t, err)
+
+		require.NoError(t, store.GC(context.Background()))
+		assert.NotContains(t, store.shards[0].entries, "expired")
+		assert.Contains(t, store.shards[0].entries, "valid")
+	})
+}
@@ -0,0 +1,202 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// MetaLimit the meta key used to define the `*Limit` applied to a router or a route.
+// Setting this meta to a `nil` `*Limit` disables rate limiting for the router or route.
+const MetaLimit = "goyave.ratelimit"
+
+// KeyFunc returns the key identifying the client of the given request.
+// Clients identified by the same key share the same budget.
+type KeyFunc func(request *goyave.Request) string
+
+// Limit defines the maximum number of requests allowed in a period of time.
+type Limit struct {
+	// Algorithm the rate limiting algorithm. Defaults to `TokenBucket`.
+	Algorithm Algorithm
+
+	// Key the function identifying the client. If `nil`, the middleware's
+	// `Key` function is used.
+	Key KeyFunc
+
+	// Name the scope of the limit. Limits with the same name share
+	// their budget if their keys are the same. If empty, the name is generated
+	// from the algorithm and the limit's parameters.
+	Name string
+
+	// Requests the maximum number of requests allowed in the window.
+	Requests int
+
+	// Window the period of time.
+	Window time.Duration
+}
+
+func (l *Limit) algorithm() Algorithm {
+	if l.Algorithm == nil {
+		return TokenBucket{}
+	}
+	return l.Algorithm
+}
+
+func (l *Limit) name() string {
+	if l.Name != "" {
+		return l.Name
+	}
+	return fmt.Sprintf("%s:%d:%s", l.algorithm().Name(), l.Requests, l.Window)
+}
+
+// Middleware limits the rate of requests. The `Limit` applied is the one defined by
+// the `MetaLimit` meta of the matched route (or its parent routers), or the middleware's
+// `Limit` if there is no such meta.
+//
+// The response contains the following headers:
+//   - `RateLimit-Limit`: the maximum number of requests in the window
+//   - `RateLimit-Remaining`: the number of requests that can still be made
+//   - `RateLimit-Reset`: the number of seconds until the quota is fully restored
+//   - `RateLimit-Policy`: the limit policy (e.g. "100;w=60")
+//
+// If the request exceeds the limit, the "Retry-After" header is set and
+// the middleware responds with "429 Too Many Requests", executing the status handler.
+type Middleware struct {
+	goyave.Component
+
+	// Store the storage of the rate limit states.
+	Store Store
+
+	// Limit the default limit applied if the route doesn't have the `MetaLimit` meta.
+	// If `nil`, requests to routes without the meta are not limited.
+	Limit *Limit
+
+	// Key the default function identifying the client. Defaults to `ByIP`.
+	Key KeyFunc
+}
+
+// New create a new rate limiting middleware using the given store and default limit.
+func New(store Store, limit *Limit) *Middleware {
+	return &Middleware{
+		Store: store,
+		Limit: limit,
+		Key:   ByIP,
+	}
+}
+
+// Handle limits the rate of requests.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		limit := m.Limit
+		if l, ok := request.Route.LookupMeta(MetaLimit); ok {
+			limit, _ = l.(*Limit)
+		}
+		if limit == nil {
+			next(response, request)
+			return
+		}
+
+		result, err := m.Store.Take(request.Context(), limit.name()+":"+m.key(limit, request), limit, request.Now)
+		if err != nil {
+			panic(errors.New(err))
+		}
+
+		header := response.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+		header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, seconds(limit.Window)))
+
+		if !result.Allowed {
+			header.Set("Retry-After", strconv.Itoa(max(1, seconds(result.RetryAfter))))
+			response.Status(http.StatusTooManyRequests)
+			return
+		}
+		next(response, request)
+	}
+}
+
+func (m *Middleware) key(limit *Limit, request *goyave.Request) string {
+	switch {
+	case limit.Key != nil:
+		return limit.Key(request)
+	case m.Key != nil:
+		return m.Key(request)
+	default:
+		return ByIP(request)
+	}
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// ByIP identifies clients by their IP address.
+func ByIP(request *goyave.Request) string {
+	addr := request.RemoteAddress()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// ByUser identifies clients by their authenticated `request.User`. If the user structure
+// has an "ID" field, its value is used. Otherwise, the user is formatted with `fmt.Sprint`.
+// Unauthenticated clients are identified by their IP address.
+func ByUser(request *goyave.Request) string {
+	if request.User == nil {
+		return "ip:" + ByIP(request)
+	}
+	v := reflect.ValueOf(request.User)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		if id := v.FieldByName("ID"); id.IsValid() {
+			return "user:" + fmt.Sprint(id.Interface())
+		}
+	}
+	return "user:" + fmt.Sprint(v.Interface())
+}
+
+// ByRoute identifies clients by the name of the matched route, or its full URI if the
+// route is not named. All clients share the same budget for a route.
+func ByRoute(request *goyave.Request) string {
+	if request.Route == nil {
+		return ""
+	}
+	if name := request.Route.GetName(); name != "" {
+		return name
+	}
+	return request.Route.GetFullURI()
+}
+
+// Combine returns a `KeyFunc` joining the keys returned by all the given functions.
+// For example, `Combine(ByRoute, ByIP)` gives each client its own budget for each route.
+func Combine(keys ...KeyFunc) KeyFunc {
+	return func(request *goyave.Request) string {
+		key := ""
+		for i, k := range keys {
+			if i > 0 {
+				key += "|"
+			}
+			key += k(request)
+		}
+		return key
+	}
+}
+
+// Store persists the rate limit states. Implementations must be safe for concurrent use
+// and execute `Take` atomically for a given key.
+type Store interface {
+	// Take attempts to consume one request for the given key using the limit's algorithm.
+	Take(ctx context.Context, key string, limit *Limit, now time.Time) (Result, error)
+}
@@ -0,0 +1,179 @@
+package ratelimit
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+type testUser struct {
+	Name string
+	ID   uint
+}
+
+func TestMiddleware(t *testing.T) {
+	prepare := func(t *testing.T, limit *Limit) (*testutil.TestServer, *goyave.Router) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		router := server.Router()
+		router.GlobalMiddleware(New(NewMemoryStore(1), limit))
+		handler := func(response *goyave.Response, _ *goyave.Request) {
+			response.String(http.StatusOK, "ok")
+		}
+		router.Get("/default", handler)
+		router.Get("/route", handler).SetMeta(MetaLimit, &Limit{Requests: 1, Window: time.Minute})
+		router.Get("/disabled", handler).SetMeta(MetaLimit, (*Limit)(nil))
+		return server, router
+	}
+
+	request := func(server *testutil.TestServer, uri, remoteAddr string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, uri, nil)
+		req.RemoteAddr = remoteAddr
+		resp := server.TestRequest(req)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	t.Run("headers", func(t *testing.T) {
+		server, _ := prepare(t, &Limit{Requests: 2, Window: time.Minute})
+
+		resp := request(server, "/default", "192.0.2.1:1234")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+		assert.Equal(t, "1", resp.Header.Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", resp.Header.Get("RateLimit-Reset"))
+		assert.Equal(t, "2;w=60", resp.Header.Get("RateLimit-Policy"))
+		assert.Empty(t, resp.Header.Get("Retry-After"))
+
+		resp = request(server, "/default", "192.0.2.1:5678") // Same IP, different port
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+
+		resp = request(server, "/default", "192.0.2.1:1234")
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", resp.Header.Get("Retry-After"))
+
+		resp = request(server, "/default", "192.0.2.2:1234")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("status_handler", func(t *testing.T) {
+		server, _ := prepare(t, &Limit{Requests: 1, Window: time.Minute})
+		request(server, "/default", "192.0.2.1:1234")
+		req := httptest.NewRequest(http.MethodGet, "/default", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		resp := server.TestRequest(req)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "{\"error\":\""+http.StatusText(http.StatusTooManyRequests)+"\"}\n", string(body))
+	})
+
+	t.Run("meta", func(t *testing.T) {
+		server, _ := prepare(t, nil)
+
+		for range 5 {
+			resp := request(server, "/default", "192.0.2.1:1234")
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Empty(t, resp.Header.Get("RateLimit-Limit"))
+		}
+
+		assert.Equal(t, http.StatusOK, request(server, "/route", "192.0.2.1:1234").StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, request(server, "/route", "192.0.2.1:1234").StatusCode)
+	})
+
+	t.Run("meta_disabled", func(t *testing.T) {
+		server, _ := prepare(t, &Limit{Requests: 1, Window: time.Minute})
+		for range 5 {
+			resp := request(server, "/disabled", "192.0.2.1:1234")
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+	})
+
+	t.Run("router_meta", func(t *testing.T) {
+		server, router := prepare(t, nil)
+		router.SetMeta(MetaLimit, &Limit{Requests: 1, Window: time.Minute, Algorithm: SlidingWindow{}})
+		assert.Equal(t, http.StatusOK, request(server, "/default", "192.0.2.1:1234").StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, request(server, "/default", "192.0.2.1:1234").StatusCode)
+	})
+
+	t.Run("limit_key", func(t *testing.T) {
+		limit := &Limit{Requests: 1, Window: time.Minute, Key: ByRoute}
+		server, _ := prepare(t, limit)
+		assert.Equal(t, http.StatusOK, request(server, "/default", "192.0.2.1:1234").StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, request(server, "/default", "192.0.2.2:1234").StatusCode)
+	})
+
+	t.Run("shared_name", func(t *testing.T) {
+		server, router := prepare(t, &Limit{Name: "shared", Requests: 1, Window: time.Minute})
+		router.Get("/other", func(response *goyave.Response, _ *goyave.Request) {
+			response.Status(http.StatusOK)
+		}).SetMeta(MetaLimit, &Limit{Name: "shared", Requests: 1, Window: time.Minute})
+		assert.Equal(t, http.StatusOK, request(server, "/default", "192.0.2.1:1234").StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, request(server, "/other", "192.0.2.1:1234").StatusCode)
+	})
+}
+
+func TestKeyFuncs(t *testing.T) {
+	t.Run("ByIP", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodGet, "/", nil)
+		request.Request().RemoteAddr = "192.0.2.1:1234"
+		assert.Equal(t, "192.0.2.1", ByIP(request))
+		request.Request().RemoteAddr = "[2001:db8::1]:1234"
+		assert.Equal(t, "2001:db8::1", ByIP(request))
+		request.Request().RemoteAddr = "192.0.2.1"
+		assert.Equal(t, "192.0.2.1", ByIP(request))
+	})
+
+	t.Run("ByUser", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodGet, "/", nil)
+		request.Request().RemoteAddr = "192.0.2.1:1234"
+		assert.Equal(t, "ip:192.0.2.1", ByUser(request))
+
+		request.User = &testUser{ID: 12, Name: "johndoe"}
+		assert.Equal(t, "user:12", ByUser(request))
+
+		request.User = testUser{ID: 12, Name: "johndoe"}
+		assert.Equal(t, "user:12", ByUser(request))
+
+		request.User = "johndoe"
+		assert.Equal(t, "user:johndoe", ByUser(request))
+	})
+
+	t.Run("ByRoute", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodGet, "/", nil)
+		assert.Empty(t, ByRoute(request))
+
+		router := goyave.NewRouter(testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()}).Server)
+		route := router.Get("/users/{id}", nil)
+		request.Route = route
+		assert.Equal(t, "/users/{id}", ByRoute(request))
+		route.Name("user.show")
+		assert.Equal(t, "user.show", ByRoute(request))
+	})
+
+	t.Run("Combine", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodGet, "/", nil)
+		a := func(_ *goyave.Request) string { return "a" }
+		b := func(_ *goyave.Request) string { return "b" }
+		assert.Equal(t, "a|b", Combine(a, b)(request))
+		assert.Empty(t, Combine()(request))
+	})
+
+	t.Run("limit_name", func(t *testing.T) {
+		assert.Equal(t, fmt.Sprintf("token-bucket:2:%s", time.Minute), (&Limit{Requests: 2, Window: time.Minute}).name())
+		assert.Equal(t, "sliding-window:2:1m0s", (&Limit{Requests: 2, Window: time.Minute, Algorithm: SlidingWindow{}}).name())
+		assert.Equal(t, "custom", (&Limit{Name: "custom"}).name())
+	})
+}
@@ -33,6 +33,22 @@ type (
 	// ExtraParseError the key used in `Context.Extra` to
 	// store specific parsing errors.
 	ExtraParseError struct{}
+
+	// ExtraCSRFToken the key used in `Context.Extra` to
+	// store the CSRF token of the current request.
+	ExtraCSRFToken struct{}
+
+	// ExtraCSRFFieldName the key used in `Context.Extra` to
+	// store the name of the form field expected to contain the CSRF token.
+	ExtraCSRFFieldName struct{}
+
+	// ExtraCSPNonce the key used in `Context.Extra` to
+	// store the Content-Security-Policy nonce of the current request.
+	ExtraCSPNonce struct{}
+
+	// ExtraHandlerDone the key used in `Context.Extra` to store a `<-chan struct{}`
+	// closed when the handler still running after a timeout returns. See `TimeoutMiddleware`.
+	ExtraHandlerDone struct{}
 )
 
 var (
@@ -47,6 +63,15 @@ var (
 
 	// ErrErrorInRequestBody error when e.g. a incoming request is not received properly.
 	ErrErrorInRequestBody = errors.New("parse middleware: could not read body")
+
+	// ErrInvalidBody error when the body cannot be decoded by the decoder matching its content type.
+	ErrInvalidBody = errors.New("parse middleware: could not decode body")
+
+	// ErrUnsupportedContentType error when no decoder is registered for the content type of the request.
+	ErrUnsupportedContentType = errors.New("parse middleware: unsupported content type")
+
+	// ErrUnsupportedContentEncoding error when the body is compressed with an unsupported algorithm.
+	ErrUnsupportedContentEncoding = errors.New("parse middleware: unsupported content encoding")
 )
 
 // Request represents a http request received by the server.
@@ -70,6 +95,7 @@ type Request struct {
 	Route       *Route
 	RouteParams map[string]string
 	cookies     []*http.Cookie
+	forwarded   *forwardedInfo
 }
 
 var requestPool = sync.Pool{
@@ -97,6 +123,7 @@ func (r *Request) reset(httpRequest *http.Request) {
 	r.Route = nil
 	r.RouteParams = nil
 	r.User = nil
+	r.forwarded = nil
 }
 
 // Request return the raw http request.
@@ -151,10 +178,40 @@ func (r *Request) ContentLength() int64 {
 
 // RemoteAddress allows to record the network address that
 // sent the request, usually for logging.
+//
+// If the request was received from one of the proxies listed in the "server.trustedProxies"
+// config entry, returns the client IP address forwarded by the proxies (without port).
+// Otherwise, returns the address of the peer (with port).
 func (r *Request) RemoteAddress() string {
+	if r.forwarded != nil && r.forwarded.remoteAddress != "" {
+		return r.forwarded.remoteAddress
+	}
 	return r.httpRequest.RemoteAddr
 }
 
+// Scheme returns the scheme ("http" or "https") used by the client to send the request.
+// If the request was received from a trusted proxy forwarding the original scheme,
+// the forwarded scheme is returned.
+func (r *Request) Scheme() string {
+	if r.forwarded != nil && r.forwarded.scheme != "" {
+		return r.forwarded.scheme
+	}
+	if r.httpRequest.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// Host returns the host requested by the client. If the request was received
+// from a trusted proxy forwarding the original host, the forwarded host is returned.
+// Otherwise, returns the value of the "Host" header, which is controlled by the client.
+func (r *Request) Host() string {
+	if r.forwarded != nil && r.forwarded.host != "" {
+		return r.forwarded.host
+	}
+	return r.httpRequest.Host
+}
+
 // Cookies returns the HTTP cookies sent with the request.
 func (r *Request) Cookies() []*http.Cookie {
 	if r.cookies == nil {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/samber/lo"
 	"goyave.dev/goyave/v5/cors"
@@ -140,6 +141,7 @@ func (r *Route) ValidateBody(validationRules RuleSetFunc) *Route {
 		r.Middleware(&validateRequestMiddleware{BodyRules: validationRules})
 	} else {
 		validationMiddleware.BodyRules = validationRules
+		validationMiddleware.bodyCache.reset()
 	}
 	return r
 }
@@ -151,6 +153,28 @@ func (r *Route) ValidateQuery(validationRules RuleSetFunc) *Route {
 		r.Middleware(&validateRequestMiddleware{QueryRules: validationRules})
 	} else {
 		validationMiddleware.QueryRules = validationRules
+		validationMiddleware.queryCache.reset()
+	}
+	return r
+}
+
+// StaticRules marks the body and query validation rules of this route as static:
+// they don't depend on the request. The `RuleSetFunc`s are called only once, on the
+// first validated request, and the returned rule sets are compiled and cached on the route.
+// The following requests only create fresh validator instances, which saves the cost
+// of parsing the paths and sorting the fields for every request.
+//
+// Don't use this if the rules depend on the request (for example if a validator uses
+// the request's user or the route parameters in a closure). See `validation.Static()`
+// for more details.
+//
+//	router.Post("/users", ctrl.Create).ValidateBody(ctrl.CreateRequest).StaticRules()
+func (r *Route) StaticRules() *Route {
+	validationMiddleware := findMiddleware[*validateRequestMiddleware](r.middleware)
+	if validationMiddleware == nil {
+		r.Middleware(&validateRequestMiddleware{Static: true})
+	} else {
+		validationMiddleware.Static = true
 	}
 	return r
 }
@@ -180,6 +204,17 @@ func (r *Route) CORS(options *cors.Options) *Route {
 	return r
 }
 
+// Timeout set the maximum duration of the execution of the route's handler.
+// The `TimeoutMiddleware` is automatically added globally.
+// A timeout lower or equal to 0 disables it.
+func (r *Route) Timeout(timeout time.Duration) *Route {
+	r.Meta[MetaTimeout] = timeout
+	if timeout > 0 && !hasMiddleware[*TimeoutMiddleware](r.parent.globalMiddleware.middleware) {
+		r.parent.GlobalMiddleware(&TimeoutMiddleware{})
+	}
+	return r
+}
+
 // Middleware register middleware for this route only.
 //
 // Returns itself.
@@ -205,6 +240,14 @@ func (r *Route) BuildProxyURL(parameters ...string) string {
 	return r.parent.server.ProxyBaseURL() + r.BuildURI(parameters...)
 }
 
+// BuildRequestURL build a full URL pointing to this route using the base URL
+// seen by the client of the given request (see `Server.RequestBaseURL()`).
+// Panics if the amount of parameters doesn't match the amount of
+// actual parameters for this route.
+func (r *Route) BuildRequestURL(request *Request, parameters ...string) string {
+	return r.parent.server.RequestBaseURL(request) + r.BuildURI(parameters...)
+}
+
 // BuildURI build a full URI pointing to this route. The returned
 // string doesn't include the protocol and domain. (e.g. "/user/login")
 // Panics if the amount of parameters doesn't match the amount of
@@ -132,6 +132,40 @@ func TestRoute(t *testing.T) {
 		assert.Nil(t, validationMiddleware.QueryRules)
 	})
 
+	t.Run("StaticRules", func(t *testing.T) {
+		router := prepareRouteTest()
+		route := &Route{
+			parent: router,
+			middlewareHolder: middlewareHolder{
+				middleware: []Middleware{},
+			},
+		}
+
+		route.StaticRules()
+		validationMiddleware := findMiddleware[*validateRequestMiddleware](route.middleware)
+		if !assert.NotNil(t, validationMiddleware) {
+			return
+		}
+		assert.True(t, validationMiddleware.Static)
+		assert.Nil(t, validationMiddleware.BodyRules)
+		assert.Nil(t, validationMiddleware.QueryRules)
+
+		route.ValidateBody(routeTestValidationRules).ValidateQuery(routeTestValidationRules).StaticRules()
+		assert.Len(t, route.middleware, 1)
+		assert.True(t, validationMiddleware.Static)
+		assert.NotNil(t, validationMiddleware.BodyRules)
+		assert.NotNil(t, validationMiddleware.QueryRules)
+
+		// Replacing the rules resets the cache
+		validationMiddleware.bodyCache.rules.Store(validation.Static(routeTestValidationRules(nil)))
+		validationMiddleware.queryCache.rules.Store(validation.Static(routeTestValidationRules(nil)))
+		route.ValidateBody(routeTestValidationRules)
+		assert.Nil(t, validationMiddleware.bodyCache.rules.Load())
+		assert.NotNil(t, validationMiddleware.queryCache.rules.Load())
+		route.ValidateQuery(routeTestValidationRules)
+		assert.Nil(t, validationMiddleware.queryCache.rules.Load())
+	})
+
 	t.Run("CORS", func(t *testing.T) {
 		router := prepareRouteTest()
 		route := &Route{
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
 	"maps"
 	"slices"
@@ -17,7 +18,8 @@ import (
 
 // Common route meta keys.
 const (
-	MetaCORS = "goyave.cors"
+	MetaCORS    = "goyave.cors"
+	MetaTimeout = "goyave.timeout"
 )
 
 // Special route names.
@@ -118,22 +120,33 @@ func NewRouter(server *Server) *Router {
 		regexCache: make(map[string]*regexp.Regexp, 5),
 		Meta:       make(map[string]any),
 	}
-	router.StatusHandler(&PanicStatusHandler{}, http.StatusInternalServerError)
+	router.registerDefaultStatusHandlers(
+		func() StatusHandler { return &PanicStatusHandler{} },
+		func() StatusHandler { return &ErrorStatusHandler{} },
+		&ParseErrorStatusHandler{},
+		&ValidationStatusHandler{},
+	)
+	router.GlobalMiddleware(&recoveryMiddleware{}, &languageMiddleware{}, &maintenanceMiddleware{})
+	return router
+}
+
+// registerDefaultStatusHandlers registers the given status handlers for all
+// standard HTTP status codes in the 400 and 500 ranges.
+func (r *Router) registerDefaultStatusHandlers(panicHandler, errorHandler func() StatusHandler, parseErrorHandler, validationHandler StatusHandler) {
+	r.StatusHandler(panicHandler(), http.StatusInternalServerError)
 	for i := http.StatusBadRequest; i <= http.StatusTeapot; i++ {
-		router.StatusHandler(&ErrorStatusHandler{}, i)
+		r.StatusHandler(errorHandler(), i)
 	}
-	router.StatusHandler(&ParseErrorStatusHandler{}, http.StatusBadRequest)
-	router.StatusHandler(&ValidationStatusHandler{}, http.StatusUnprocessableEntity)
+	r.StatusHandler(parseErrorHandler, http.StatusBadRequest, http.StatusUnsupportedMediaType)
+	r.StatusHandler(validationHandler, http.StatusUnprocessableEntity)
 	for i := http.StatusLocked; i <= http.StatusUpgradeRequired; i++ {
-		router.StatusHandler(&ErrorStatusHandler{}, i)
+		r.StatusHandler(errorHandler(), i)
 	}
-	router.StatusHandler(&ErrorStatusHandler{}, http.StatusMisdirectedRequest, http.StatusPreconditionRequired, http.StatusTooManyRequests, http.StatusRequestHeaderFieldsTooLarge, 444, http.StatusUnavailableForLegalReasons) // 444 is a nginx status code. Indicates server to return no information to the client and close the connection immediately
+	r.StatusHandler(errorHandler(), http.StatusMisdirectedRequest, http.StatusPreconditionRequired, http.StatusTooManyRequests, http.StatusRequestHeaderFieldsTooLarge, 444, http.StatusUnavailableForLegalReasons) // 444 is a nginx status code. Indicates server to return no information to the client and close the connection immediately
 	for i := http.StatusNotImplemented; i <= http.StatusLoopDetected; i++ {
-		router.StatusHandler(&ErrorStatusHandler{}, i)
+		r.StatusHandler(errorHandler(), i)
 	}
-	router.StatusHandler(&ErrorStatusHandler{}, http.StatusNotExtended, http.StatusNetworkAuthenticationRequired)
-	router.GlobalMiddleware(&recoveryMiddleware{}, &languageMiddleware{})
-	return router
+	r.StatusHandler(errorHandler(), http.StatusNotExtended, http.StatusNetworkAuthenticationRequired)
 }
 
 // ClearRegexCache set internal router's regex cache used for route parameters optimisation to nil
@@ -247,6 +260,17 @@ func (r *Router) CORS(options *cors.Options) *Router {
 	return r
 }
 
+// Timeout set the maximum duration of the execution of the handlers of this route group.
+// The `TimeoutMiddleware` is automatically added globally. Subrouters and routes can
+// override the timeout. A timeout lower or equal to 0 disables it.
+func (r *Router) Timeout(timeout time.Duration) *Router {
+	r.Meta[MetaTimeout] = timeout
+	if timeout > 0 && !hasMiddleware[*TimeoutMiddleware](r.globalMiddleware.middleware) {
+		r.GlobalMiddleware(&TimeoutMiddleware{})
+	}
+	return r
+}
+
 // StatusHandler set a handler for responses with an empty body.
 // The handler will be automatically executed if the request's life-cycle reaches its end
 // and nothing has been written in the response body.
@@ -267,9 +291,39 @@ func (r *Router) StatusHandler(handler StatusHandler, status int, additionalStat
 	}
 }
 
+// ProblemDetails replace the default status handlers of this router with
+// their RFC 9457 "Problem Details for HTTP APIs" alternatives, so all error responses
+// are written using the "application/problem+json" format:
+//   - `ProblemPanicStatusHandler` for 500
+//   - `ProblemParseErrorStatusHandler` for 400 and 415
+//   - `ProblemValidationStatusHandler` for 422
+//   - `ProblemStatusHandler` for all the other codes in the 400 and 500 ranges
+//
+// Status handlers are inherited as a copy in sub-routers, so this method should be called
+// before creating sub-routers. Custom status handlers should be registered after calling this method.
+func (r *Router) ProblemDetails() *Router {
+	r.registerDefaultStatusHandlers(
+		func() StatusHandler { return &ProblemPanicStatusHandler{} },
+		func() StatusHandler { return &ProblemStatusHandler{} },
+		&ProblemParseErrorStatusHandler{},
+		&ProblemValidationStatusHandler{},
+	)
+	return r
+}
+
 // ServeHTTP dispatches the handler registered in the matched route.
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	if req.URL.Scheme != "" && req.URL.Scheme != "http" {
+	var forwarded *forwardedInfo
+	scheme := req.URL.Scheme
+	redirect := scheme != "" && scheme != "http"
+	if info, ok := r.server.resolveForwarded(req); ok {
+		forwarded = &info
+		// The client's scheme is known from the trusted proxy: only redirect
+		// if it doesn't match the expected proxy protocol.
+		redirect = info.scheme != "" && r.server.config.Has("server.proxy.host") && info.scheme != r.server.config.GetString("server.proxy.protocol")
+	}
+
+	if redirect {
 		address := r.server.getProxyAddress(r.server.config) + req.URL.Path
 		query := req.URL.Query()
 		if len(query) != 0 {
@@ -281,7 +335,7 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 
 	match := routeMatch{currentPath: req.URL.Path}
 	r.match(req.Method, &match)
-	r.requestHandler(&match, w, req)
+	r.requestHandler(&match, w, req, forwarded)
 }
 
 // TODO export RouteMatch and add Match with string param function
@@ -486,8 +540,9 @@ func (r *Router) Controller(controller Registrer) *Router {
 	return r
 }
 
-func (r *Router) requestHandler(match *routeMatch, w http.ResponseWriter, rawRequest *http.Request) {
+func (r *Router) requestHandler(match *routeMatch, w http.ResponseWriter, rawRequest *http.Request, forwarded *forwardedInfo) {
 	request := NewRequest(rawRequest)
+	request.forwarded = forwarded
 	request.Route = match.route
 	if match.parameters == nil {
 		request.RouteParams = map[string]string{}
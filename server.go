@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"html/template"
 	"log"
 	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
 	"strconv"
@@ -47,6 +49,16 @@ type Options struct {
 	// If not provided, uses `osfs.FS` as a default.
 	LangFS fsutil.FS
 
+	// TemplateFS the file system from which the HTML templates used by
+	// `Response.Render()` will be loaded. This file system is expected to contain
+	// a `resources/templates` directory.
+	// If not provided, template rendering is disabled.
+	TemplateFS fsutil.FS
+
+	// TemplateFuncs additional functions made available to all HTML templates.
+	// Ignored if `TemplateFS` is not provided.
+	TemplateFuncs template.FuncMap
+
 	// ConnState specifies an optional callback function that is
 	// called when a client connection changes state. See the
 	// `http.ConnState` type and associated constants for details.
@@ -88,6 +100,10 @@ type Server struct {
 	config *config.Config
 	Lang   *lang.Languages
 
+	// Templates the HTML templates used by `Response.Render()`.
+	// `nil` if no `TemplateFS` was provided in the server options.
+	Templates *Templates
+
 	router *Router
 	db     *gorm.DB
 
@@ -101,6 +117,11 @@ type Server struct {
 	baseURL      string
 	proxyBaseURL string
 
+	trustedProxies       []netip.Prefix
+	proxyProtocolSources []netip.Prefix
+
+	maintenance maintenanceState
+
 	stopChannel chan struct{}
 	sigChannel  chan os.Signal
 
@@ -145,6 +166,15 @@ func New(opts Options) (*Server, error) {
 	port := cfg.GetInt("server.port")
 	host := cfg.GetString("server.host") + ":" + strconv.Itoa(port)
 
+	trustedProxies, err := parsePrefixes(cfg.GetStringSlice("server.trustedProxies"))
+	if err != nil {
+		return nil, err
+	}
+	proxyProtocolSources, err := parsePrefixes(cfg.GetStringSlice("server.proxyProtocol.allowedSources"))
+	if err != nil {
+		return nil, err
+	}
+
 	server := &Server{
 		server: &http.Server{
 			Addr:              host,
@@ -156,18 +186,21 @@ func New(opts Options) (*Server, error) {
 			ConnContext:       opts.ConnContext,
 			MaxHeaderBytes:    opts.MaxHeaderBytes,
 		},
-		ctx:           context.Background(),
-		baseContext:   opts.BaseContext,
-		config:        cfg,
-		services:      make(map[string]Service),
-		Lang:          languages,
-		stopChannel:   make(chan struct{}, 1),
-		startupHooks:  []func(*Server){},
-		shutdownHooks: []func(*Server){},
-		host:          cfg.GetString("server.host"),
-		port:          port,
-		Logger:        slogger,
+		ctx:                  context.Background(),
+		baseContext:          opts.BaseContext,
+		config:               cfg,
+		services:             make(map[string]Service),
+		Lang:                 languages,
+		stopChannel:          make(chan struct{}, 1),
+		startupHooks:         []func(*Server){},
+		shutdownHooks:        []func(*Server){},
+		host:                 cfg.GetString("server.host"),
+		port:                 port,
+		Logger:               slogger,
+		trustedProxies:       trustedProxies,
+		proxyProtocolSources: proxyProtocolSources,
 	}
+	server.maintenance.enabled.Store(cfg.GetBool("server.maintenance.enabled"))
 	server.server.BaseContext = server.internalBaseContext
 	server.refreshURLs()
 	server.server.ErrorLog = log.New(&errLogWriter{server: server}, "", 0)
@@ -180,6 +213,22 @@ func New(opts Options) (*Server, error) {
 		server.db = db
 	}
 
+	if opts.TemplateFS != nil {
+		templatesDirectory := "."
+		if wd, ok := opts.TemplateFS.(fsutil.WorkingDirFS); ok {
+			workingDir, err := wd.Getwd()
+			if err != nil {
+				return nil, errors.New(err)
+			}
+			templatesDirectory = workingDir + "/resources/templates"
+		}
+		templates, err := NewTemplates(server, opts.TemplateFS, templatesDirectory, opts.TemplateFuncs)
+		if err != nil {
+			return nil, err
+		}
+		server.Templates = templates
+	}
+
 	server.router = NewRouter(server)
 	server.server.Handler = server.router
 	return server, nil
@@ -389,6 +438,11 @@ func (s *Server) Router() *Router {
 }
 
 // Start the server. This operation is blocking and returns when the server is closed.
+//
+// If the "server.proxyProtocol.enabled" config entry is `true`, the listener decodes the
+// PROXY protocol (v1 and v2) headers sent by connections coming from the sources listed
+// in "server.proxyProtocol.allowedSources". The decoded client address becomes the
+// connection's remote address.
 func (s *Server) Start() error {
 	swapped := s.state.CompareAndSwap(0, 1)
 	if !swapped {
@@ -406,6 +460,13 @@ func (s *Server) Start() error {
 	if err != nil {
 		return errors.New(err)
 	}
+	if s.config.GetBool("server.proxyProtocol.enabled") {
+		ln = &proxyProtocolListener{
+			Listener:       ln,
+			allowedSources: s.proxyProtocolSources,
+			headerTimeout:  time.Duration(s.config.GetInt("server.proxyProtocol.headerTimeout")) * time.Second,
+		}
+	}
 	baseCtx := context.Background()
 	if s.baseContext != nil {
 		baseCtx = s.baseContext(ln)
@@ -0,0 +1,149 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	stderrors "errors"
+
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+// FileStoreFS the file system requirements of the `FileStore`.
+// `osfs.FS` implements this interface.
+type FileStoreFS interface {
+	fs.ReadDirFS
+	fsutil.WritableFS
+	fsutil.RemoveFS
+	fsutil.MkdirFS
+}
+
+type fileEntry struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+	Data      []byte    `json:"data"`
+}
+
+// FileStore a session `Store` keeping each session in its own file
+// inside a directory. Expired session files are removed when read or when calling `GC`.
+type FileStore struct {
+	FS FileStoreFS
+
+	// Directory the directory in which the session files are stored.
+	// It is created automatically if it doesn't exist.
+	Directory string
+}
+
+// NewFileStore create a new `FileStore` storing the session files
+// in the given directory of the given file system.
+func NewFileStore(fs FileStoreFS, directory string) *FileStore {
+	return &FileStore{
+		FS:        fs,
+		Directory: directory,
+	}
+}
+
+func (s *FileStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", errors.Errorf("session: invalid session ID %q", id)
+	}
+	return path.Join(s.Directory, id), nil
+}
+
+// Read the data of the session identified by the given ID.
+func (s *FileStore) Read(ctx context.Context, id string) ([]byte, error) {
+	p, err := s.path(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	f, err := s.FS.OpenFile(p, os.O_RDONLY, 0)
+	if err != nil {
+		if stderrors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, errors.New(err)
+	}
+	content, err := io.ReadAll(f)
+	_ = f.Close()
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	entry := &fileEntry{}
+	if err := json.Unmarshal(content, entry); err != nil {
+		return nil, errors.New(err)
+	}
+	if !entry.ExpiresAt.After(time.Now()) {
+		if err := s.Destroy(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrNotFound
+	}
+	return entry.Data, nil
+}
+
+// Write the data of the session identified by the given ID.
+func (s *FileStore) Write(_ context.Context, id string, data []byte, expiresAt time.Time) (err error) {
+	p, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err = s.FS.MkdirAll(s.Directory, os.ModePerm); err != nil {
+		return errors.New(err)
+	}
+	content, err := json.Marshal(&fileEntry{ExpiresAt: expiresAt, Data: data})
+	if err != nil {
+		return errors.New(err)
+	}
+
+	f, err := s.FS.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.New(err)
+	}
+	defer func() {
+		closeErr := f.Close()
+		if err == nil && closeErr != nil {
+			err = errors.New(closeErr)
+		}
+	}()
+	_, err = f.Write(content)
+	return errors.New(err)
+}
+
+// Destroy the session identified by the given ID.
+func (s *FileStore) Destroy(_ context.Context, id string) error {
+	p, err := s.path(id)
+	if err != nil {
+		return nil
+	}
+	if err := s.FS.Remove(p); err != nil && !stderrors.Is(err, fs.ErrNotExist) {
+		return errors.New(err)
+	}
+	return nil
+}
+
+// GC deletes all expired session files.
+func (s *FileStore) GC(ctx context.Context) error {
+	entries, err := s.FS.ReadDir(s.Directory)
+	if err != nil {
+		if stderrors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return errors.New(err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if _, err := s.Read(ctx, e.Name()); err != nil && !stderrors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
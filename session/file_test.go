@@ -0,0 +1,37 @@
+package session
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+)
+
+func TestFileStore(t *testing.T) {
+	fs := &osfs.FS{}
+	dir := path.Join(t.TempDir(), "sessions")
+	store := NewFileStore(fs, dir)
+	testStore(t, store)
+
+	t.Run("GC", func(t *testing.T) {
+		ctx := context.Background()
+		require.NoError(t, store.Write(ctx, "expired", []byte("data"), time.Now().Add(-time.Second)))
+		require.NoError(t, store.GC(ctx))
+		assert.False(t, fs.FileExists(path.Join(dir, "expired")))
+		assert.True(t, fs.FileExists(path.Join(dir, "valid")))
+
+		require.NoError(t, NewFileStore(fs, path.Join(dir, "notadir")).GC(ctx))
+	})
+
+	t.Run("invalid_id", func(t *testing.T) {
+		ctx := context.Background()
+		require.Error(t, store.Write(ctx, "../escape", []byte("data"), time.Now().Add(time.Hour)))
+		_, err := store.Read(ctx, "../escape")
+		require.ErrorIs(t, err, ErrNotFound)
+		require.NoError(t, store.Destroy(ctx, "../escape"))
+	})
+}
@@ -0,0 +1,83 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	stderrors "errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// Model the database representation of a session used by the `GormStore`.
+type Model struct {
+	ExpiresAt time.Time `gorm:"index"`
+	ID        string    `gorm:"primaryKey;size:64"`
+	Data      []byte
+}
+
+// TableName returns the default table name for the sessions: "sessions".
+func (Model) TableName() string {
+	return "sessions"
+}
+
+// GormStore a session `Store` keeping sessions in a database table, allowing
+// sessions to be shared between multiple instances of the application.
+// Expired sessions are ignored when read and deleted when calling `GC`.
+//
+// The table can be created using the `Model` structure with auto-migrations.
+type GormStore struct {
+	DB *gorm.DB
+
+	// Table the name of the table storing the sessions.
+	// If empty, the default "sessions" is used.
+	Table string
+}
+
+// NewGormStore create a new `GormStore` using the given database.
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{DB: db}
+}
+
+func (s *GormStore) db(ctx context.Context) *gorm.DB {
+	db := s.DB.WithContext(ctx)
+	if s.Table != "" {
+		return db.Table(s.Table)
+	}
+	return db.Model(&Model{})
+}
+
+// Read the data of the session identified by the given ID.
+func (s *GormStore) Read(ctx context.Context, id string) ([]byte, error) {
+	m := &Model{}
+	err := s.db(ctx).Where("id = ? AND expires_at > ?", id, time.Now()).Take(m).Error
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, errors.New(err)
+	}
+	return m.Data, nil
+}
+
+// Write the data of the session identified by the given ID.
+func (s *GormStore) Write(ctx context.Context, id string, data []byte, expiresAt time.Time) error {
+	m := &Model{ID: id, Data: data, ExpiresAt: expiresAt}
+	db := s.db(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"data", "expires_at"}),
+	})
+	return errors.New(db.Create(m).Error)
+}
+
+// Destroy the session identified by the given ID.
+func (s *GormStore) Destroy(ctx context.Context, id string) error {
+	return errors.New(s.db(ctx).Where("id = ?", id).Delete(&Model{}).Error)
+}
+
+// GC deletes all expired sessions.
+func (s *GormStore) GC(ctx context.Context) error {
+	return errors.New(s.db(ctx).Where("expires_at <= ?", time.Now()).Delete(&Model{}).Error)
+}
@@ -0,0 +1,48 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+
+	_ "goyave.dev/goyave/v5/database/dialect/sqlite"
+)
+
+func prepareGormStoreTest(t *testing.T, name string) *testutil.TestServer {
+	cfg := config.LoadDefault()
+	cfg.Set("database.connection", "sqlite3")
+	cfg.Set("database.name", name)
+	cfg.Set("database.options", "mode=memory")
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+	return server
+}
+
+func TestGormStore(t *testing.T) {
+	t.Run("default_table", func(t *testing.T) {
+		server := prepareGormStoreTest(t, "testsessiongormstore.db")
+		require.NoError(t, server.DB().AutoMigrate(&Model{}))
+		store := NewGormStore(server.DB())
+		testStore(t, store)
+
+		var count int64
+		require.NoError(t, server.DB().Model(&Model{}).Where("id = ?", "expired").Count(&count).Error)
+		assert.Zero(t, count)
+	})
+
+	t.Run("custom_table", func(t *testing.T) {
+		server := prepareGormStoreTest(t, "testsessiongormstorecustom.db")
+		require.NoError(t, server.DB().Table("custom_sessions").AutoMigrate(&Model{}))
+		store := &GormStore{DB: server.DB(), Table: "custom_sessions"}
+		require.NoError(t, store.Write(context.Background(), "id", []byte("data"), time.Now().Add(time.Hour)))
+
+		var count int64
+		require.NoError(t, server.DB().Table("custom_sessions").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+}
@@ -0,0 +1,257 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	stderrors "errors"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// neverExpires expiration used when both the idle and absolute timeouts are disabled.
+const neverExpires = 100 * 365 * 24 * time.Hour
+
+var sameSiteModes = map[string]http.SameSite{
+	"Lax":    http.SameSiteLaxMode,
+	"Strict": http.SameSiteStrictMode,
+	"None":   http.SameSiteNoneMode,
+}
+
+// Middleware loads the session identified by the request's session cookie
+// from its `Store` and attaches it to the request. The session can then be retrieved
+// using `session.FromRequest()`.
+//
+// The session cookie contains the session ID signed with HMAC-SHA256 using the
+// "session.secret" config entry (or `Secret` if set). Cookies with an invalid signature,
+// as well as unknown or expired sessions, are ignored and a new session is started.
+//
+// Sessions expire after "session.idleTimeout" seconds of inactivity, or "session.absoluteTimeout"
+// seconds after their creation, whichever comes first. A timeout of 0 disables it.
+//
+// The session is saved and the cookie is set right before the response header is written.
+// New sessions are only saved if they were modified. Existing sessions are saved on every request
+// so their last activity time is refreshed. Hijacked responses are ignored.
+//
+// The cookie attributes are defined by the "session.cookie.*" config entries.
+type Middleware struct {
+	goyave.Component
+	Store Store
+
+	// Secret the key used to sign the session cookies.
+	// If not set, uses the "session.secret" config entry.
+	Secret []byte
+}
+
+// New create a new session middleware using the given store.
+func New(store Store) *Middleware {
+	return &Middleware{Store: store}
+}
+
+// Handle loads the request session and sets up its persistence.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		session := m.load(request)
+		request.Extra[ExtraSession{}] = session
+
+		writer := &sessionWriter{
+			CommonWriter: goyave.NewCommonWriter(response.Writer()),
+			middleware:   m,
+			session:      session,
+			request:      request,
+			response:     response,
+		}
+		response.SetWriter(writer)
+
+		next(response, request)
+
+		if !response.IsHeaderWritten() && !response.Hijacked() {
+			writer.commit()
+		}
+	}
+}
+
+func (m *Middleware) secret() []byte {
+	if len(m.Secret) > 0 {
+		return m.Secret
+	}
+	if !m.Config().Has("session.secret") || m.Config().GetString("session.secret") == "" {
+		panic(errors.New("session: no secret, the \"session.secret\" config entry is not set"))
+	}
+	return []byte(m.Config().GetString("session.secret"))
+}
+
+func (m *Middleware) sign(id string) string {
+	mac := hmac.New(sha256.New, m.secret())
+	mac.Write([]byte(m.Config().GetString("session.cookie.name") + "=" + id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify the signature of the given cookie value and returns the session ID.
+func (m *Middleware) verify(value string) (string, bool) {
+	id, signature, ok := strings.Cut(value, ".")
+	if !ok || id == "" {
+		return "", false
+	}
+	return id, hmac.Equal([]byte(signature), []byte(m.sign(id)))
+}
+
+func (m *Middleware) load(request *goyave.Request) *Session {
+	now := request.Now
+	if cookie, err := request.Request().Cookie(m.Config().GetString("session.cookie.name")); err == nil {
+		if id, ok := m.verify(cookie.Value); ok {
+			if session := m.read(request, id); session != nil {
+				return session
+			}
+		}
+	}
+
+	session, err := newSession(now)
+	if err != nil {
+		panic(err)
+	}
+	session.modified = false
+	return session
+}
+
+func (m *Middleware) read(request *goyave.Request, id string) *Session {
+	data, err := m.Store.Read(request.Context(), id)
+	if err != nil {
+		if stderrors.Is(err, ErrNotFound) {
+			return nil
+		}
+		panic(errors.New(err))
+	}
+	session, err := decodeSession(id, data)
+	if err != nil {
+		m.Logger().Error(err)
+		return nil
+	}
+	if m.isExpired(session, request.Now) {
+		if err := m.Store.Destroy(request.Context(), id); err != nil {
+			panic(errors.New(err))
+		}
+		return nil
+	}
+	return session
+}
+
+func (m *Middleware) isExpired(session *Session, now time.Time) bool {
+	return !m.expiresAt(session).After(now)
+}
+
+func (m *Middleware) expiresAt(session *Session) time.Time {
+	idle := time.Duration(m.Config().GetInt("session.idleTimeout")) * time.Second
+	absolute := time.Duration(m.Config().GetInt("session.absoluteTimeout")) * time.Second
+	switch {
+	case idle <= 0 && absolute <= 0:
+		return session.lastActivity.Add(neverExpires)
+	case idle <= 0:
+		return session.createdAt.Add(absolute)
+	case absolute <= 0:
+		return session.lastActivity.Add(idle)
+	}
+	expiresAt := session.lastActivity.Add(idle)
+	if absoluteExpiry := session.createdAt.Add(absolute); absoluteExpiry.Before(expiresAt) {
+		return absoluteExpiry
+	}
+	return expiresAt
+}
+
+func (m *Middleware) cookie(value string) *http.Cookie {
+	cfg := m.Config()
+	return &http.Cookie{
+		Name:     cfg.GetString("session.cookie.name"),
+		Value:    value,
+		Path:     cfg.GetString("session.cookie.path"),
+		Domain:   cfg.GetString("session.cookie.domain"),
+		Secure:   cfg.GetBool("session.cookie.secure"),
+		HttpOnly: true,
+		SameSite: sameSiteModes[cfg.GetString("session.cookie.sameSite")],
+	}
+}
+
+// save persists the session and sets the session cookie.
+func (m *Middleware) save(response *goyave.Response, request *goyave.Request, session *Session) error {
+	ctx := request.Context()
+	session.mu.Lock()
+	oldID := session.oldID
+	destroyed := session.destroyed
+	persist := !session.isNew || session.modified
+	session.oldID = ""
+	if !destroyed {
+		session.lastActivity = request.Now
+	}
+	session.mu.Unlock()
+
+	if oldID != "" {
+		if err := m.Store.Destroy(ctx, oldID); err != nil {
+			return errors.New(err)
+		}
+	}
+
+	if destroyed {
+		if !session.isNew {
+			if err := m.Store.Destroy(ctx, session.ID()); err != nil {
+				return errors.New(err)
+			}
+		}
+		cookie := m.cookie("")
+		cookie.MaxAge = -1
+		response.Cookie(cookie)
+		return nil
+	}
+
+	if !persist {
+		return nil
+	}
+
+	data, err := session.encode()
+	if err != nil {
+		return err
+	}
+	expiresAt := m.expiresAt(session)
+	if err := m.Store.Write(ctx, session.ID(), data, expiresAt); err != nil {
+		return errors.New(err)
+	}
+
+	id := session.ID()
+	cookie := m.cookie(id + "." + m.sign(id))
+	if absolute := m.Config().GetInt("session.absoluteTimeout"); absolute > 0 {
+		cookie.Expires = session.CreatedAt().Add(time.Duration(absolute) * time.Second)
+	}
+	response.Cookie(cookie)
+	return nil
+}
+
+// sessionWriter chained writer saving the session right before
+// the response header is written.
+type sessionWriter struct {
+	goyave.CommonWriter
+	middleware *Middleware
+	session    *Session
+	request    *goyave.Request
+	response   *goyave.Response
+	committed  bool
+}
+
+// PreWrite saves the session and sets the cookie before calling PreWrite on the child writer.
+func (w *sessionWriter) PreWrite(b []byte) {
+	w.commit()
+	w.CommonWriter.PreWrite(b)
+}
+
+func (w *sessionWriter) commit() {
+	if w.committed {
+		return
+	}
+	w.committed = true
+	if err := w.middleware.save(w.response, w.request, w.session); err != nil {
+		w.middleware.Logger().Error(err)
+	}
+}
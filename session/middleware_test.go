@@ -0,0 +1,176 @@
+package session
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func prepareMiddlewareTest(t *testing.T) (*testutil.TestServer, *MemoryStore) {
+	cfg := config.LoadDefault()
+	cfg.Set("session.secret", "test-secret")
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+	store := NewMemoryStore()
+
+	router := server.Router()
+	router.GlobalMiddleware(New(store))
+	router.Get("/get", func(response *goyave.Response, request *goyave.Request) {
+		s := FromRequest(request)
+		v, _ := s.Get("key")
+		flash, _ := s.GetFlash("flash")
+		response.JSON(http.StatusOK, map[string]any{"value": v, "flash": flash, "new": s.IsNew()})
+	})
+	router.Get("/set", func(response *goyave.Response, request *goyave.Request) {
+		s := FromRequest(request)
+		s.Set("key", request.Request().URL.Query().Get("value"))
+		s.Flash("flash", "message")
+		response.Status(http.StatusNoContent)
+	})
+	router.Get("/regenerate", func(response *goyave.Response, request *goyave.Request) {
+		require.NoError(t, FromRequest(request).Regenerate())
+		response.String(http.StatusOK, "ok")
+	})
+	router.Get("/destroy", func(response *goyave.Response, request *goyave.Request) {
+		FromRequest(request).Destroy()
+		response.Status(http.StatusNoContent)
+	})
+	return server, store
+}
+
+func sessionCookie(resp *http.Response) *http.Cookie {
+	for _, c := range resp.Cookies() {
+		if c.Name == "goyave_session" {
+			return c
+		}
+	}
+	return nil
+}
+
+func doRequest(server *testutil.TestServer, uri string, cookie *http.Cookie) (*http.Response, string) {
+	req := httptest.NewRequest(http.MethodGet, uri, nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	resp := server.TestRequest(req)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	return resp, string(body)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("unmodified_new_session_not_saved", func(t *testing.T) {
+		server, store := prepareMiddlewareTest(t)
+		resp, body := doRequest(server, "/get", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `{"flash":null,"new":true,"value":null}`+"\n", body)
+		assert.Nil(t, sessionCookie(resp))
+		assert.Empty(t, store.sessions)
+	})
+
+	t.Run("persist", func(t *testing.T) {
+		server, store := prepareMiddlewareTest(t)
+		resp, _ := doRequest(server, "/set?value=hello", nil)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		cookie := sessionCookie(resp)
+		require.NotNil(t, cookie)
+		assert.True(t, cookie.HttpOnly)
+		assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+		assert.Equal(t, "/", cookie.Path)
+		assert.False(t, cookie.Expires.IsZero())
+		assert.Len(t, store.sessions, 1)
+
+		resp, body := doRequest(server, "/get", cookie)
+		assert.Equal(t, `{"flash":"message","new":false,"value":"hello"}`+"\n", body)
+		require.NotNil(t, sessionCookie(resp))
+
+		// Flash consumed
+		_, body = doRequest(server, "/get", cookie)
+		assert.Equal(t, `{"flash":null,"new":false,"value":"hello"}`+"\n", body)
+	})
+
+	t.Run("invalid_signature", func(t *testing.T) {
+		server, store := prepareMiddlewareTest(t)
+		resp, _ := doRequest(server, "/set?value=hello", nil)
+		cookie := sessionCookie(resp)
+		require.NotNil(t, cookie)
+
+		id, _, _ := strings.Cut(cookie.Value, ".")
+		for _, value := range []string{id, id + ".invalid", "." + strings.Split(cookie.Value, ".")[1]} {
+			_, body := doRequest(server, "/get", &http.Cookie{Name: cookie.Name, Value: value})
+			assert.Equal(t, `{"flash":null,"new":true,"value":null}`+"\n", body)
+		}
+		assert.Len(t, store.sessions, 1)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		server, store := prepareMiddlewareTest(t)
+		resp, _ := doRequest(server, "/set?value=hello", nil)
+		cookie := sessionCookie(resp)
+		require.NotNil(t, cookie)
+
+		id, _, _ := strings.Cut(cookie.Value, ".")
+		s, err := decodeSession(id, store.sessions[id].data)
+		require.NoError(t, err)
+		s.lastActivity = time.Now().Add(-time.Hour)
+		data, err := s.encode()
+		require.NoError(t, err)
+		require.NoError(t, store.Write(context.Background(), id, data, time.Now().Add(time.Hour)))
+
+		_, body := doRequest(server, "/get", cookie)
+		assert.Equal(t, `{"flash":null,"new":true,"value":null}`+"\n", body)
+		assert.NotContains(t, store.sessions, id)
+	})
+
+	t.Run("regenerate", func(t *testing.T) {
+		server, store := prepareMiddlewareTest(t)
+		resp, _ := doRequest(server, "/set?value=hello", nil)
+		cookie := sessionCookie(resp)
+		require.NotNil(t, cookie)
+		oldID, _, _ := strings.Cut(cookie.Value, ".")
+
+		resp, _ = doRequest(server, "/regenerate", cookie)
+		newCookie := sessionCookie(resp)
+		require.NotNil(t, newCookie)
+		newID, _, _ := strings.Cut(newCookie.Value, ".")
+		assert.NotEqual(t, oldID, newID)
+		assert.NotContains(t, store.sessions, oldID)
+		assert.Contains(t, store.sessions, newID)
+
+		_, body := doRequest(server, "/get", newCookie)
+		assert.Equal(t, `{"flash":null,"new":false,"value":"hello"}`+"\n", body)
+	})
+
+	t.Run("destroy", func(t *testing.T) {
+		server, store := prepareMiddlewareTest(t)
+		resp, _ := doRequest(server, "/set?value=hello", nil)
+		cookie := sessionCookie(resp)
+		require.NotNil(t, cookie)
+
+		resp, _ = doRequest(server, "/destroy", cookie)
+		deleted := sessionCookie(resp)
+		require.NotNil(t, deleted)
+		assert.Equal(t, -1, deleted.MaxAge)
+		assert.Empty(t, deleted.Value)
+		assert.Empty(t, store.sessions)
+	})
+
+	t.Run("secret_option", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		m := New(NewMemoryStore())
+		m.Init(server.Server)
+		assert.PanicsWithError(t, "session: no secret, the \"session.secret\" config entry is not set", func() { m.sign("id") })
+
+		m.Secret = []byte("secret")
+		assert.NotPanics(t, func() { m.sign("id") })
+	})
+}
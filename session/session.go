@@ -0,0 +1,299 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// ExtraSession the key used in `Request.Extra` to store the
+// `*Session` of the current request.
+type ExtraSession struct{}
+
+// idLength the length of the session IDs, in bytes, before encoding.
+const idLength = 32
+
+func init() {
+	config.Register("session.secret", config.Entry{
+		Value:            nil,
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("session.idleTimeout", config.Entry{
+		Value:            1800,
+		Type:             reflect.Int,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("session.absoluteTimeout", config.Entry{
+		Value:            86400,
+		Type:             reflect.Int,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("session.cookie.name", config.Entry{
+		Value:            "goyave_session",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("session.cookie.path", config.Entry{
+		Value:            "/",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("session.cookie.domain", config.Entry{
+		Value:            "",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("session.cookie.secure", config.Entry{
+		Value:            false,
+		Type:             reflect.Bool,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("session.cookie.sameSite", config.Entry{
+		Value:            "Lax",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{"Lax", "Strict", "None"},
+	})
+}
+
+// record the persisted representation of a session.
+type record struct {
+	Values       map[string]any `json:"values"`
+	Flashes      map[string]any `json:"flashes,omitempty"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	LastActivity time.Time      `json:"lastActivity"`
+}
+
+// Session server-side HTTP session attached to a request.
+// The session data is loaded by the session `Middleware` from its `Store`
+// and persisted automatically before the response is written.
+//
+// Values are serialized using JSON when persisted. Therefore, values retrieved
+// in subsequent requests will have the type resulting from JSON unmarshaling
+// (e.g. numbers are `float64`).
+//
+// This structure is safe for concurrent use.
+type Session struct {
+	values     map[string]any
+	oldFlashes map[string]any
+	newFlashes map[string]any
+
+	createdAt    time.Time
+	lastActivity time.Time
+
+	id    string
+	oldID string
+
+	mu sync.RWMutex
+
+	modified  bool
+	destroyed bool
+	isNew     bool
+}
+
+func newSession(now time.Time) (*Session, error) {
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		id:           id,
+		values:       map[string]any{},
+		oldFlashes:   map[string]any{},
+		newFlashes:   map[string]any{},
+		createdAt:    now,
+		lastActivity: now,
+		isNew:        true,
+		modified:     true,
+	}, nil
+}
+
+func decodeSession(id string, data []byte) (*Session, error) {
+	r := &record{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, errors.New(err)
+	}
+	if r.Values == nil {
+		r.Values = map[string]any{}
+	}
+	if r.Flashes == nil {
+		r.Flashes = map[string]any{}
+	}
+	return &Session{
+		id:           id,
+		values:       r.Values,
+		oldFlashes:   r.Flashes,
+		newFlashes:   map[string]any{},
+		createdAt:    r.CreatedAt,
+		lastActivity: r.LastActivity,
+		// Flashes from the previous request are consumed by this request,
+		// so the session has to be saved again.
+		modified: len(r.Flashes) > 0,
+	}, nil
+}
+
+func (s *Session) encode() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, err := json.Marshal(&record{
+		Values:       s.values,
+		Flashes:      s.newFlashes,
+		CreatedAt:    s.createdAt,
+		LastActivity: s.lastActivity,
+	})
+	return data, errors.New(err)
+}
+
+func generateID() (string, error) {
+	b := make([]byte, idLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.New(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ID returns the current identifier of the session.
+func (s *Session) ID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.id
+}
+
+// IsNew returns true if the session has been created during the current request.
+func (s *Session) IsNew() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.isNew
+}
+
+// CreatedAt returns the time at which the session was created.
+func (s *Session) CreatedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.createdAt
+}
+
+// LastActivity returns the time of the last request using this session,
+// before the current request.
+func (s *Session) LastActivity() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastActivity
+}
+
+// Get the value identified by the given key. Returns the value and `true` if it exists,
+// `nil` and `false` otherwise.
+func (s *Session) Get(key string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Set the value identified by the given key.
+func (s *Session) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	s.modified = true
+}
+
+// Delete the value identified by the given key.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	s.modified = true
+}
+
+// Clear removes all the values and flash messages from the session.
+// The session ID is kept.
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = map[string]any{}
+	s.oldFlashes = map[string]any{}
+	s.newFlashes = map[string]any{}
+	s.modified = true
+}
+
+// Flash set a flash value identified by the given key. Flash values are only
+// available in the next request using this session (see `GetFlash`), then deleted.
+func (s *Session) Flash(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.newFlashes[key] = value
+	s.modified = true
+}
+
+// GetFlash returns the flash value identified by the given key and set during the
+// previous request. Returns the value and `true` if it exists, `nil` and `false` otherwise.
+func (s *Session) GetFlash(key string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.oldFlashes[key]
+	return v, ok
+}
+
+// Reflash keeps all the flash values of the previous request for the next request.
+func (s *Session) Reflash() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.oldFlashes {
+		if _, ok := s.newFlashes[k]; !ok {
+			s.newFlashes[k] = v
+		}
+	}
+	s.modified = true
+}
+
+// Regenerate the session ID while keeping its data. The previous ID is invalidated
+// when the session is saved. This should be called on login and privilege level changes
+// to prevent session fixation attacks.
+func (s *Session) Regenerate() error {
+	id, err := generateID()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.oldID == "" && !s.isNew {
+		s.oldID = s.id
+	}
+	s.id = id
+	s.modified = true
+	return nil
+}
+
+// Destroy the session. Its data is removed from the store and the session
+// cookie is deleted from the client.
+func (s *Session) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.destroyed = true
+	s.values = map[string]any{}
+	s.oldFlashes = map[string]any{}
+	s.newFlashes = map[string]any{}
+}
+
+// FromRequest returns the session attached to the given request by the session `Middleware`.
+// Returns `nil` if there is none.
+func FromRequest(request *goyave.Request) *Session {
+	s, _ := request.Extra[ExtraSession{}].(*Session)
+	return s
+}
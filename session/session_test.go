@@ -0,0 +1,132 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func TestSession(t *testing.T) {
+	t.Run("newSession", func(t *testing.T) {
+		now := time.Now()
+		s, err := newSession(now)
+		require.NoError(t, err)
+		assert.Len(t, s.ID(), 43)
+		assert.True(t, s.IsNew())
+		assert.Equal(t, now, s.CreatedAt())
+		assert.Equal(t, now, s.LastActivity())
+
+		s2, err := newSession(now)
+		require.NoError(t, err)
+		assert.NotEqual(t, s.ID(), s2.ID())
+	})
+
+	t.Run("Get_Set_Delete_Clear", func(t *testing.T) {
+		s, err := newSession(time.Now())
+		require.NoError(t, err)
+		s.modified = false
+
+		v, ok := s.Get("key")
+		assert.Nil(t, v)
+		assert.False(t, ok)
+
+		s.Set("key", "value")
+		assert.True(t, s.modified)
+		v, ok = s.Get("key")
+		assert.Equal(t, "value", v)
+		assert.True(t, ok)
+
+		s.Delete("key")
+		_, ok = s.Get("key")
+		assert.False(t, ok)
+
+		s.Set("key", "value")
+		s.Flash("flash", "value")
+		s.Clear()
+		assert.Empty(t, s.values)
+		assert.Empty(t, s.newFlashes)
+	})
+
+	t.Run("encode_decode", func(t *testing.T) {
+		now := time.Now().Round(0)
+		s, err := newSession(now)
+		require.NoError(t, err)
+		s.Set("key", "value")
+		s.Set("number", 12)
+		s.Flash("flash", "message")
+
+		data, err := s.encode()
+		require.NoError(t, err)
+
+		decoded, err := decodeSession(s.ID(), data)
+		require.NoError(t, err)
+		assert.Equal(t, s.ID(), decoded.ID())
+		assert.False(t, decoded.IsNew())
+		assert.True(t, decoded.modified)
+		assert.True(t, now.Equal(decoded.CreatedAt()))
+		assert.Equal(t, map[string]any{"key": "value", "number": 12.0}, decoded.values)
+
+		v, ok := decoded.GetFlash("flash")
+		assert.Equal(t, "message", v)
+		assert.True(t, ok)
+
+		// Flashes are only available for one request
+		data, err = decoded.encode()
+		require.NoError(t, err)
+		decoded, err = decodeSession(s.ID(), data)
+		require.NoError(t, err)
+		_, ok = decoded.GetFlash("flash")
+		assert.False(t, ok)
+		assert.False(t, decoded.modified)
+
+		_, err = decodeSession("id", []byte("{invalid"))
+		require.Error(t, err)
+	})
+
+	t.Run("Reflash", func(t *testing.T) {
+		s := &Session{
+			oldFlashes: map[string]any{"a": "old", "b": "old"},
+			newFlashes: map[string]any{"b": "new"},
+		}
+		s.Reflash()
+		assert.Equal(t, map[string]any{"a": "old", "b": "new"}, s.newFlashes)
+		assert.True(t, s.modified)
+	})
+
+	t.Run("Regenerate", func(t *testing.T) {
+		s, err := newSession(time.Now())
+		require.NoError(t, err)
+		id := s.ID()
+		require.NoError(t, s.Regenerate())
+		assert.NotEqual(t, id, s.ID())
+		assert.Empty(t, s.oldID) // New session: nothing to invalidate
+
+		s.isNew = false
+		id = s.ID()
+		require.NoError(t, s.Regenerate())
+		require.NoError(t, s.Regenerate())
+		assert.NotEqual(t, id, s.ID())
+		assert.Equal(t, id, s.oldID)
+	})
+
+	t.Run("Destroy", func(t *testing.T) {
+		s, err := newSession(time.Now())
+		require.NoError(t, err)
+		s.Set("key", "value")
+		s.Destroy()
+		assert.True(t, s.destroyed)
+		assert.Empty(t, s.values)
+	})
+
+	t.Run("FromRequest", func(t *testing.T) {
+		request := testutil.NewTestRequest("GET", "/", nil)
+		assert.Nil(t, FromRequest(request))
+
+		s := &Session{}
+		request.Extra[ExtraSession{}] = s
+		assert.Same(t, s, FromRequest(request))
+	})
+}
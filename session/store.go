@@ -0,0 +1,99 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotFound returned by `Store.Read` if the session doesn't exist or is expired.
+var ErrNotFound = errors.New("session not found")
+
+// Store persists the encoded session data. Implementations must be safe for concurrent use.
+type Store interface {
+	// Read the data of the session identified by the given ID.
+	// Returns `ErrNotFound` if the session doesn't exist or is expired.
+	Read(ctx context.Context, id string) ([]byte, error)
+
+	// Write the data of the session identified by the given ID, creating it if needed.
+	// The session should be considered expired after the given time.
+	Write(ctx context.Context, id string, data []byte, expiresAt time.Time) error
+
+	// Destroy the session identified by the given ID.
+	// Destroying a session that doesn't exist is not an error.
+	Destroy(ctx context.Context, id string) error
+}
+
+// GarbageCollector can be implemented by stores that need expired
+// sessions to be purged periodically.
+type GarbageCollector interface {
+	// GC deletes all expired sessions.
+	GC(ctx context.Context) error
+}
+
+type memoryEntry struct {
+	expiresAt time.Time
+	data      []byte
+}
+
+// MemoryStore a session `Store` keeping sessions in memory.
+// Sessions are lost when the process stops and are not shared between multiple instances
+// of the application. Expired sessions are removed when read or when calling `GC`.
+type MemoryStore struct {
+	sessions map[string]memoryEntry
+	mu       sync.RWMutex
+}
+
+// NewMemoryStore create a new empty `MemoryStore`.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions: make(map[string]memoryEntry),
+	}
+}
+
+// Read the data of the session identified by the given ID.
+func (s *MemoryStore) Read(_ context.Context, id string) ([]byte, error) {
+	s.mu.RLock()
+	entry, ok := s.sessions[id]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if !entry.expiresAt.After(time.Now()) {
+		s.mu.Lock()
+		delete(s.sessions, id)
+		s.mu.Unlock()
+		return nil, ErrNotFound
+	}
+	return entry.data, nil
+}
+
+// Write the data of the session identified by the given ID.
+func (s *MemoryStore) Write(_ context.Context, id string, data []byte, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[id] = memoryEntry{data: data, expiresAt: expiresAt}
+	return nil
+}
+
+// Destroy the session identified by the given ID.
+func (s *MemoryStore) Destroy(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+// GC deletes all expired sessions.
+func (s *MemoryStore) GC(_ context.Context) error {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, entry := range s.sessions {
+		if !entry.expiresAt.After(now) {
+			delete(s.sessions, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	_, err := store.Read(ctx, "unknown")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, store.Write(ctx, "id", []byte("data"), time.Now().Add(time.Hour)))
+	data, err := store.Read(ctx, "id")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+
+	require.NoError(t, store.Write(ctx, "id", []byte("updated"), time.Now().Add(time.Hour)))
+	data, err = store.Read(ctx, "id")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("updated"), data)
+
+	require.NoError(t, store.Destroy(ctx, "id"))
+	_, err = store.Read(ctx, "id")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, store.Destroy(ctx, "id"))
+
+	require.NoError(t, store.Write(ctx, "expired", []byte("data"), time.Now().Add(-time.Second)))
+	_, err = store.Read(ctx, "expired")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	gc, ok := store.(GarbageCollector)
+	require.True(t, ok)
+	require.NoError(t, store.Write(ctx, "expired", []byte("data"), time.Now().Add(-time.Second)))
+	require.NoError(t, store.Write(ctx, "valid", []byte("data"), time.Now().Add(time.Hour)))
+	require.NoError(t, gc.GC(ctx))
+	_, err = store.Read(ctx, "valid")
+	require.NoError(t, err)
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	testStore(t, store)
+
+	require.NoError(t, store.Write(context.Background(), "expired", []byte("data"), time.Now().Add(-time.Second)))
+	require.NoError(t, store.GC(context.Background()))
+	assert.NotContains(t, store.sessions, "expired")
+	assert.Contains(t, store.sessions, "valid")
+}
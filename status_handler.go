@@ -36,16 +36,20 @@ func (*PanicStatusHandler) Handle(response *Response, _ *Request) {
 }
 
 // ErrorStatusHandler a generic status handler for non-success codes.
-// Writes the corresponding status message to the response.
+// Writes the corresponding status message to the response, or the
+// localized maintenance message if the server is in maintenance mode.
 type ErrorStatusHandler struct {
 	Component
 }
 
 // Handle generic error responses.
-func (*ErrorStatusHandler) Handle(response *Response, _ *Request) {
+func (*ErrorStatusHandler) Handle(response *Response, request *Request) {
 	message := map[string]string{
 		"error": http.StatusText(response.GetStatus()),
 	}
+	if m, ok := maintenanceMessage(request); ok {
+		message["error"] = m
+	}
 	response.JSON(response.GetStatus(), message)
 }
 
@@ -56,41 +60,62 @@ type ParseErrorStatusHandler struct {
 
 // Handle generic request (error) responses.
 func (h *ParseErrorStatusHandler) Handle(response *Response, request *Request) {
-	var errorMessage string
+	message := map[string]string{
+		"error": parseErrorMessage(response, request),
+	}
+	response.JSON(response.GetStatus(), message)
+}
+
+// parseErrorMessage returns the localized message describing the parse
+// error stored in the request's extra, or the status text if there is none.
+func parseErrorMessage(response *Response, request *Request) string {
 	lang := request.Lang
 
 	err, ok := request.Extra[ExtraParseError{}].(error)
-	if ok {
-		switch {
-		case errors.Is(err, ErrInvalidJSONBody):
-			errorMessage = lang.Get("parse.json-invalid-body")
-		case errors.Is(err, ErrInvalidQuery):
-			errorMessage = lang.Get("parse.invalid-query")
-		case errors.Is(err, ErrInvalidContentForType):
-			errorMessage = lang.Get("parse.invalid-content-for-type")
-		case errors.Is(err, ErrErrorInRequestBody):
-			errorMessage = lang.Get("parse.error-in-request-body")
-		default:
-			errorMessage = lang.Get(err.Error())
-		}
-	} else {
-		errorMessage = http.StatusText(response.GetStatus())
+	if !ok {
+		return http.StatusText(response.GetStatus())
 	}
-
-	message := map[string]string{
-		"error": errorMessage,
+	switch {
+	case errors.Is(err, ErrInvalidJSONBody):
+		return lang.Get("parse.json-invalid-body")
+	case errors.Is(err, ErrInvalidQuery):
+		return lang.Get("parse.invalid-query")
+	case errors.Is(err, ErrInvalidContentForType):
+		return lang.Get("parse.invalid-content-for-type")
+	case errors.Is(err, ErrErrorInRequestBody):
+		return lang.Get("parse.error-in-request-body")
+	case errors.Is(err, ErrInvalidBody):
+		return lang.Get("parse.invalid-body")
+	case errors.Is(err, ErrUnsupportedContentType):
+		return lang.Get("parse.unsupported-content-type")
+	case errors.Is(err, ErrUnsupportedContentEncoding):
+		return lang.Get("parse.unsupported-content-encoding")
+	default:
+		return lang.Get(err.Error())
 	}
-	response.JSON(response.GetStatus(), message)
 }
 
 // ValidationStatusHandler for HTTP 422 errors.
 // Writes the validation errors to the response.
 type ValidationStatusHandler struct {
 	Component
+
+	// Format the format of the validation errors written to the response.
+	// Defaults to `validation.ErrorFormatNested`.
+	//
+	//	router.StatusHandler(&goyave.ValidationStatusHandler{Format: validation.ErrorFormatViolations}, http.StatusUnprocessableEntity)
+	Format validation.ErrorFormat
 }
 
 // Handle validation error responses.
-func (*ValidationStatusHandler) Handle(response *Response, request *Request) {
+func (h *ValidationStatusHandler) Handle(response *Response, request *Request) {
+	message := map[string]any{"error": validationErrorResponse(request).Format(h.Format)}
+	response.JSON(response.GetStatus(), message)
+}
+
+// validationErrorResponse gathers the body and query validation errors
+// stored in the request's extra.
+func validationErrorResponse(request *Request) *validation.ErrorResponse {
 	errs := &validation.ErrorResponse{}
 
 	if e, ok := request.Extra[ExtraValidationError{}]; ok {
@@ -100,7 +125,5 @@ func (*ValidationStatusHandler) Handle(response *Response, request *Request) {
 	if e, ok := request.Extra[ExtraQueryValidationError{}]; ok {
 		errs.Query = e.(*validation.Errors)
 	}
-
-	message := map[string]*validation.ErrorResponse{"error": errs}
-	response.JSON(response.GetStatus(), message)
+	return errs
 }
@@ -136,6 +136,51 @@ func TestValidationStatusHandler(t *testing.T) {
 	assert.Equal(t, `{"error":{"body":{"fields":{"field":{"errors":["The field is required"]}},"errors":["The body is required"]},"query":{"fields":{"query":{"errors":["The query is required"]}}}}}`+"\n", string(body))
 }
 
+func TestValidationStatusHandlerFormat(t *testing.T) {
+	cases := []struct {
+		expected string
+		format   validation.ErrorFormat
+	}{
+		{format: validation.ErrorFormatPath, expected: `{"error":{"body":{"items[0].name":["The name is required"]},"query":{"page":["The page must be an integer"]}}}`},
+		{format: validation.ErrorFormatJSONPointer, expected: `{"error":{"body":{"/items/0/name":["The name is required"]},"query":{"/page":["The page must be an integer"]}}}`},
+		{format: validation.ErrorFormatViolations, expected: `{"error":{"body":[{"path":"items[0].name","rule":"required","message":"The name is required"}],"query":[{"path":"page","message":"The page must be an integer"}]}}`},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%d", c.format), func(t *testing.T) {
+			req, resp, recorder := prepareStatusHandlerTest()
+			handler := &ValidationStatusHandler{Format: c.format}
+			handler.Init(resp.server)
+
+			req.Extra[ExtraValidationError{}] = &validation.Errors{
+				Fields: validation.FieldsErrors{
+					"items": &validation.Errors{Elements: validation.ArrayErrors{
+						0: &validation.Errors{Fields: validation.FieldsErrors{
+							"name": &validation.Errors{
+								Errors:     []string{"The name is required"},
+								Violations: []*validation.Violation{{Rule: "required", Message: "The name is required"}},
+							},
+						}},
+					}},
+				},
+			}
+			req.Extra[ExtraQueryValidationError{}] = &validation.Errors{
+				Fields: validation.FieldsErrors{
+					"page": &validation.Errors{Errors: []string{"The page must be an integer"}},
+				},
+			}
+
+			handler.Handle(resp, req)
+
+			res := recorder.Result()
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, res.Body.Close())
+			require.NoError(t, err)
+			assert.JSONEq(t, c.expected, string(body))
+		})
+	}
+}
+
 func TestParseErrorStatusHandler(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -167,6 +212,24 @@ func TestParseErrorStatusHandler(t *testing.T) {
 			expectedMessage: "Failed to read request body due to connection issues, timeouts, size mismatches, or corrupted data.",
 			expectedStatus:  http.StatusBadRequest,
 		},
+		{
+			name:            "InvalidBody",
+			err:             ErrInvalidBody,
+			expectedMessage: "The request body could not be decoded according to its Content-Type.",
+			expectedStatus:  http.StatusBadRequest,
+		},
+		{
+			name:            "UnsupportedContentType",
+			err:             ErrUnsupportedContentType,
+			expectedMessage: "The request Content-Type is not supported.",
+			expectedStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:            "UnsupportedContentEncoding",
+			err:             ErrUnsupportedContentEncoding,
+			expectedMessage: "The request Content-Encoding is not supported.",
+			expectedStatus:  http.StatusUnsupportedMediaType,
+		},
 		{
 			name:            "OtherError",
 			err:             errors.New("some.other.error"),
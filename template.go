@@ -0,0 +1,303 @@
+package goyave
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"io/fs"
+	"strings"
+	"sync"
+
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+const (
+	// TemplateLayoutsDirectory the directory containing the layouts, relative
+	// to the templates root directory.
+	TemplateLayoutsDirectory = "layouts"
+
+	// TemplatePartialsDirectory the directory containing the partials, relative
+	// to the templates root directory.
+	TemplatePartialsDirectory = "partials"
+
+	// TemplateExtension the extension of the template files.
+	TemplateExtension = ".html"
+
+	// DefaultCSRFFieldName the name of the form field generated by the
+	// "csrf_field" template function.
+	DefaultCSRFFieldName = "_csrf"
+)
+
+// Templates container for the HTML templates used by `Response.Render()`.
+//
+// Templates are loaded from a directory with the following structure:
+//
+//	templates
+//	  ├─ layouts          (layouts, named "layouts/<name>")
+//	  │   └─ main.html
+//	  ├─ partials         (partials, named "partials/<name>")
+//	  │   └─ nav.html
+//	  └─ users
+//	      └─ index.html   (page, named "users/index")
+//
+// Each page is parsed together with all the layouts and partials, so they can
+// be referenced with the `template` action. Layouts are expected to include the
+// page content using the `block` or `template` actions, the page defining the
+// corresponding templates (e.g. `{{ define "content" }}...{{ end }}`).
+//
+// In addition to the functions provided by the `html/template` package, the
+// following functions are available in all templates:
+//   - `route "name" "param"...`: builds the full URL to the named route (see `Route.BuildURL()`)
+//   - `trans "line" ":placeholder" "value"...`: translates the line using the request's language (see `lang.Language.Get()`)
+//   - `lang`: the name of the request's language
+//   - `csrf_token`: the CSRF token of the current request, if any
+//   - `csrf_field`: a hidden input field containing the CSRF token of the current request, named after
+//     the CSRF middleware's field name ("_csrf" by default)
+//   - `csp_nonce`: the Content-Security-Policy nonce of the current request, if any (e.g. `<script nonce="{{ csp_nonce }}">`)
+//
+// If debugging is enabled, templates are reloaded before every render so changes
+// are reflected without restarting the server.
+//
+// This structure is safe for concurrent use.
+type Templates struct {
+	server *Server
+	fs     fsutil.FS
+	funcs  template.FuncMap
+	pages  map[string]*template.Template
+
+	// DefaultLayout the name of the layout used by `Response.Render()`,
+	// without the "layouts/" prefix. If empty, pages are rendered without layout.
+	DefaultLayout string
+
+	directory string
+
+	mu sync.RWMutex
+}
+
+// NewTemplates create a new `Templates` for the given server. The templates are
+// loaded from the given directory of the given file system.
+// The given functions are made available to all templates in addition to the built-in functions.
+func NewTemplates(server *Server, fs fsutil.FS, directory string, funcs template.FuncMap) (*Templates, error) {
+	t := &Templates{
+		server:    server,
+		fs:        fs,
+		directory: directory,
+		funcs:     template.FuncMap{},
+	}
+	for k, v := range t.requestFuncs(nil) {
+		t.funcs[k] = v
+	}
+	for k, v := range funcs {
+		t.funcs[k] = v
+	}
+
+	if err := t.Load(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Load (re)parse all the templates. On failure, the previously loaded templates are kept.
+func (t *Templates) Load() error {
+	base := template.New("").Funcs(t.funcs)
+	pageFiles := []string{}
+
+	err := fs.WalkDir(t.fs, t.directory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, TemplateExtension) {
+			return nil
+		}
+		name := t.templateName(path)
+		if strings.HasPrefix(name, TemplateLayoutsDirectory+"/") || strings.HasPrefix(name, TemplatePartialsDirectory+"/") {
+			return t.parseFile(base.New(name), path)
+		}
+		pageFiles = append(pageFiles, path)
+		return nil
+	})
+	if err != nil {
+		return errors.New(err)
+	}
+
+	pages := make(map[string]*template.Template, len(pageFiles))
+	for _, path := range pageFiles {
+		set, err := base.Clone()
+		if err != nil {
+			return errors.New(err)
+		}
+		name := t.templateName(path)
+		if err := t.parseFile(set.New(name), path); err != nil {
+			return err
+		}
+		pages[name] = set
+	}
+
+	t.mu.Lock()
+	t.pages = pages
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *Templates) templateName(path string) string {
+	name := path
+	if t.directory != "." {
+		name = strings.TrimPrefix(name, t.directory+"/")
+	}
+	return strings.TrimSuffix(name, TemplateExtension)
+}
+
+func (t *Templates) parseFile(tmpl *template.Template, path string) error {
+	f, err := t.fs.Open(path)
+	if err != nil {
+		return errors.New(err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return errors.New(err)
+	}
+	if _, err := tmpl.Parse(string(content)); err != nil {
+		return errors.New(err)
+	}
+	return nil
+}
+
+// Has returns true if a page identified by the given name exists.
+func (t *Templates) Has(name string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	_, ok := t.pages[name]
+	return ok
+}
+
+// Execute the page identified by the given name and writes the output to the given writer.
+// If the given layout is not empty, the layout is executed instead of the page. The layout name
+// should not include the "layouts/" prefix.
+//
+// The given request is used by the request-scoped template functions (translations, CSRF, etc).
+// It can be `nil`.
+func (t *Templates) Execute(w io.Writer, name, layout string, data any, request *Request) error {
+	if t.server.Config().GetBool("app.debug") {
+		if err := t.Load(); err != nil {
+			return err
+		}
+	}
+
+	t.mu.RLock()
+	page, ok := t.pages[name]
+	t.mu.RUnlock()
+	if !ok {
+		return errors.Errorf("template %q does not exist", name)
+	}
+
+	page, err := page.Clone()
+	if err != nil {
+		return errors.New(err)
+	}
+	page.Funcs(t.requestFuncs(request))
+
+	if layout != "" {
+		return errors.New(page.ExecuteTemplate(w, TemplateLayoutsDirectory+"/"+layout, data))
+	}
+	return errors.New(page.ExecuteTemplate(w, name, data))
+}
+
+func (t *Templates) requestFuncs(request *Request) template.FuncMap {
+	return template.FuncMap{
+		"route": func(name string, parameters ...string) (string, error) {
+			route := t.server.Router().GetRoute(name)
+			if route == nil {
+				return "", errors.Errorf("route %q does not exist", name)
+			}
+			return route.BuildRequestURL(request, parameters...), nil
+		},
+		"trans": func(line string, placeholders ...string) string {
+			if request == nil || request.Lang == nil {
+				return t.server.Lang.GetDefault().Get(line, placeholders...)
+			}
+			return request.Lang.Get(line, placeholders...)
+		},
+		"lang": func() string {
+			if request == nil || request.Lang == nil {
+				return t.server.Lang.Default
+			}
+			return request.Lang.Name()
+		},
+		"csrf_token": func() string {
+			return csrfToken(request)
+		},
+		"csrf_field": func() template.HTML {
+			return template.HTML(`<input type="hidden" name="` + template.HTMLEscapeString(csrfFieldName(request)) + `" value="` + template.HTMLEscapeString(csrfToken(request)) + `">`)
+		},
+		"csp_nonce": func() string {
+			if request == nil {
+				return ""
+			}
+			nonce, _ := request.Extra[ExtraCSPNonce{}].(string)
+			return nonce
+		},
+	}
+}
+
+func csrfToken(request *Request) string {
+	if request == nil {
+		return ""
+	}
+	token, _ := request.Extra[ExtraCSRFToken{}].(string)
+	return token
+}
+
+func csrfFieldName(request *Request) string {
+	if request == nil {
+		return DefaultCSRFFieldName
+	}
+	if name, ok := request.Extra[ExtraCSRFFieldName{}].(string); ok && name != "" {
+		return name
+	}
+	return DefaultCSRFFieldName
+}
+
+// Render the page identified by the given name using the given data and the
+// default layout (`Templates.DefaultLayout`). The output is written as a response
+// with the "text/html; charset=utf-8" content type.
+//
+// The page is fully rendered before anything is written to the response, so
+// rendering errors don't result in partial output.
+//
+// Panics if templates are not enabled (see `Options.TemplateFS`) or if rendering failed.
+func (r *Response) Render(status int, name string, data any) {
+	layout := ""
+	if templates := r.server.Templates; templates != nil {
+		layout = templates.DefaultLayout
+	}
+	r.render(status, layout, name, data)
+}
+
+// RenderWithLayout is the same as `Render` but uses the given layout instead of the default one.
+// The layout name should not include the "layouts/" prefix. If the given layout is empty,
+// the page is rendered without layout.
+func (r *Response) RenderWithLayout(status int, layout, name string, data any) {
+	r.render(status, layout, name, data)
+}
+
+func (r *Response) render(status int, layout, name string, data any) {
+	templates := r.server.Templates
+	if templates == nil {
+		panic(errors.NewSkip("templates are not enabled, no TemplateFS provided in server options", 4))
+	}
+
+	var buf bytes.Buffer
+	if err := templates.Execute(&buf, name, layout, data, r.request); err != nil {
+		panic(errors.NewSkip(err, 4))
+	}
+
+	r.responseWriter.Header().Set("Content-Type", "text/html; charset=utf-8")
+	r.status = status
+	if _, err := r.Write(buf.Bytes()); err != nil {
+		panic(errors.NewSkip(err, 4))
+	}
+}
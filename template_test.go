@@ -0,0 +1,195 @@
+package goyave
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+func prepareTemplateTest(t *testing.T, files fstest.MapFS) (*Server, fstest.MapFS) {
+	cfg := config.LoadDefault()
+	cfg.Set("app.debug", false)
+	server, err := New(Options{
+		Config:     cfg,
+		TemplateFS: fsutil.NewEmbed(files),
+		TemplateFuncs: template.FuncMap{
+			"upper": strings.ToUpper,
+		},
+	})
+	require.NoError(t, err)
+	return server, files
+}
+
+func templateTestFiles() fstest.MapFS {
+	return fstest.MapFS{
+		"layouts/main.html":   {Data: []byte(`<html lang="{{ lang }}">{{ template "partials/nav" . }}{{ block "content" . }}{{ end }}</html>`)},
+		"partials/nav.html":   {Data: []byte(`<nav>{{ route "home" }}</nav>`)},
+		"users/index.html":    {Data: []byte(`{{ define "content" }}<p>{{ upper .Name }} {{ trans "malformed-request" }}</p>{{ end }}`)},
+		"standalone.html":     {Data: []byte(`<p>{{ .Name }}</p>{{ csrf_field }}{{ csrf_token }}`)},
+		"script.html":         {Data: []byte(`<script nonce="{{ csp_nonce }}"></script>`)},
+		"ignored/notice.txt":  {Data: []byte(`not a template`)},
+		"layouts/second.html": {Data: []byte(`<main>{{ template "content" . }}</main>`)},
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	t.Run("NewTemplates", func(t *testing.T) {
+		server, _ := prepareTemplateTest(t, templateTestFiles())
+		require.NotNil(t, server.Templates)
+		assert.True(t, server.Templates.Has("users/index"))
+		assert.True(t, server.Templates.Has("standalone"))
+		assert.False(t, server.Templates.Has("layouts/main"))
+		assert.False(t, server.Templates.Has("partials/nav"))
+		assert.False(t, server.Templates.Has("ignored/notice"))
+	})
+
+	t.Run("no_template_fs", func(t *testing.T) {
+		server, err := New(Options{Config: config.LoadDefault()})
+		require.NoError(t, err)
+		assert.Nil(t, server.Templates)
+	})
+
+	t.Run("parse_error", func(t *testing.T) {
+		_, err := New(Options{
+			Config: config.LoadDefault(),
+			TemplateFS: fsutil.NewEmbed(fstest.MapFS{
+				"page.html": {Data: []byte(`{{ .Name `)},
+			}),
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("Execute_with_layout", func(t *testing.T) {
+		server, _ := prepareTemplateTest(t, templateTestFiles())
+		server.Router().Get("/", nil).Name("home")
+
+		buf := &bytes.Buffer{}
+		err := server.Templates.Execute(buf, "users/index", "main", map[string]any{"Name": "john"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, `<html lang="en-US"><nav>`+server.BaseURL()+`/</nav><p>JOHN Malformed request</p></html>`, buf.String())
+
+		buf.Reset()
+		err = server.Templates.Execute(buf, "users/index", "second", map[string]any{"Name": "john"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, `<main><p>JOHN Malformed request</p></main>`, buf.String())
+	})
+
+	t.Run("Execute_unknown", func(t *testing.T) {
+		server, _ := prepareTemplateTest(t, templateTestFiles())
+		err := server.Templates.Execute(io.Discard, "unknown", "", nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("Execute_unknown_route", func(t *testing.T) {
+		server, _ := prepareTemplateTest(t, templateTestFiles())
+		err := server.Templates.Execute(io.Discard, "users/index", "main", map[string]any{"Name": "john"}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("hot_reload", func(t *testing.T) {
+		server, files := prepareTemplateTest(t, templateTestFiles())
+		files["standalone.html"] = &fstest.MapFile{Data: []byte(`reloaded`)}
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, server.Templates.Execute(buf, "standalone", "", map[string]any{"Name": "john"}, nil))
+		assert.NotEqual(t, "reloaded", buf.String())
+
+		server.Config().Set("app.debug", true)
+		buf.Reset()
+		require.NoError(t, server.Templates.Execute(buf, "standalone", "", nil, nil))
+		assert.Equal(t, "reloaded", buf.String())
+	})
+}
+
+func TestResponseRender(t *testing.T) {
+	t.Run("Render", func(t *testing.T) {
+		server, _ := prepareTemplateTest(t, templateTestFiles())
+		server.Router().Get("/", nil).Name("home")
+		server.Templates.DefaultLayout = "main"
+
+		req := NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		req.Lang = server.Lang.GetDefault()
+		recorder := httptest.NewRecorder()
+		resp := NewResponse(server, req, recorder)
+
+		resp.Render(http.StatusCreated, "users/index", map[string]any{"Name": "john"})
+
+		res := recorder.Result()
+		body, err := io.ReadAll(res.Body)
+		assert.NoError(t, res.Body.Close())
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, "text/html; charset=utf-8", res.Header.Get("Content-Type"))
+		assert.Equal(t, `<html lang="en-US"><nav>`+server.BaseURL()+`/</nav><p>JOHN Malformed request</p></html>`, string(body))
+	})
+
+	t.Run("RenderWithLayout_csrf", func(t *testing.T) {
+		server, _ := prepareTemplateTest(t, templateTestFiles())
+		server.Templates.DefaultLayout = "main"
+
+		req := NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		req.Extra[ExtraCSRFToken{}] = `tok"en`
+		recorder := httptest.NewRecorder()
+		resp := NewResponse(server, req, recorder)
+
+		resp.RenderWithLayout(http.StatusOK, "", "standalone", map[string]any{"Name": "<b>john</b>"})
+
+		res := recorder.Result()
+		body, err := io.ReadAll(res.Body)
+		assert.NoError(t, res.Body.Close())
+		require.NoError(t, err)
+		assert.Equal(t, `<p>&lt;b&gt;john&lt;/b&gt;</p><input type="hidden" name="_csrf" value="tok&#34;en">tok&#34;en`, string(body))
+
+		// Custom field name set by the CSRF middleware
+		req.Extra[ExtraCSRFFieldName{}] = "custom_token"
+		buf := &bytes.Buffer{}
+		require.NoError(t, server.Templates.Execute(buf, "standalone", "", map[string]any{"Name": "john"}, req))
+		assert.Equal(t, `<p>john</p><input type="hidden" name="custom_token" value="tok&#34;en">tok&#34;en`, buf.String())
+	})
+
+	t.Run("csp_nonce", func(t *testing.T) {
+		server, _ := prepareTemplateTest(t, templateTestFiles())
+		req := NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		buf := &bytes.Buffer{}
+		require.NoError(t, server.Templates.Execute(buf, "script", "", nil, req))
+		assert.Equal(t, `<script nonce=""></script>`, buf.String())
+
+		req.Extra[ExtraCSPNonce{}] = "abc123"
+		buf.Reset()
+		require.NoError(t, server.Templates.Execute(buf, "script", "", nil, req))
+		assert.Equal(t, `<script nonce="abc123"></script>`, buf.String())
+	})
+
+	t.Run("render_error", func(t *testing.T) {
+		server, _ := prepareTemplateTest(t, templateTestFiles())
+		req := NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		recorder := httptest.NewRecorder()
+		resp := NewResponse(server, req, recorder)
+
+		assert.Panics(t, func() {
+			resp.Render(http.StatusOK, "unknown", nil)
+		})
+		assert.True(t, resp.IsEmpty())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		server, err := New(Options{Config: config.LoadDefault()})
+		require.NoError(t, err)
+		req := NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		resp := NewResponse(server, req, httptest.NewRecorder())
+
+		assert.Panics(t, func() {
+			resp.Render(http.StatusOK, "page", nil)
+		})
+	})
+}
@@ -0,0 +1,235 @@
+package goyave
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	errorutil "goyave.dev/goyave/v5/util/errors"
+)
+
+// timeoutKey context key marking requests already handled by a `TimeoutMiddleware`.
+type timeoutKey struct{}
+
+// TimeoutMiddleware limits the time spent executing the handler (and the middleware executed
+// after this one). The request context is derived with a deadline, so database queries
+// and downstream calls using `request.Context()` are canceled when the deadline is exceeded.
+//
+// The timeout is defined by the `MetaTimeout` meta of the matched route (or its parent routers),
+// or by the middleware's `Timeout` if there is no such meta. A timeout lower or equal to 0
+// disables the middleware, as well as requests already subject to a timeout set by another
+// `TimeoutMiddleware`. This middleware is automatically added globally
+// when using `Route.Timeout()` or `Router.Timeout()`.
+//
+// The handler is executed in a separate goroutine, with a copy of the request and a buffered
+// response. If it completes before the deadline, the buffered response is written. Otherwise,
+// the middleware immediately responds with the `Status` code (processed by the status handlers),
+// even if the handler is still running. All writes made by the handler after the deadline are
+// discarded and return `http.ErrHandlerTimeout`. In this case, a channel closed when the handler
+// returns is stored in the request's extra `ExtraHandlerDone`, so middleware releasing resources
+// used by the handler (such as uploaded files) can wait for it.
+//
+// The handler's request has its own `Extra`, and deep copies of `Data` and `Query`,
+// so the status handlers and middleware executed after a timeout can read them while
+// the handler is still running. `User` is not copied and must therefore not be modified
+// by a handler subject to a timeout.
+//
+// Because the response is buffered, flushing and hijacking are not supported
+// in handlers subject to a timeout.
+//
+// Panics occurring in the handler before the deadline are propagated
+// so they are handled by the recovery middleware.
+type TimeoutMiddleware struct {
+	Component
+
+	// Timeout the default timeout, used if the route doesn't define the `MetaTimeout` meta.
+	Timeout time.Duration
+
+	// Status the status code returned when the deadline is exceeded.
+	// Defaults to "503 Service Unavailable".
+	Status int
+}
+
+// Handle executes the next handler with a deadline.
+func (m *TimeoutMiddleware) Handle(next Handler) Handler {
+	return func(response *Response, request *Request) {
+		timeout := m.Timeout
+		if t, ok := request.Route.LookupMeta(MetaTimeout); ok {
+			timeout, _ = t.(time.Duration)
+		}
+		if timeout <= 0 || request.Context().Value(timeoutKey{}) != nil {
+			next(response, request)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(request.Context(), timeout)
+		defer cancel()
+		ctx = context.WithValue(ctx, timeoutKey{}, true)
+
+		handlerRequest := &Request{}
+		*handlerRequest = *request
+		handlerRequest.httpRequest = request.httpRequest.WithContext(ctx)
+		handlerRequest.Extra = maps.Clone(request.Extra)
+		handlerRequest.Data = cloneRequestData(request.Data)
+		handlerRequest.Query = cloneRequestData(request.Query).(map[string]any)
+
+		writer := &timeoutWriter{header: response.Header().Clone()}
+		handlerResponse := &Response{}
+		handlerResponse.reset(m.server, handlerRequest, writer)
+
+		done := make(chan struct{})
+		var panicErr error
+		go func() {
+			defer close(done)
+			defer func() {
+				if err := recover(); err != nil {
+					if writer.isTimedOut() {
+						if e, ok := err.(error); !ok || !errors.Is(e, http.ErrHandlerTimeout) {
+							m.Logger().Error(errorutil.NewSkip(err, 4))
+						}
+						return
+					}
+					panicErr = errorutil.NewSkip(err, 4) // Skipped: runtime.Callers, NewSkip, this func, runtime.panic
+				}
+			}()
+			next(handlerResponse, handlerRequest)
+			if err := handlerResponse.close(); err != nil {
+				m.Logger().Error(err)
+			}
+		}()
+
+		select {
+		case <-done:
+			if panicErr != nil {
+				panic(panicErr)
+			}
+			m.copyResponse(response, handlerResponse, writer)
+			request.Data = handlerRequest.Data
+			request.User = handlerRequest.User
+			request.Query = handlerRequest.Query
+			request.Lang = handlerRequest.Lang
+			maps.Copy(request.Extra, handlerRequest.Extra)
+		case <-ctx.Done():
+			writer.timeout()
+			request.Extra[ExtraHandlerDone{}] = (<-chan struct{})(done)
+			status := m.Status
+			if status == 0 {
+				status = http.StatusServiceUnavailable
+			}
+			response.Status(status)
+		}
+	}
+}
+
+func (m *TimeoutMiddleware) copyResponse(response *Response, handlerResponse *Response, writer *timeoutWriter) {
+	header := response.Header()
+	for k := range header {
+		if _, ok := writer.header[k]; !ok {
+			delete(header, k)
+		}
+	}
+	maps.Copy(header, writer.header)
+
+	if handlerResponse.err != nil {
+		response.err = handlerResponse.err
+	}
+	switch {
+	case !handlerResponse.empty:
+		response.status = handlerResponse.status
+		if _, err := response.Write(writer.body.Bytes()); err != nil {
+			m.Logger().Error(errorutil.New(err))
+		}
+	case handlerResponse.wroteHeader:
+		response.WriteHeader(handlerResponse.status)
+	case handlerResponse.status != 0:
+		response.status = handlerResponse.status
+	}
+}
+
+// timeoutWriter buffers the response of a handler executed by the `TimeoutMiddleware`.
+// Once timed out, all writes are discarded.
+type timeoutWriter struct {
+	header   http.Header
+	body     bytes.Buffer
+	mu       sync.Mutex
+	timedOut bool
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	return w.body.Write(b)
+}
+
+func (w *timeoutWriter) WriteHeader(_ int) {}
+
+func (w *timeoutWriter) timeout() {
+	w.mu.Lock()
+	w.timedOut = true
+	w.mu.Unlock()
+}
+
+func (w *timeoutWriter) isTimedOut() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.timedOut
+}
+
+// cloneRequestData returns a deep copy of the given request data or query.
+// Maps and slices are copied recursively, other values are copied as is.
+func cloneRequestData(data any) any {
+	switch d := data.(type) {
+	case map[string]any:
+		if d == nil {
+			return d
+		}
+		clone := make(map[string]any, len(d))
+		for k, v := range d {
+			clone[k] = cloneRequestData(v)
+		}
+		return clone
+	case []any:
+		if d == nil {
+			return d
+		}
+		clone := make([]any, len(d))
+		for i, v := range d {
+			clone[i] = cloneRequestData(v)
+		}
+		return clone
+	}
+
+	value := reflect.ValueOf(data)
+	switch value.Kind() {
+	case reflect.Slice:
+		if value.IsNil() {
+			return data
+		}
+		clone := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		reflect.Copy(clone, value)
+		return clone.Interface()
+	case reflect.Map:
+		if value.IsNil() {
+			return data
+		}
+		clone := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			clone.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return clone.Interface()
+	}
+	return data
+}
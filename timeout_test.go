@@ -0,0 +1,236 @@
+package goyave
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/slog"
+	errorutil "goyave.dev/goyave/v5/util/errors"
+)
+
+type testTimeoutExtraKey struct{}
+
+type testTimeoutMiddleware struct {
+	Component
+	handle func(next Handler) Handler
+}
+
+func (m *testTimeoutMiddleware) Handle(next Handler) Handler {
+	return m.handle(next)
+}
+
+func prepareTimeoutTest(t *testing.T) *Server {
+	server, err := New(Options{Config: config.LoadDefault()})
+	require.NoError(t, err)
+	return server
+}
+
+func serveTimeoutTest(server *Server, method, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	server.Router().ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	return recorder
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	t.Run("completed", func(t *testing.T) {
+		server := prepareTimeoutTest(t)
+		var request *Request
+		server.Router().GlobalMiddleware(&testTimeoutMiddleware{
+			handle: func(next Handler) Handler {
+				return func(response *Response, r *Request) {
+					request = r
+					response.Header().Set("X-Outer", "outer")
+					response.Header().Set("X-Removed", "removed")
+					next(response, r)
+				}
+			},
+		})
+		server.Router().Get("/test", func(response *Response, r *Request) {
+			deadline, ok := r.Context().Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+			assert.Equal(t, "outer", response.Header().Get("X-Outer"))
+			response.Header().Del("X-Removed")
+			response.Header().Set("X-Inner", "inner")
+			r.Extra[testTimeoutExtraKey{}] = "value"
+			r.User = "user"
+			response.String(http.StatusCreated, "hello")
+		}).Timeout(time.Second)
+
+		recorder := serveTimeoutTest(server, http.MethodGet, "/test")
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, "hello", recorder.Body.String())
+		assert.Equal(t, "outer", recorder.Header().Get("X-Outer"))
+		assert.Equal(t, "inner", recorder.Header().Get("X-Inner"))
+		assert.Empty(t, recorder.Header().Get("X-Removed"))
+		assert.Equal(t, "value", request.Extra[testTimeoutExtraKey{}])
+		assert.Equal(t, "user", request.User)
+	})
+
+	t.Run("status_only", func(t *testing.T) {
+		server := prepareTimeoutTest(t)
+		server.Router().Get("/test", func(response *Response, _ *Request) {
+			response.Status(http.StatusNotFound)
+		}).Timeout(time.Second)
+		server.Router().Get("/header", func(response *Response, _ *Request) {
+			response.WriteHeader(http.StatusAccepted)
+		}).Timeout(time.Second)
+
+		recorder := serveTimeoutTest(server, http.MethodGet, "/test")
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Equal(t, "{\"error\":\"Not Found\"}\n", recorder.Body.String())
+
+		recorder = serveTimeoutTest(server, http.MethodGet, "/header")
+		assert.Equal(t, http.StatusAccepted, recorder.Code)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := prepareTimeoutTest(t)
+		handlerDone := make(chan struct{})
+		var ctxErr, writeErr error
+		server.Router().Get("/test", func(response *Response, r *Request) {
+			defer close(handlerDone)
+			<-r.Context().Done()
+			ctxErr = r.Context().Err()
+			time.Sleep(10 * time.Millisecond) // Let the middleware respond
+			response.Header().Set("X-Late", "late")
+			_, writeErr = response.Write([]byte("late"))
+			response.String(http.StatusOK, "late") // Panics, recovered silently
+		}).Timeout(20 * time.Millisecond)
+
+		recorder := serveTimeoutTest(server, http.MethodGet, "/test")
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		assert.Equal(t, "{\"error\":\"Service Unavailable\"}\n", recorder.Body.String())
+
+		<-handlerDone
+		assert.Equal(t, context.DeadlineExceeded, ctxErr)
+		require.ErrorIs(t, writeErr, http.ErrHandlerTimeout)
+		assert.Empty(t, recorder.Header().Get("X-Late"))
+		assert.NotContains(t, recorder.Body.String(), "late")
+	})
+
+	t.Run("timeout_data_copied", func(t *testing.T) {
+		server := prepareTimeoutTest(t)
+		var request *Request
+		server.Router().GlobalMiddleware(&testTimeoutMiddleware{
+			handle: func(next Handler) Handler {
+				return func(response *Response, r *Request) {
+					request = r
+					r.Data = map[string]any{"object": map[string]any{"a": 1}, "array": []any{1}, "strings": []string{"a"}}
+					r.Query = map[string]any{"page": 1}
+					next(response, r)
+				}
+			},
+		})
+		handlerDone := make(chan struct{})
+		server.Router().Get("/test", func(_ *Response, r *Request) {
+			defer close(handlerDone)
+			<-r.Context().Done()
+			time.Sleep(10 * time.Millisecond) // Let the middleware respond
+			data := r.Data.(map[string]any)
+			data["object"].(map[string]any)["a"] = 2
+			data["array"].([]any)[0] = 2
+			data["strings"].([]string)[0] = "b"
+			data["added"] = true
+			r.Query["page"] = 2
+		}).Timeout(20 * time.Millisecond)
+
+		recorder := serveTimeoutTest(server, http.MethodGet, "/test")
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		<-handlerDone
+		assert.Equal(t, map[string]any{"object": map[string]any{"a": 1}, "array": []any{1}, "strings": []string{"a"}}, request.Data)
+		assert.Equal(t, map[string]any{"page": 1}, request.Query)
+	})
+
+	t.Run("timeout_wrapped_error_not_logged", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		server, err := New(Options{Config: config.LoadDefault(), Logger: slog.New(slog.NewHandler(false, buffer))})
+		require.NoError(t, err)
+		handlerDone := make(chan struct{})
+		server.Router().Get("/test", func(_ *Response, r *Request) {
+			defer close(handlerDone)
+			<-r.Context().Done()
+			time.Sleep(10 * time.Millisecond) // Let the middleware respond
+			panic(errorutil.New(fmt.Errorf("late write: %w", http.ErrHandlerTimeout)))
+		}).Timeout(20 * time.Millisecond)
+
+		recorder := serveTimeoutTest(server, http.MethodGet, "/test")
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		<-handlerDone
+		time.Sleep(10 * time.Millisecond) // Let the recover execute
+		assert.Empty(t, buffer.String())
+	})
+
+	t.Run("explicit_middleware", func(t *testing.T) {
+		server := prepareTimeoutTest(t)
+		router := server.Router().Subrouter("/api")
+		router.Middleware(&TimeoutMiddleware{Timeout: 10 * time.Millisecond, Status: http.StatusGatewayTimeout})
+		router.Get("/slow", func(_ *Response, r *Request) {
+			<-r.Context().Done()
+		})
+		router.Get("/disabled", func(response *Response, r *Request) {
+			_, ok := r.Context().Deadline()
+			assert.False(t, ok)
+			response.Status(http.StatusOK)
+		}).Timeout(0)
+
+		recorder := serveTimeoutTest(server, http.MethodGet, "/api/slow")
+		assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+		assert.Equal(t, "{\"error\":\"Gateway Timeout\"}\n", recorder.Body.String())
+
+		recorder = serveTimeoutTest(server, http.MethodGet, "/api/disabled")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		server := prepareTimeoutTest(t)
+		router := server.Router().Subrouter("/api").Timeout(time.Second)
+		router.Middleware(&TimeoutMiddleware{Timeout: time.Millisecond})
+		router.Get("/test", func(response *Response, r *Request) {
+			deadline, ok := r.Context().Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+			response.Status(http.StatusOK)
+		})
+		recorder := serveTimeoutTest(server, http.MethodGet, "/api/test")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		server := prepareTimeoutTest(t)
+		server.Router().Get("/test", func(_ *Response, _ *Request) {
+			panic("test panic")
+		}).Timeout(time.Second)
+
+		recorder := serveTimeoutTest(server, http.MethodGet, "/test")
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		body, err := io.ReadAll(recorder.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "test panic")
+	})
+}
+
+func TestTimeoutMeta(t *testing.T) {
+	router := prepareRouterTest()
+	route := router.Get("/test", nil).Timeout(time.Second)
+	assert.Equal(t, time.Second, route.Meta[MetaTimeout])
+	assert.True(t, hasMiddleware[*TimeoutMiddleware](router.globalMiddleware.middleware))
+
+	router.Timeout(2 * time.Second)
+	router.Subrouter("/sub").Timeout(0)
+	assert.Equal(t, 2*time.Second, router.Meta[MetaTimeout])
+	assert.Len(t, router.globalMiddleware.middleware, 4) // Recovery, language, maintenance and timeout
+
+	router = prepareRouterTest()
+	router.Timeout(0)
+	assert.False(t, hasMiddleware[*TimeoutMiddleware](router.globalMiddleware.middleware))
+}
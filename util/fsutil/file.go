@@ -14,12 +14,12 @@ import (
 	"goyave.dev/goyave/v5/util/errors"
 )
 
-// marshalCache temporarily stores files' `*multipart.FileHeader`. This type
+// marshalCache temporarily stores files' `*multipart.FileHeader` and storage. These
 // cannot be marshaled, making the use of `fsutil.file` inconvenient with DTO conversion.
 // The key should a be unique ID. The key is removed from the map.
 // To avoid infinite growth of this cache, leading to potential memory problems, this map
 // is reset every time its length goes back to 0.
-var marshalCache = map[string]*multipart.FileHeader{}
+var marshalCache = map[string]File{}
 var cacheMu sync.RWMutex
 
 // File represents a file received from client.
@@ -32,9 +32,20 @@ var cacheMu sync.RWMutex
 // retrieved then deleted from the cache. To avoid orphans clogging up the cache, you should
 // never JSON marshal this type outside of `typeutil.Convert()`: if a marshaled File never gets
 // unmarshaled, its UUID would remain in the cache forever.
+//
+// If the file was streamed to a file system by the parse middleware (`StreamUploads` option), `FS` and `Path` identify
+// where its content is stored. In this case, `Header.Open()` cannot be used: use `File.Open()` instead.
 type File struct {
-	Header   *multipart.FileHeader
+	Header *multipart.FileHeader
+
+	// FS the file system the content of the file is stored in.
+	// If nil, the content is held by `Header`.
+	FS WritableFS
+
 	MIMEType string
+
+	// Path the path of the file's content in `FS`.
+	Path string
 }
 
 type marshaledFile struct {
@@ -51,7 +62,7 @@ func (file File) MarshalJSON() ([]byte, error) {
 
 	uidStr := headerUID.String()
 	cacheMu.Lock()
-	marshalCache[uidStr] = file.Header
+	marshalCache[uidStr] = file
 	cacheMu.Unlock()
 
 	return json.Marshal(marshaledFile{
@@ -70,7 +81,7 @@ func (file *File) UnmarshalJSON(data []byte) error {
 	file.MIMEType = v.MIMEType
 
 	cacheMu.RLock()
-	header, ok := marshalCache[v.Header]
+	cached, ok := marshalCache[v.Header]
 	cacheMu.RUnlock()
 	if !ok {
 		return errors.New("cannot unmarshal fsutil.File: multipart header not found in cache")
@@ -81,14 +92,32 @@ func (file *File) UnmarshalJSON(data []byte) error {
 	if len(marshalCache) == 0 {
 		// Maps never shrink, let's allocate a new empty map to reset the cache capacity
 		// and allow garbage collecting.
-		marshalCache = map[string]*multipart.FileHeader{}
+		marshalCache = map[string]File{}
 	}
 	cacheMu.Unlock()
 
-	file.Header = header
+	file.Header = cached.Header
+	file.FS = cached.FS
+	file.Path = cached.Path
 	return nil
 }
 
+// Open opens the file's content for reading. If the file is stored in a file system
+// (`FS` is not nil), the file at `Path` is opened. Otherwise, `Header.Open()` is used.
+func (file *File) Open() (io.ReadCloser, error) {
+	var f io.ReadCloser
+	var err error
+	if file.FS != nil {
+		f, err = file.FS.OpenFile(file.Path, os.O_RDONLY, 0)
+	} else {
+		f, err = file.Header.Open()
+	}
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return f, nil
+}
+
 // Save writes the file's content to a new file in the given file system.
 // Appends a timestamp to the given file name to avoid duplicate file names.
 // The file is not readable anymore once saved as its FileReader has already been
@@ -107,10 +136,9 @@ func (file *File) Save(fs WritableFS, path string, name string) (filename string
 		}
 	}
 
-	var f multipart.File
-	f, err = file.Header.Open()
+	var f io.ReadCloser
+	f, err = file.Open()
 	if err != nil {
-		err = errors.New(err)
 		return
 	}
 	defer func() {
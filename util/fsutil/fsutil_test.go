@@ -194,6 +194,61 @@ func TestSave(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestFileOpen(t *testing.T) {
+	t.Run("header", func(t *testing.T) {
+		file := createTestFiles("resources/img/logo/goyave_16.png")[0]
+		f, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		assert.Len(t, content, 630)
+	})
+
+	t.Run("fs", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(dir+"/upload", []byte("hello"), 0o600))
+		file := File{
+			Header: &multipart.FileHeader{Filename: "hello.txt", Size: 5},
+			FS:     osfs.New(dir),
+			Path:   "upload",
+		}
+		f, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		assert.Equal(t, "hello", string(content))
+
+		name, err := file.Save(osfs.New(dir), "saved", "hello.txt")
+		require.NoError(t, err)
+		content, err = os.ReadFile(dir + "/saved/" + name)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(content))
+
+		file.Path = "notafile"
+		_, err = file.Open()
+		require.Error(t, err)
+		_, err = file.Save(osfs.New(dir), "saved", "hello.txt")
+		require.Error(t, err)
+	})
+
+	t.Run("marshal", func(t *testing.T) {
+		type testDTO struct {
+			Files []File `json:"files"`
+		}
+		files := []File{{
+			Header:   &multipart.FileHeader{Filename: "hello.txt", Size: 5},
+			FS:       osfs.New(t.TempDir()),
+			MIMEType: "text/plain",
+			Path:     "upload",
+		}}
+		dto, err := typeutil.Convert[*testDTO](map[string]any{"files": files})
+		require.NoError(t, err)
+		assert.Equal(t, files, dto.Files)
+	})
+}
+
 func TestMarshalFile(t *testing.T) {
 	type testDTO struct {
 		Files []File `json:"files"`
@@ -0,0 +1,68 @@
+package validation
+
+import "slices"
+
+var (
+	acceptedValues = []string{"1", "on", "yes", "true"}
+	declinedValues = []string{"0", "off", "no", "false"}
+)
+
+// AcceptedValidator the field under validation must be "yes", "on", "1", "true",
+// `true` or a number equal to `1`.
+type AcceptedValidator struct{ BaseValidator }
+
+// Validate checks the field under validation satisfies this validator's criteria.
+func (v *AcceptedValidator) Validate(ctx *Context) bool {
+	return matchesBoolValue(ctx.Value, true, acceptedValues)
+}
+
+// Name returns the string name of the validator.
+func (v *AcceptedValidator) Name() string { return "accepted" }
+
+// Accepted the field under validation must be "yes", "on", "1", "true",
+// `true` or a number equal to `1`. This is useful for validating
+// "Terms of Service" acceptance or similar fields.
+//
+// Combine it with `Required()` if the field must be present.
+func Accepted() *AcceptedValidator {
+	return &AcceptedValidator{}
+}
+
+//------------------------------
+
+// DeclinedValidator the field under validation must be "no", "off", "0", "false",
+// `false` or a number equal to `0`.
+type DeclinedValidator struct{ BaseValidator }
+
+// Validate checks the field under validation satisfies this validator's criteria.
+func (v *DeclinedValidator) Validate(ctx *Context) bool {
+	return matchesBoolValue(ctx.Value, false, declinedValues)
+}
+
+// Name returns the string name of the validator.
+func (v *DeclinedValidator) Name() string { return "declined" }
+
+// Declined the field under validation must be "no", "off", "0", "false",
+// `false` or a number equal to `0`.
+//
+// Combine it with `Required()` if the field must be present.
+func Declined() *DeclinedValidator {
+	return &DeclinedValidator{}
+}
+
+func matchesBoolValue(value any, expected bool, values []string) bool {
+	switch val := value.(type) {
+	case bool:
+		return val == expected
+	case string:
+		return slices.Contains(values, val)
+	}
+	f, ok, err := numberAsFloat64(value)
+	if !ok || err != nil {
+		return false
+	}
+	if expected {
+		return f == 1
+	}
+	return f == 0
+}
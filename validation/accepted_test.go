@@ -0,0 +1,82 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcceptedValidator(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		v := Accepted()
+		assert.NotNil(t, v)
+		assert.Equal(t, "accepted", v.Name())
+		assert.False(t, v.IsType())
+		assert.False(t, v.IsTypeDependent())
+		assert.Empty(t, v.MessagePlaceholders(&Context{}))
+	})
+
+	cases := []struct {
+		value any
+		want  bool
+	}{
+		{value: true, want: true},
+		{value: "yes", want: true},
+		{value: "on", want: true},
+		{value: "1", want: true},
+		{value: "true", want: true},
+		{value: 1, want: true},
+		{value: 1.0, want: true},
+		{value: uint8(1), want: true},
+		{value: false, want: false},
+		{value: "no", want: false},
+		{value: "YES", want: false},
+		{value: 2, want: false},
+		{value: 0, want: false},
+		{value: nil, want: false},
+		{value: []string{"yes"}, want: false},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("Validate_%v_%t", c.value, c.want), func(t *testing.T) {
+			v := Accepted()
+			assert.Equal(t, c.want, v.Validate(&Context{Value: c.value}))
+		})
+	}
+}
+
+func TestDeclinedValidator(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		v := Declined()
+		assert.NotNil(t, v)
+		assert.Equal(t, "declined", v.Name())
+		assert.False(t, v.IsType())
+		assert.False(t, v.IsTypeDependent())
+		assert.Empty(t, v.MessagePlaceholders(&Context{}))
+	})
+
+	cases := []struct {
+		value any
+		want  bool
+	}{
+		{value: false, want: true},
+		{value: "no", want: true},
+		{value: "off", want: true},
+		{value: "0", want: true},
+		{value: "false", want: true},
+		{value: 0, want: true},
+		{value: 0.0, want: true},
+		{value: true, want: false},
+		{value: "yes", want: false},
+		{value: 1, want: false},
+		{value: nil, want: false},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("Validate_%v_%t", c.value, c.want), func(t *testing.T) {
+			v := Declined()
+			assert.Equal(t, c.want, v.Validate(&Context{Value: c.value}))
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/walk"
+)
+
+// BatchChunkSize the maximum number of values in a single `IN` query executed
+// when resolving the lookups registered by `BatchValidator`s.
+const BatchChunkSize = 1000
+
+var batchIdentifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// checkBatchIdentifiers returns an error if the given table or column are not plain
+// identifiers. The table can be prefixed with a schema name: "schema.table".
+func checkBatchIdentifiers(table, column string) error {
+	name := table
+	if schema, t, hasSchema := strings.Cut(table, "."); hasSchema {
+		if !batchIdentifierRegex.MatchString(schema) {
+			return fmt.Errorf("invalid table name %q", table)
+		}
+		name = t
+	}
+	if !batchIdentifierRegex.MatchString(name) {
+		return fmt.Errorf("invalid table name %q", table)
+	}
+	if !batchIdentifierRegex.MatchString(column) {
+		return fmt.Errorf("invalid column name %q", column)
+	}
+	return nil
+}
+
+// BatchLookup a database lookup of a single value in a column.
+type BatchLookup struct {
+	Value any
+
+	// Scope if not nil, applied to the query looking up the value.
+	Scope func(db *gorm.DB) *gorm.DB
+
+	// Normalize if not nil, applied to the looked up string and to the strings
+	// returned by the database before comparing them. See `BatchValidator`.
+	Normalize func(value string) string
+
+	Table  string
+	Column string
+}
+
+// BatchValidator is a `Validator` checking the existence of the value under validation
+// in the database. Instead of executing a query for each validated value, `validation.Validate()`
+// registers the lookups returned by `Lookup()` and resolves them all at the end of the
+// validation, using one `IN` query per table, column and scope. This avoids executing as many queries
+// as there are elements when validating array elements.
+//
+// The values returned by the database are compared with the looked up values. Numbers are matched
+// regardless of their type and strings are compared exactly. If the column uses a collation
+// matching values that are not strictly equal, such as case-insensitive or PAD SPACE collations,
+// the lookup must provide a `Normalize` function applied to both sides of the comparison
+// (for example `NormalizeCaseInsensitive`).
+//
+// Lookups with a `Scope` or a `Normalize` function are only batched with the lookups of the same
+// validator, as two functions cannot be compared. Using a single validator for the elements of
+// an array, for example with the path `items[].id`, still results in a single query.
+//
+// Because the lookups are resolved at the end of the validation, the error messages of batch
+// validators are added after the messages of the other validators of the field.
+type BatchValidator interface {
+	Validator
+
+	// Lookup returns the database lookup required to validate the field under validation.
+	// If it returns `false`, the lookup is not registered and `Validate()` is executed instead.
+	Lookup(ctx *Context) (BatchLookup, bool)
+
+	// ValidateLookup checks the field under validation satisfies this validator's criteria
+	// using the result of the lookup. `found` is true if the value exists in the database.
+	ValidateLookup(ctx *Context, found bool) bool
+}
+
+// NormalizeCaseInsensitive a `BatchLookup.Normalize` function for columns using a case-insensitive
+// PAD SPACE collation, such as MySQL's default collations: strings are converted to lower case
+// and their trailing spaces are removed.
+func NormalizeCaseInsensitive(value string) string {
+	return strings.ToLower(strings.TrimRight(value, " "))
+}
+
+type batchKey struct {
+	// validator the validator that registered the lookup if it has a scope
+	// or a normalization function, nil otherwise.
+	validator BatchValidator
+	table     string
+	column    string
+}
+
+type pendingLookup struct {
+	ctx       *Context
+	validator BatchValidator
+	errorPath *walk.Path
+	fieldName string
+	key       string
+}
+
+type batchGroup struct {
+	lookup  BatchLookup
+	values  map[string]struct{}
+	ordered []any
+	pending []*pendingLookup
+}
+
+type batch struct {
+	groups map[batchKey]*batchGroup
+	keys   []batchKey
+}
+
+func (b *batch) add(lookup BatchLookup, pending *pendingLookup) {
+	if b.groups == nil {
+		b.groups = map[batchKey]*batchGroup{}
+	}
+	key := batchKey{table: lookup.Table, column: lookup.Column}
+	if lookup.Scope != nil || lookup.Normalize != nil {
+		key.validator = pending.validator
+	}
+	group, ok := b.groups[key]
+	if !ok {
+		group = &batchGroup{lookup: lookup, values: map[string]struct{}{}}
+		b.groups[key] = group
+		b.keys = append(b.keys, key)
+	}
+	pending.key = lookupKey(lookup.Value, lookup.Normalize)
+	if _, ok := group.values[pending.key]; !ok {
+		group.values[pending.key] = struct{}{}
+		group.ordered = append(group.ordered, lookup.Value)
+	}
+	group.pending = append(group.pending, pending)
+}
+
+// lookupKey returns a representation of the given value used to match the values
+// returned by the database with the looked up values. Numbers are matched
+// regardless of their type. If not nil, `normalize` is applied to strings.
+func lookupKey(value any, normalize func(string) string) string {
+	var str string
+	switch val := value.(type) {
+	case string:
+		str = val
+	case []byte:
+		str = string(val)
+	default:
+		if f, isNumber, err := numberAsFloat64(value); isNumber && err == nil {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		return fmt.Sprintf("%v", value)
+	}
+	if normalize != nil {
+		return normalize(str)
+	}
+	return str
+}
+
+// findExistingValues returns the keys (see `lookupKey`) of the given values
+// that exist in the table and column of the given lookup.
+func findExistingValues(db *gorm.DB, cfg *config.Config, lookup BatchLookup, values []any) (map[string]struct{}, error) {
+	if err := checkBatchIdentifiers(lookup.Table, lookup.Column); err != nil {
+		return nil, err
+	}
+	if cfg != nil {
+		timeout := cfg.GetInt("database.defaultReadQueryTimeout")
+		if _, hasDeadline := db.Statement.Context.Deadline(); !hasDeadline && timeout > 0 {
+			timeoutCtx, cancel := context.WithTimeout(db.Statement.Context, time.Duration(timeout)*time.Millisecond)
+			defer cancel()
+			db = db.WithContext(timeoutCtx)
+		}
+	}
+
+	existing := make(map[string]struct{}, len(values))
+	for start := 0; start < len(values); start += BatchChunkSize {
+		chunk := values[start:min(start+BatchChunkSize, len(values))]
+		query := db.Table(lookup.Table).
+			Distinct(lookup.Column).
+			Where(clause.IN{Column: clause.Column{Name: lookup.Column}, Values: chunk})
+		if lookup.Scope != nil {
+			query = lookup.Scope(query)
+		}
+		results := []any{}
+		if err := query.Pluck(lookup.Column, &results).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			existing[lookupKey(r, lookup.Normalize)] = struct{}{}
+		}
+	}
+	return existing, nil
+}
+
+func (v *validator) executeBatch() {
+	for _, key := range v.batch.keys {
+		group := v.batch.groups[key]
+		existing, err := findExistingValues(v.options.DB, v.options.Config, group.lookup, group.ordered)
+		if err != nil {
+			v.errors = append(v.errors, errors.New(err))
+			continue
+		}
+		for _, p := range group.pending {
+			_, found := existing[p.key]
+			if !p.validator.ValidateLookup(p.ctx, found) {
+				v.addValidationError(p.fieldName, p.errorPath, v.newViolation(p.ctx, p.validator, v.getMessage(p.ctx, p.validator)))
+			}
+		}
+	}
+}
+
+// validateLookup executes the lookup of a single `BatchValidator` immediately.
+func validateLookup(ctx *Context, v BatchValidator) bool {
+	lookup, ok := v.Lookup(ctx)
+	if !ok {
+		// The previous validators didn't pass or the value cannot be looked up.
+		return ctx.Invalid
+	}
+	existing, err := findExistingValues(v.DB(), v.Config(), lookup, []any{lookup.Value})
+	if err != nil {
+		ctx.AddError(errors.New(err))
+		return false
+	}
+	_, found := existing[lookupKey(lookup.Value, lookup.Normalize)]
+	return v.ValidateLookup(ctx, found)
+}
+
+//------------------------------
+
+// ExistsBatchValidator validates the field under validation must exist in the given
+// table and column. See `BatchValidator` for more details.
+type ExistsBatchValidator struct {
+	BaseValidator
+
+	// Scope if not nil, applied to the query looking up the value, for example
+	// to exclude soft-deleted records. The given `*gorm.DB` already selects from
+	// `Table` and filters on `Column`.
+	Scope func(db *gorm.DB) *gorm.DB
+
+	// Normalize if not nil, applied to the strings compared when the column's collation
+	// matches values that are not strictly equal. See `BatchValidator`.
+	Normalize func(value string) string
+
+	Table  string
+	Column string
+}
+
+// Validate checks the field under validation satisfies this validator's criteria.
+// This executes a query immediately. When used in `validation.Validate()`,
+// the lookup is batched with the lookups of the other fields instead.
+func (v *ExistsBatchValidator) Validate(ctx *Context) bool {
+	return validateLookup(ctx, v)
+}
+
+// Lookup returns the database lookup of the value under validation.
+// Returns false if a previous validator didn't pass or if the value is not
+// a scalar value.
+func (v *ExistsBatchValidator) Lookup(ctx *Context) (BatchLookup, bool) {
+	lookup, ok := lookupScalar(ctx, v.Table, v.Column)
+	lookup.Scope = v.Scope
+	lookup.Normalize = v.Normalize
+	return lookup, ok
+}
+
+// ValidateLookup returns true if the value exists.
+func (v *ExistsBatchValidator) ValidateLookup(_ *Context, found bool) bool {
+	return found
+}
+
+// Name returns the string name of the validator.
+func (v *ExistsBatchValidator) Name() string { return "exists" }
+
+// ExistsBatch validates the field under validation must exist in the given table and column.
+//
+// When validating many values, for example array elements with the path `items[].product_id`,
+// this is preferable to `Exists` because all the lookups on the same table and column
+// are executed in a single query at the end of the validation.
+//
+// The table and column must be plain identifiers. The table can be prefixed with
+// a schema name: "schema.table". Otherwise, the validation fails with an error.
+//
+// The lookup can be restricted by setting the validator's `Scope`:
+//
+//	exists := v.ExistsBatch("users", "id")
+//	exists.Scope = func(db *gorm.DB) *gorm.DB {
+//		return db.Where("deleted_at IS NULL")
+//	}
+//
+// If the column uses a case-insensitive or PAD SPACE collation, set the validator's
+// `Normalize` function (for example to `NormalizeCaseInsensitive`), otherwise values
+// matched by the database but not strictly equal are considered absent.
+func ExistsBatch(table, column string) *ExistsBatchValidator {
+	return &ExistsBatchValidator{Table: table, Column: column}
+}
+
+//------------------------------
+
+// UniqueBatchValidator validates the field under validation must not already exist in the given
+// table and column. See `BatchValidator` for more details.
+type UniqueBatchValidator struct {
+	ExistsBatchValidator
+}
+
+// Validate checks the field under validation satisfies this validator's criteria.
+// This executes a query immediately. When used in `validation.Validate()`,
+// the lookup is batched with the lookups of the other fields instead.
+func (v *UniqueBatchValidator) Validate(ctx *Context) bool {
+	return validateLookup(ctx, v)
+}
+
+// ValidateLookup returns true if the value doesn't exist.
+func (v *UniqueBatchValidator) ValidateLookup(_ *Context, found bool) bool {
+	return !found
+}
+
+// Name returns the string name of the validator.
+func (v *UniqueBatchValidator) Name() string { return "unique" }
+
+// UniqueBatch validates the field under validation must not already exist in the given table and column.
+//
+// When validating many values, for example array elements with the path `items[].email`,
+// this is preferable to `Unique` because all the lookups on the same table and column
+// are executed in a single query at the end of the validation.
+//
+// Like `ExistsBatch`, the lookup can be restricted by setting the validator's `Scope`
+// and the compared values can be normalized by setting its `Normalize` function.
+func UniqueBatch(table, column string) *UniqueBatchValidator {
+	return &UniqueBatchValidator{ExistsBatchValidator: ExistsBatchValidator{Table: table, Column: column}}
+}
+
+func lookupScalar(ctx *Context, table, column string) (BatchLookup, bool) {
+	if ctx.Invalid {
+		return BatchLookup{}, false
+	}
+	switch GetFieldType(ctx.Value) {
+	case FieldTypeArray, FieldTypeObject, FieldTypeFile, FieldTypeUnsupported:
+		return BatchLookup{}, false
+	}
+	return BatchLookup{Table: table, Column: column, Value: ctx.Value}, true
+}
@@ -0,0 +1,305 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"goyave.dev/goyave/v5/lang"
+)
+
+func countQueries(t *testing.T, db *gorm.DB) *int {
+	count := 0
+	err := db.Callback().Query().After("gorm:query").Register("test:count", func(_ *gorm.DB) {
+		count++
+	})
+	require.NoError(t, err)
+	return &count
+}
+
+func TestBatchValidators(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		v := ExistsBatch("models", "id")
+		assert.NotNil(t, v)
+		assert.Equal(t, "exists", v.Name())
+		assert.False(t, v.IsType())
+		assert.False(t, v.IsTypeDependent())
+		assert.Empty(t, v.MessagePlaceholders(&Context{}))
+		assert.Equal(t, "models", v.Table)
+		assert.Equal(t, "id", v.Column)
+
+		v2 := UniqueBatch("models", "name")
+		assert.NotNil(t, v2)
+		assert.Equal(t, "unique", v2.Name())
+		assert.False(t, v2.IsType())
+		assert.False(t, v2.IsTypeDependent())
+		assert.Empty(t, v2.MessagePlaceholders(&Context{}))
+		assert.Equal(t, "models", v2.Table)
+		assert.Equal(t, "name", v2.Column)
+	})
+
+	t.Run("Lookup", func(t *testing.T) {
+		v := ExistsBatch("models", "id")
+		lookup, ok := v.Lookup(&Context{Value: 1})
+		assert.True(t, ok)
+		assert.Equal(t, BatchLookup{Table: "models", Column: "id", Value: 1}, lookup)
+
+		_, ok = v.Lookup(&Context{Value: 1, Invalid: true})
+		assert.False(t, ok)
+		_, ok = v.Lookup(&Context{Value: []int{1}})
+		assert.False(t, ok)
+		_, ok = v.Lookup(&Context{Value: map[string]any{}})
+		assert.False(t, ok)
+
+		assert.True(t, v.ValidateLookup(&Context{}, true))
+		assert.False(t, v.ValidateLookup(&Context{}, false))
+		v2 := UniqueBatch("models", "id")
+		assert.False(t, v2.ValidateLookup(&Context{}, true))
+		assert.True(t, v2.ValidateLookup(&Context{}, false))
+	})
+
+	t.Run("Validate_single", func(t *testing.T) {
+		opts := prepareUniqueTest(t)
+		require.NoError(t, opts.DB.Create([]uniqueTestModel{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}).Error)
+
+		cases := []struct {
+			validator      Validator
+			value          any
+			desc           string
+			expectedErrors []string
+			invalid        bool
+			want           bool
+		}{
+			{desc: "exists_ok", validator: ExistsBatch("models", "id"), value: 1.0, want: true},
+			{desc: "exists_nok", validator: ExistsBatch("models", "id"), value: 3, want: false},
+			{desc: "exists_array", validator: ExistsBatch("models", "id"), value: []int{1}, want: false},
+			{desc: "exists_invalid", validator: ExistsBatch("models", "id"), value: 3, invalid: true, want: true},
+			{desc: "unique_ok", validator: UniqueBatch("models", "name"), value: "c", want: true},
+			{desc: "unique_nok", validator: UniqueBatch("models", "name"), value: "a", want: false},
+			{desc: "error", validator: UniqueBatch("models", "not_a_column"), value: "a", want: false, expectedErrors: []string{"no such column: not_a_column"}},
+		}
+
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				c.validator.init(opts)
+				ctx := &Context{Value: c.value, Invalid: c.invalid}
+				assert.Equal(t, c.want, c.validator.Validate(ctx))
+				if c.expectedErrors == nil {
+					c.expectedErrors = []string{}
+				}
+				assert.Equal(t, c.expectedErrors, lo.Map(ctx.errors, func(e error, _ int) string { return e.Error() }))
+			})
+		}
+	})
+
+	t.Run("Validate_batched", func(t *testing.T) {
+		opts := prepareUniqueTest(t)
+		records := make([]uniqueTestModel, 0, 150)
+		for i := 1; i <= 150; i++ {
+			records = append(records, uniqueTestModel{ID: int64(i), Name: fmt.Sprintf("name%d", i)})
+		}
+		require.NoError(t, opts.DB.Create(records).Error)
+		queries := countQueries(t, opts.DB)
+
+		items := make([]any, 0, 200)
+		for i := 1; i <= 200; i++ {
+			items = append(items, map[string]any{"id": float64(i)})
+		}
+		data := map[string]any{
+			"items":  items,
+			"owner":  3.0,
+			"emails": []any{"name1", "new", "name2"},
+		}
+
+		opts.Data = data
+		opts.Language = lang.New().GetDefault()
+		opts.Rules = RuleSet{
+			{Path: "items", Rules: List{Array()}},
+			{Path: "items[]", Rules: List{Object()}},
+			{Path: "items[].id", Rules: List{Int(), ExistsBatch("models", "id"), Min(2)}},
+			{Path: "owner", Rules: List{Int(), ExistsBatch("models", "id")}},
+			{Path: "emails", Rules: List{Array()}},
+			{Path: "emails[]", Rules: List{String(), UniqueBatch("models", "name")}},
+		}
+		errs, errsBag := Validate(opts)
+		assert.Nil(t, errsBag)
+		assert.Equal(t, 2, *queries) // One per table and column
+
+		require.NotNil(t, errs)
+		itemErrors := errs.Fields["items"].Elements
+		assert.Len(t, itemErrors, 51)
+		assert.Equal(t, []string{"The id must be at least 2."}, itemErrors[0].Fields["id"].Errors)
+		assert.Equal(t, []string{"The id does not exist."}, itemErrors[150].Fields["id"].Errors)
+		assert.Equal(t, []string{"The id does not exist."}, itemErrors[199].Fields["id"].Errors)
+		assert.NotContains(t, errs.Fields, "owner")
+		assert.Equal(t, &Errors{
+			Elements: ArrayErrors{
+				0: &Errors{Errors: []string{"The emails element value has already been taken."}},
+				2: &Errors{Errors: []string{"The emails element value has already been taken."}},
+			},
+		}, errs.Fields["emails"])
+	})
+
+	t.Run("Validate_batched_chunks", func(t *testing.T) {
+		opts := prepareUniqueTest(t)
+		queries := countQueries(t, opts.DB)
+
+		items := make([]any, 0, BatchChunkSize+1)
+		for i := 0; i <= BatchChunkSize; i++ {
+			items = append(items, float64(i))
+		}
+		opts.Data = map[string]any{"items": items}
+		opts.Language = lang.New().GetDefault()
+		opts.Rules = RuleSet{
+			{Path: "items[]", Rules: List{Int(), UniqueBatch("models", "id")}},
+		}
+		errs, errsBag := Validate(opts)
+		assert.Nil(t, errsBag)
+		assert.Nil(t, errs)
+		assert.Equal(t, 2, *queries)
+	})
+
+	t.Run("Validate_batched_collation", func(t *testing.T) {
+		opts := prepareUniqueTest(t)
+		require.NoError(t, opts.DB.Exec("CREATE TABLE nocase_models (name TEXT COLLATE NOCASE)").Error)
+		require.NoError(t, opts.DB.Exec("INSERT INTO nocase_models (name) VALUES ('John'), ('jane')").Error)
+		queries := countQueries(t, opts.DB)
+
+		exists := ExistsBatch("nocase_models", "name")
+		exists.Normalize = NormalizeCaseInsensitive
+		unique := UniqueBatch("nocase_models", "name")
+		unique.Normalize = NormalizeCaseInsensitive
+		opts.Data = map[string]any{
+			"names":     []any{"JOHN", "Jane", "john", "other"},
+			"new_names": []any{"JANE", "new"},
+		}
+		opts.Language = lang.New().GetDefault()
+		opts.Rules = RuleSet{
+			{Path: "names[]", Rules: List{String(), exists}},
+			{Path: "new_names[]", Rules: List{String(), unique}},
+		}
+		errs, errsBag := Validate(opts)
+		assert.Nil(t, errsBag)
+		assert.Equal(t, 2, *queries) // One per validator with a normalization function
+		assert.Equal(t, &Errors{
+			Elements: ArrayErrors{
+				3: &Errors{Errors: []string{"The names element value does not exist."}},
+			},
+		}, errs.Fields["names"])
+		assert.Equal(t, &Errors{
+			Elements: ArrayErrors{
+				0: &Errors{Errors: []string{"The new_names element value has already been taken."}},
+			},
+		}, errs.Fields["new_names"])
+
+		exists.init(opts)
+		assert.True(t, exists.Validate(&Context{Value: "JOHN"}))
+		assert.False(t, exists.Validate(&Context{Value: "other"}))
+	})
+
+	t.Run("Validate_batched_scope", func(t *testing.T) {
+		opts := prepareUniqueTest(t)
+		require.NoError(t, opts.DB.Create([]uniqueTestModel{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}}).Error)
+		queries := countQueries(t, opts.DB)
+
+		existsA := ExistsBatch("models", "id")
+		existsA.Scope = func(db *gorm.DB) *gorm.DB {
+			return db.Where("name != ?", "a")
+		}
+		existsB := ExistsBatch("models", "id")
+		existsB.Scope = func(db *gorm.DB) *gorm.DB {
+			return db.Where("name != ?", "b")
+		}
+		opts.Data = map[string]any{
+			"a": []any{1.0, 2.0, 3.0},
+			"b": []any{1.0, 2.0, 3.0},
+		}
+		opts.Language = lang.New().GetDefault()
+		opts.Rules = RuleSet{
+			{Path: "a[]", Rules: List{Int(), existsA}},
+			{Path: "b[]", Rules: List{Int(), existsB}},
+		}
+		errs, errsBag := Validate(opts)
+		assert.Nil(t, errsBag)
+		assert.Equal(t, 2, *queries) // One per validator with a scope
+		require.NotNil(t, errs)
+		assert.Equal(t, &Errors{
+			Elements: ArrayErrors{
+				0: &Errors{Errors: []string{"The a element value does not exist."}},
+			},
+		}, errs.Fields["a"])
+		assert.Equal(t, &Errors{
+			Elements: ArrayErrors{
+				1: &Errors{Errors: []string{"The b element value does not exist."}},
+			},
+		}, errs.Fields["b"])
+
+		existsA.init(opts)
+		assert.False(t, existsA.Validate(&Context{Value: 1}))
+		assert.True(t, existsA.Validate(&Context{Value: 2}))
+	})
+
+	t.Run("Validate_invalid_identifiers", func(t *testing.T) {
+		opts := prepareUniqueTest(t)
+		v := ExistsBatch("models WHERE 1=1; --", "id")
+		v.init(opts)
+		ctx := &Context{Value: 1}
+		assert.False(t, v.Validate(ctx))
+		assert.Equal(t, []string{`invalid table name "models WHERE 1=1; --"`}, lo.Map(ctx.errors, func(e error, _ int) string { return e.Error() }))
+	})
+
+	t.Run("Validate_batched_error", func(t *testing.T) {
+		opts := prepareUniqueTest(t)
+		opts.Data = map[string]any{"items": []any{1.0, 2.0}}
+		opts.Language = lang.New().GetDefault()
+		opts.Rules = RuleSet{
+			{Path: "items[]", Rules: List{Int(), UniqueBatch("models", "not_a_column")}},
+		}
+		errs, errsBag := Validate(opts)
+		assert.Nil(t, errs)
+		require.Len(t, errsBag, 1)
+		assert.Equal(t, "no such column: not_a_column", errsBag[0].Error())
+	})
+}
+
+func TestCheckBatchIdentifiers(t *testing.T) {
+	require.NoError(t, checkBatchIdentifiers("models", "id"))
+	require.NoError(t, checkBatchIdentifiers("public.models", "_name2"))
+
+	cases := []struct {
+		table  string
+		column string
+		want   string
+	}{
+		{table: "models m", column: "id", want: `invalid table name "models m"`},
+		{table: "public.", column: "id", want: `invalid table name "public."`},
+		{table: ".models", column: "id", want: `invalid table name ".models"`},
+		{table: "a.b.c", column: "id", want: `invalid table name "a.b.c"`},
+		{table: "`models`", column: "id", want: "invalid table name \"`models`\""},
+		{table: "1models", column: "id", want: `invalid table name "1models"`},
+		{table: "models", column: "id; DROP TABLE models", want: `invalid column name "id; DROP TABLE models"`},
+		{table: "models", column: "models.id", want: `invalid column name "models.id"`},
+		{table: "models", column: "", want: `invalid column name ""`},
+	}
+	for _, c := range cases {
+		err := checkBatchIdentifiers(c.table, c.column)
+		require.Error(t, err)
+		assert.Equal(t, c.want, err.Error())
+	}
+}
+
+func TestLookupKey(t *testing.T) {
+	assert.Equal(t, "abc", lookupKey("abc", nil))
+	assert.Equal(t, "abc", lookupKey([]byte("abc"), nil))
+	assert.Equal(t, "1", lookupKey(1, nil))
+	assert.Equal(t, "1", lookupKey(int64(1), nil))
+	assert.Equal(t, "1", lookupKey(1.0, nil))
+	assert.Equal(t, "1.5", lookupKey(float32(1.5), nil))
+	assert.Equal(t, "true", lookupKey(true, nil))
+	assert.Equal(t, "abc", lookupKey("ABC  ", NormalizeCaseInsensitive))
+	assert.Equal(t, "abc", lookupKey([]byte("AbC "), NormalizeCaseInsensitive))
+	assert.Equal(t, "1", lookupKey(1, NormalizeCaseInsensitive))
+}
@@ -0,0 +1,172 @@
+package validation
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// ErrorFormat the format in which validation errors are written in responses.
+type ErrorFormat int
+
+const (
+	// ErrorFormatNested the default format: the nested `fields` / `elements` / `errors`
+	// tree represented by `Errors`.
+	ErrorFormatNested ErrorFormat = iota
+
+	// ErrorFormatPath a flat map associating the path of each invalid element
+	// with its error messages. See `Errors.PathMap()`.
+	ErrorFormatPath
+
+	// ErrorFormatJSONPointer a flat map associating the JSON pointer of each invalid
+	// element with its error messages. See `Errors.JSONPointerMap()`.
+	ErrorFormatJSONPointer
+
+	// ErrorFormatViolations a list of violations including the name of the rule and
+	// the message placeholders. See `Errors.ViolationList()`.
+	ErrorFormatViolations
+)
+
+// errorStep a single step in the path to an invalid element: either a field name
+// or an array index.
+type errorStep struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// walk calls the given function for each element having errors, depth-first.
+// Fields are visited in alphabetical order and array elements in ascending order.
+func (e *Errors) walk(steps []errorStep, f func(steps []errorStep, errs *Errors)) {
+	if e == nil {
+		return
+	}
+	if len(e.Errors) > 0 {
+		f(steps, e)
+	}
+	names := lo.Keys(e.Fields)
+	slices.Sort(names)
+	for _, name := range names {
+		e.Fields[name].walk(append(slices.Clip(steps), errorStep{name: name}), f)
+	}
+	indexes := lo.Keys(e.Elements)
+	slices.Sort(indexes)
+	for _, index := range indexes {
+		e.Elements[index].walk(append(slices.Clip(steps), errorStep{index: index, isIndex: true}), f)
+	}
+}
+
+func errorPath(steps []errorStep) string {
+	var b strings.Builder
+	for i, step := range steps {
+		if step.isIndex {
+			b.WriteString("[" + strconv.Itoa(step.index) + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(step.name)
+	}
+	return b.String()
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func errorJSONPointer(steps []errorStep) string {
+	var b strings.Builder
+	for _, step := range steps {
+		b.WriteByte('/')
+		switch {
+		case !step.isIndex:
+			b.WriteString(jsonPointerEscaper.Replace(step.name))
+		case step.index < 0:
+			// The element doesn't exist
+			b.WriteByte('-')
+		default:
+			b.WriteString(strconv.Itoa(step.index))
+		}
+	}
+	return b.String()
+}
+
+// PathMap returns a flat map associating the path of each invalid element with its
+// error messages. The keys use the same syntax as the `RuleSet` paths, with array indexes,
+// for example `user.addresses[0].city`. Errors on the root element use an empty key.
+// The index `-1` identifies array elements that don't exist.
+func (e *Errors) PathMap() map[string][]string {
+	result := map[string][]string{}
+	e.walk(nil, func(steps []errorStep, errs *Errors) {
+		result[errorPath(steps)] = errs.Errors
+	})
+	return result
+}
+
+// JSONPointerMap returns a flat map associating the JSON pointer (RFC 6901) of each
+// invalid element with its error messages, for example `/user/addresses/0/city`.
+// Errors on the root element use an empty key. The `-` token identifies array
+// elements that don't exist.
+func (e *Errors) JSONPointerMap() map[string][]string {
+	result := map[string][]string{}
+	e.walk(nil, func(steps []errorStep, errs *Errors) {
+		result[errorJSONPointer(steps)] = errs.Errors
+	})
+	return result
+}
+
+// ViolationList returns the list of all the errors, including the name of the rule
+// and the message placeholders if the errors were recorded with `Options.RecordViolations`.
+// Otherwise, only the path and the message of the violations are set.
+func (e *Errors) ViolationList() []*Violation {
+	result := []*Violation{}
+	e.walk(nil, func(steps []errorStep, errs *Errors) {
+		path := errorPath(steps)
+		used := make([]bool, len(errs.Violations))
+		for _, message := range errs.Errors {
+			violation := &Violation{Path: path, Message: message}
+			for i, v := range errs.Violations {
+				if !used[i] && v.Message == message {
+					used[i] = true
+					violation.Rule = v.Rule
+					violation.Params = v.Params
+					break
+				}
+			}
+			result = append(result, violation)
+		}
+	})
+	return result
+}
+
+// Format returns the errors in the given format. Returns the `Errors` itself for
+// `ErrorFormatNested`.
+func (e *Errors) Format(format ErrorFormat) any {
+	switch format {
+	case ErrorFormatPath:
+		return e.PathMap()
+	case ErrorFormatJSONPointer:
+		return e.JSONPointerMap()
+	case ErrorFormatViolations:
+		return e.ViolationList()
+	default:
+		return e
+	}
+}
+
+// Format returns the body and query errors in the given format.
+// Returns the `ErrorResponse` itself for `ErrorFormatNested`.
+func (r *ErrorResponse) Format(format ErrorFormat) any {
+	if format == ErrorFormatNested {
+		return r
+	}
+	result := map[string]any{}
+	if r.Body != nil {
+		result["body"] = r.Body.Format(format)
+	}
+	if r.Query != nil {
+		result["query"] = r.Query.Format(format)
+	}
+	return result
+}
@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/lang"
+	"goyave.dev/goyave/v5/util/walk"
+)
+
+func TestErrorFormat(t *testing.T) {
+	validate := func(recordViolations bool) *Errors {
+		errs, errsBag := Validate(&Options{
+			Data: map[string]any{
+				"user": map[string]any{
+					"name":      "abcdef",
+					"addresses": []any{map[string]any{"city": "Paris"}, map[string]any{"city": 1}},
+				},
+				"a/b~c": "x",
+			},
+			Language:         lang.New().GetDefault(),
+			RecordViolations: recordViolations,
+			Rules: RuleSet{
+				{Path: "user", Rules: List{Required(), Object()}},
+				{Path: "user.name", Rules: List{Required(), String(), Max(5)}},
+				{Path: "user.addresses", Rules: List{Required(), Array()}},
+				{Path: "user.addresses[]", Rules: List{Object()}},
+				{Path: "user.addresses[].city", Rules: List{Required(), String()}},
+				{Path: "user.email", Rules: List{Required(), Email()}},
+				{Path: "a/b~c", Rules: List{Int()}},
+			},
+		})
+		require.Nil(t, errsBag)
+		require.NotNil(t, errs)
+		return errs
+	}
+
+	t.Run("PathMap", func(t *testing.T) {
+		errs := validate(false)
+		errs.Add(&walk.Path{Type: walk.PathTypeElement}, "root error")
+		errs.Add(&walk.Path{Type: walk.PathTypeObject, Next: walk.MustParse("items[]")}, "missing element")
+		assert.Equal(t, map[string][]string{
+			"":                       {"root error"},
+			"a/b~c":                  {"The a/b~c must be an integer."},
+			"items[-1]":              {"missing element"},
+			"user.addresses[1].city": {"The city must be a string."},
+			"user.email":             {"The email address is required.", "The email address must be a valid email address."},
+			"user.name":              {"The name may not have more than 5 characters."},
+		}, errs.PathMap())
+		assert.Equal(t, errs.PathMap(), errs.Format(ErrorFormatPath))
+	})
+
+	t.Run("JSONPointerMap", func(t *testing.T) {
+		errs := validate(false)
+		errs.Add(&walk.Path{Type: walk.PathTypeObject, Next: walk.MustParse("items[]")}, "missing element")
+		assert.Equal(t, map[string][]string{
+			"/a~1b~0c":               {"The a/b~c must be an integer."},
+			"/items/-":               {"missing element"},
+			"/user/addresses/1/city": {"The city must be a string."},
+			"/user/email":            {"The email address is required.", "The email address must be a valid email address."},
+			"/user/name":             {"The name may not have more than 5 characters."},
+		}, errs.JSONPointerMap())
+		assert.Equal(t, errs.JSONPointerMap(), errs.Format(ErrorFormatJSONPointer))
+	})
+
+	t.Run("ViolationList", func(t *testing.T) {
+		errs := validate(true)
+		errs.Add(&walk.Path{Type: walk.PathTypeObject, Next: walk.MustParse("user.name")}, "added message")
+		expected := []*Violation{
+			{Path: "a/b~c", Rule: "int", Message: "The a/b~c must be an integer."},
+			{Path: "user.addresses[1].city", Rule: "string", Message: "The city must be a string."},
+			{Path: "user.email", Rule: "required", Message: "The email address is required."},
+			{Path: "user.email", Rule: "email", Message: "The email address must be a valid email address."},
+			{Path: "user.name", Rule: "max", Message: "The name may not have more than 5 characters.", Params: map[string]string{"max": "5"}},
+			{Path: "user.name", Message: "added message"},
+		}
+		assert.Equal(t, expected, errs.ViolationList())
+		assert.Equal(t, expected, errs.Format(ErrorFormatViolations))
+
+		raw, err := json.Marshal(errs.ViolationList()[4])
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"path":"user.name","rule":"max","message":"The name may not have more than 5 characters.","params":{"max":"5"}}`, string(raw))
+
+		// Violations are not part of the nested format
+		raw, err = json.Marshal(errs.Fields["a/b~c"])
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"errors":["The a/b~c must be an integer."]}`, string(raw))
+	})
+
+	t.Run("ViolationList_not_recorded", func(t *testing.T) {
+		errs := validate(false)
+		assert.Nil(t, errs.Fields["user"].Fields["name"].Violations)
+		assert.Equal(t, &Violation{Path: "user.name", Message: "The name may not have more than 5 characters."}, errs.ViolationList()[4])
+	})
+
+	t.Run("array_elements", func(t *testing.T) {
+		errs, _ := Validate(&Options{
+			Data:             map[string]any{"a": []any{"x", "y"}},
+			Language:         lang.New().GetDefault(),
+			RecordViolations: true,
+			Rules: RuleSet{
+				{Path: "a", Rules: List{Array(), &testValidator{
+					validateFunc: func(_ component, ctx *Context) bool {
+						ctx.AddArrayElementValidationErrors(1)
+						return true
+					},
+				}}},
+			},
+		})
+		assert.Equal(t, []*Violation{
+			{Path: "a[1]", Rule: "test_validator", Message: "validation.rules.test_validator.element"},
+		}, errs.ViolationList())
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		errs := &Errors{}
+		errs.Merge(&walk.Path{Type: walk.PathTypeObject, Next: walk.MustParse("a")}, &Errors{
+			Errors:     []string{"message"},
+			Violations: []*Violation{{Rule: "rule", Message: "message"}},
+		})
+		assert.Equal(t, []*Violation{{Path: "a", Rule: "rule", Message: "message"}}, errs.ViolationList())
+	})
+
+	t.Run("ErrorResponse", func(t *testing.T) {
+		response := &ErrorResponse{
+			Body: &Errors{Fields: FieldsErrors{"a": &Errors{Errors: []string{"message"}}}},
+		}
+		assert.Same(t, response, response.Format(ErrorFormatNested))
+		assert.Equal(t, map[string]any{"body": map[string][]string{"a": {"message"}}}, response.Format(ErrorFormatPath))
+	})
+}
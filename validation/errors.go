@@ -13,6 +13,27 @@ type Errors struct {
 	Fields   FieldsErrors `json:"fields,omitempty"`
 	Elements ArrayErrors  `json:"elements,omitempty"`
 	Errors   []string     `json:"errors,omitempty"`
+
+	// Violations the details of the errors of this element (rule name and placeholders).
+	// Only filled if `Options.RecordViolations` is `true`. Violations are matched
+	// with the messages in `Errors` using their message.
+	Violations []*Violation `json:"-"`
+}
+
+// Violation the details of a single validation error.
+type Violation struct {
+	// Params the placeholders of the message (see `Validator.MessagePlaceholders()`),
+	// without the leading colon. The ":field" placeholder is not included.
+	Params map[string]string `json:"params,omitempty"`
+
+	// Path the path to the invalid element (e.g. "user.addresses[0].city").
+	// Only set in the violations returned by `Errors.ViolationList()`.
+	Path string `json:"path"`
+
+	// Rule the name of the validator (see `Validator.Name()`). Empty if the error
+	// was added by a validator using `Context.AddValidationError()`.
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
 }
 
 // FieldsErrors representing the errors associated with the fields of an object,
@@ -34,9 +55,22 @@ type ArrayErrors map[int]*Errors
 // considered as the root element and skipped. This allows this implementation
 // to know the root element is an object and create the `FieldsErrors` accordingly.
 func (e *Errors) Add(path *walk.Path, message string) {
+	e.add(path, message, nil)
+}
+
+// AddViolation adds the given violation and its message to the element identified
+// by the given path. See `Errors.Add()` for more details.
+func (e *Errors) AddViolation(path *walk.Path, violation *Violation) {
+	e.add(path, violation.Message, violation)
+}
+
+func (e *Errors) add(path *walk.Path, message string, violation *Violation) {
 	switch path.Type {
 	case walk.PathTypeElement:
 		e.Errors = append(e.Errors, message)
+		if violation != nil {
+			e.Violations = append(e.Violations, violation)
+		}
 	case walk.PathTypeArray:
 		if e.Elements == nil {
 			e.Elements = make(map[int]*Errors)
@@ -46,12 +80,12 @@ func (e *Errors) Add(path *walk.Path, message string) {
 		if path.Index != nil {
 			index = *path.Index
 		}
-		e.Elements.Add(path.Next, index, message)
+		e.Elements.add(path.Next, index, message, violation)
 	case walk.PathTypeObject:
 		if e.Fields == nil {
 			e.Fields = make(FieldsErrors)
 		}
-		e.Fields.Add(path.Next, message)
+		e.Fields.add(path.Next, message, violation)
 	}
 }
 
@@ -86,6 +120,7 @@ func (e *Errors) Merge(path *walk.Path, errors *Errors) {
 			}
 		}
 		e.Errors = append(e.Errors, errors.Errors...)
+		e.Violations = append(e.Violations, errors.Violations...)
 	case walk.PathTypeArray:
 		if e.Elements == nil {
 			e.Elements = make(ArrayErrors)
@@ -107,12 +142,16 @@ func (e *Errors) Merge(path *walk.Path, errors *Errors) {
 // Add an error message to the element identified by the given path.
 // Creates all missing elements in the path.
 func (e FieldsErrors) Add(path *walk.Path, message string) {
+	e.add(path, message, nil)
+}
+
+func (e FieldsErrors) add(path *walk.Path, message string, violation *Violation) {
 	errs, ok := e[*path.Name]
 	if !ok {
 		errs = &Errors{}
 		e[*path.Name] = errs
 	}
-	errs.Add(path, message)
+	errs.add(path, message, violation)
 }
 
 // Merge the given errors into this bag of errors at the given path.
@@ -129,12 +168,16 @@ func (e FieldsErrors) Merge(path *walk.Path, errors *Errors) {
 // at the given index. "-1" index is accepted to identify non-existing elements.
 // Creates all missing elements in the path.
 func (e ArrayErrors) Add(path *walk.Path, index int, message string) {
+	e.add(path, index, message, nil)
+}
+
+func (e ArrayErrors) add(path *walk.Path, index int, message string, violation *Violation) {
 	errs, ok := e[index]
 	if !ok {
 		errs = &Errors{}
 		e[index] = errs
 	}
-	errs.Add(path, message)
+	errs.add(path, message, violation)
 }
 
 // Merge the given errors into this bag of errors at the given path.
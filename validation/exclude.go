@@ -0,0 +1,59 @@
+package validation
+
+// conditionalExcludeValidator is implemented by validators removing the field
+// under validation from the input data if a condition is met.
+type conditionalExcludeValidator interface {
+	Validator
+	isExcluded(ctx *Context) bool
+}
+
+// ExcludeValidator the field under validation is removed from the input data.
+type ExcludeValidator struct{ BaseValidator }
+
+// Validate returns true. This validator is never executed because excluded fields
+// are not validated.
+func (v *ExcludeValidator) Validate(_ *Context) bool {
+	return true
+}
+
+func (v *ExcludeValidator) isExcluded(_ *Context) bool {
+	return true
+}
+
+// Name returns the string name of the validator.
+func (v *ExcludeValidator) Name() string { return "exclude" }
+
+// Exclude the field under validation is removed from the input data and the other
+// validators are not executed. Only fields inside objects can be removed: excluded
+// array elements are left untouched but are not validated.
+func Exclude() *ExcludeValidator {
+	return &ExcludeValidator{}
+}
+
+//------------------------------
+
+// ExcludeIfValidator is the same as `ExcludeValidator` but only applies the behavior
+// described if the specified `Condition` function returns true.
+type ExcludeIfValidator struct {
+	BaseValidator
+	Condition func(*Context) bool
+}
+
+// Validate returns true. If the condition is met, this validator is never executed
+// because excluded fields are not validated.
+func (v *ExcludeIfValidator) Validate(_ *Context) bool {
+	return true
+}
+
+func (v *ExcludeIfValidator) isExcluded(ctx *Context) bool {
+	return v.Condition(ctx)
+}
+
+// Name returns the string name of the validator.
+func (v *ExcludeIfValidator) Name() string { return "exclude_if" }
+
+// ExcludeIf is the same as `Exclude` but only applies the behavior
+// described if the specified condition function returns true.
+func ExcludeIf(condition func(*Context) bool) *ExcludeIfValidator {
+	return &ExcludeIfValidator{Condition: condition}
+}
@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v5/lang"
+	"goyave.dev/goyave/v5/util/walk"
+)
+
+func TestExcludeValidator(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		v := Exclude()
+		assert.NotNil(t, v)
+		assert.Equal(t, "exclude", v.Name())
+		assert.False(t, v.IsType())
+		assert.False(t, v.IsTypeDependent())
+		assert.Empty(t, v.MessagePlaceholders(&Context{}))
+		assert.True(t, v.Validate(&Context{}))
+
+		v2 := ExcludeIf(func(_ *Context) bool { return true })
+		assert.NotNil(t, v2)
+		assert.Equal(t, "exclude_if", v2.Name())
+		assert.False(t, v2.IsType())
+		assert.False(t, v2.IsTypeDependent())
+		assert.Empty(t, v2.MessagePlaceholders(&Context{}))
+		assert.True(t, v2.Validate(&Context{}))
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		data := map[string]any{
+			"excluded":    "value",
+			"object":      map[string]any{"a": "b"},
+			"conditional": "value",
+			"kept":        "value",
+			"items":       []any{map[string]any{"type": "a", "value": 1}, map[string]any{"type": "b", "value": 2}},
+		}
+		errs, errsBag := Validate(&Options{
+			Data:     data,
+			Language: lang.New().GetDefault(),
+			Rules: RuleSet{
+				{Path: "excluded", Rules: List{Exclude(), Int()}},
+				{Path: "object", Rules: List{Exclude(), Object()}},
+				{Path: "object.a", Rules: List{Int()}},
+				{Path: "absent", Rules: List{Required(), Exclude()}},
+				{Path: "conditional", Rules: List{ExcludeIf(func(ctx *Context) bool {
+					return ctx.Data.(map[string]any)["kept"] == "value"
+				}), Int()}},
+				{Path: "kept", Rules: List{ExcludeIf(func(_ *Context) bool { return false }), String()}},
+				{Path: "items", Rules: List{Array()}},
+				{Path: "items[]", Rules: List{Object()}},
+				{Path: "items[].value", Rules: List{ExcludeIf(func(ctx *Context) bool {
+					return hasValue(ctx, walk.MustParse("items[].type"), []any{"b"})
+				}), Int()}},
+			},
+		})
+		assert.Nil(t, errsBag)
+		assert.Nil(t, errs)
+		assert.Equal(t, map[string]any{
+			"kept":  "value",
+			"items": []map[string]any{{"type": "a", "value": 1}, {"type": "b"}},
+		}, data)
+	})
+}
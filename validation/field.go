@@ -8,11 +8,15 @@ import (
 // Provides useful information based on its validators (if required, nullable, etc).
 type Field struct {
 	isRequired func(*Context) bool
+	isExcluded func(*Context) bool
 
 	Path       *walk.Path
 	Elements   *Field
 	Validators []Validator
 
+	messages     Messages
+	defaultValue *DefaultValidator
+
 	// prefixDepth When using composition, `prefixDepth` allows to truncate the path to the
 	// validated element in order to retrieve the root object or array relative to
 	// the composed RuleSet.
@@ -21,6 +25,12 @@ type Field struct {
 	isArray    bool
 	isObject   bool
 	isNullable bool
+	isMissing  bool
+
+	// requiredIf the field is made conditionally required by a `RequiredIfValidator`.
+	// Its condition receives the whole input data when checking if the field
+	// is absent, even in a composed rule set.
+	requiredIf bool
 }
 
 func alwaysRequired(_ *Context) bool { return true }
@@ -32,23 +42,37 @@ func newField(path string, validators []Validator, prefixDepth uint) *Field {
 		Validators:  validators,
 		prefixDepth: prefixDepth,
 	}
+	f.bindValidators()
+	return f
+}
 
-	for _, v := range validators {
+// bindValidators sets the field's flags and presence functions from its validators.
+func (f *Field) bindValidators() {
+	for _, v := range f.Validators {
 		switch v := v.(type) {
 		case *RequiredValidator:
 			f.isRequired = alwaysRequired
+			f.requiredIf = false
 		case *RequiredIfValidator:
-			f.isRequired = v.Condition
+			f.isRequired = v.isRequired
+			f.requiredIf = true
+		case conditionalRequiredValidator:
+			f.isRequired = v.isRequired
+			f.requiredIf = false
+		case conditionalExcludeValidator:
+			f.isExcluded = v.isExcluded
+		case *MissingValidator:
+			f.isMissing = true
 		case *NullableValidator:
 			f.isNullable = true
+		case *DefaultValidator:
+			f.defaultValue = v
 		case *ArrayValidator:
 			f.isArray = true
 		case *ObjectValidator:
 			f.isObject = true
 		}
 	}
-
-	return f
 }
 
 // getErrorPath returns the path to use when appending the error message to the
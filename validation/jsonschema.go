@@ -0,0 +1,804 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"goyave.dev/goyave/v5/util/walk"
+)
+
+const (
+	// JSONSchemaDialect the URI of the JSON Schema dialect generated by `ToJSONSchema()`.
+	JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+	// JSONSchemaExtensionPrefix prefix of the JSON Schema keywords representing
+	// validators that cannot be expressed with standard JSON Schema keywords.
+	// The prefix is followed by the name of the rule as written in validation tags
+	// (see `FromStruct()`) and the value is either `true` or the list of parameters of the rule.
+	//
+	//	"x-goyave-starts_with": ["foo", "bar"]
+	JSONSchemaExtensionPrefix = "x-goyave-"
+)
+
+var jsonSchemaAnnotations = []string{
+	"$schema", "$id", "$comment", "$anchor", "title", "description",
+	"examples", "deprecated", "readOnly", "writeOnly",
+}
+
+// ToJSONSchema generates a JSON Schema (Draft 2020-12) document representing the given `RuleSet`.
+// The returned map can be directly marshaled to JSON.
+//
+// Types, required fields, sizes, enumerations (`In`, `NotIn`), patterns (`Regex`), formats
+// (`Email`, `URL`, `UUID`, `Date`, `IPv4`, `IPv6`) as well as nested objects and arrays are
+// mapped to their JSON Schema equivalent. The validators that cannot be expressed are
+// represented with `x-goyave-*` extension keywords (see `JSONSchemaExtensionPrefix`).
+// Validators using functions, such as `RequiredIf`, cannot be serialized and are
+// represented with an extension keyword set to `true` that cannot be imported with `FromJSONSchema()`.
+func ToJSONSchema(rules RuleSet) map[string]any {
+	root := map[string]any{"$schema": JSONSchemaDialect}
+	for _, field := range rules.AsRules() {
+		node, parent, name := jsonSchemaNode(root, field.Path)
+		exportJSONSchemaField(node, field)
+		if parent != nil && lo.ContainsBy(field.Validators, func(v Validator) bool {
+			_, ok := v.(*RequiredValidator)
+			return ok
+		}) {
+			required, _ := parent["required"].([]string)
+			if !lo.Contains(required, name) {
+				parent["required"] = append(required, name)
+			}
+		}
+	}
+	if _, ok := root["type"]; !ok && root["properties"] != nil {
+		root["type"] = "object"
+	}
+	return root
+}
+
+// jsonSchemaNode returns the sub-schema identified by the given path, creating it
+// if necessary. If the path identifies an object property, the schema of the parent
+// object and the name of the property are returned as well.
+func jsonSchemaNode(root map[string]any, path *walk.Path) (node map[string]any, parent map[string]any, name string) {
+	node = root
+	for step := path; step != nil; step = step.Next {
+		if step.Name != nil && *step.Name != CurrentElement {
+			if *step.Name == "*" {
+				parent, name = nil, ""
+				node = jsonSubSchema(node, "additionalProperties")
+			} else {
+				properties, ok := node["properties"].(map[string]any)
+				if !ok {
+					properties = map[string]any{}
+					node["properties"] = properties
+				}
+				parent, name = node, *step.Name
+				node = jsonSubSchema(properties, *step.Name)
+			}
+		}
+		if step.Type == walk.PathTypeArray {
+			parent, name = nil, ""
+			node = jsonSubSchema(node, "items")
+		}
+	}
+	return node, parent, name
+}
+
+func jsonSubSchema(node map[string]any, key string) map[string]any {
+	sub, ok := node[key].(map[string]any)
+	if !ok {
+		sub = map[string]any{}
+		node[key] = sub
+	}
+	return sub
+}
+
+func exportJSONSchemaField(node map[string]any, field *Field) {
+	fieldType := ""
+	for _, v := range field.Validators {
+		if v.IsType() {
+			fieldType = jsonSchemaFieldType(v)
+			break
+		}
+	}
+
+	for _, v := range field.Validators {
+		exportJSONSchemaValidator(node, v, fieldType)
+	}
+
+	if field.IsNullable() {
+		if t, ok := node["type"].(string); ok {
+			node["type"] = []any{t, "null"}
+		}
+	}
+
+	if field.Elements != nil {
+		exportJSONSchemaField(jsonSubSchema(node, "items"), field.Elements)
+	}
+}
+
+func jsonSchemaFieldType(v Validator) string {
+	switch v.(type) {
+	case *IntValidator, *Int8Validator, *Int16Validator, *Int32Validator, *Int64Validator,
+		*UintValidator, *Uint8Validator, *Uint16Validator, *Uint32Validator, *Uint64Validator,
+		*Float32Validator, *Float64Validator:
+		return FieldTypeNumeric
+	case *ArrayValidator:
+		return FieldTypeArray
+	case *ObjectValidator:
+		return FieldTypeObject
+	case *FileValidator:
+		return FieldTypeFile
+	case *BoolValidator:
+		return FieldTypeBool
+	default:
+		return FieldTypeString
+	}
+}
+
+func exportJSONSchemaValidator(node map[string]any, validator Validator, fieldType string) {
+	switch v := validator.(type) {
+	case *RequiredValidator, *NullableValidator:
+		// Handled by the field
+	case *StringValidator:
+		node["type"] = "string"
+	case *IntValidator, *Int8Validator, *Int16Validator, *Int32Validator, *Int64Validator:
+		node["type"] = "integer"
+	case *UintValidator, *Uint8Validator, *Uint16Validator, *Uint32Validator, *Uint64Validator:
+		node["type"] = "integer"
+		if _, ok := node["minimum"]; !ok {
+			node["minimum"] = 0
+		}
+	case *Float32Validator, *Float64Validator:
+		node["type"] = "number"
+	case *BoolValidator:
+		node["type"] = "boolean"
+	case *ArrayValidator:
+		node["type"] = "array"
+	case *ObjectValidator:
+		node["type"] = "object"
+	case *FileValidator:
+		node["type"] = "string"
+		node["format"] = "binary"
+	case *EmailValidator:
+		node["type"] = "string"
+		node["format"] = "email"
+	case *URLValidator:
+		node["type"] = "string"
+		node["format"] = "uri"
+	case *IPv4Validator:
+		node["type"] = "string"
+		node["format"] = "ipv4"
+	case *IPv6Validator:
+		node["type"] = "string"
+		node["format"] = "ipv6"
+	case *JSONValidator:
+		node["type"] = "string"
+		node["contentMediaType"] = "application/json"
+	case *UUIDValidator:
+		node["type"] = "string"
+		node["format"] = "uuid"
+		if len(v.AcceptedVersions) > 0 {
+			node[JSONSchemaExtensionPrefix+v.Name()] = lo.Map(v.AcceptedVersions, func(version uuid.Version, _ int) any { return strconv.Itoa(int(version)) })
+		}
+	case *DateValidator:
+		node["type"] = "string"
+		switch {
+		case slices.Equal(v.Formats, []string{time.DateOnly}):
+			node["format"] = "date"
+		case slices.Equal(v.Formats, []string{time.RFC3339}):
+			node["format"] = "date-time"
+		default:
+			node[JSONSchemaExtensionPrefix+v.Name()] = lo.ToAnySlice(v.Formats)
+		}
+	case *RegexValidator:
+		node["pattern"] = v.Regexp.String()
+	case *DefaultValidator:
+		node["default"] = v.Value
+	case *KeysInValidator:
+		node["propertyNames"] = map[string]any{"enum": lo.ToAnySlice(v.Keys)}
+	case *MinValidator:
+		exportJSONSchemaSize(node, v, fieldType, &v.Min, nil)
+	case *MaxValidator:
+		exportJSONSchemaSize(node, v, fieldType, nil, &v.Max)
+	case *BetweenValidator:
+		exportJSONSchemaSize(node, v, fieldType, &v.Min, &v.Max)
+	case *SizeValidator:
+		if fieldType == FieldTypeNumeric {
+			node["const"] = v.Size
+			return
+		}
+		size := float64(v.Size)
+		exportJSONSchemaSize(node, v, fieldType, &size, &size)
+	default:
+		if validator.Name() == "distinct" {
+			node["uniqueItems"] = true
+			return
+		}
+		if validator.Name() == "enum" {
+			// EnumValidator[T]: the names of the enum are the accepted strings
+			names := lo.Map(reflect.ValueOf(validator).Elem().FieldByName("Values").MapKeys(), func(k reflect.Value, _ int) string { return k.String() })
+			slices.Sort(names)
+			node["type"] = "string"
+			node["enum"] = lo.ToAnySlice(names)
+			return
+		}
+		if values, ok := enumValues(validator); ok {
+			if validator.Name() == "not_in" {
+				node["not"] = map[string]any{"enum": values}
+			} else {
+				node["enum"] = values
+			}
+			return
+		}
+		node[JSONSchemaExtensionPrefix+jsonSchemaExtensionName(validator)] = jsonSchemaExtensionValue(validator)
+	}
+}
+
+func exportJSONSchemaSize(node map[string]any, v Validator, fieldType string, min, max *float64) {
+	var minKey, maxKey string
+	isLength := true
+	switch fieldType {
+	case FieldTypeString:
+		minKey, maxKey = "minLength", "maxLength"
+	case FieldTypeArray:
+		minKey, maxKey = "minItems", "maxItems"
+	case FieldTypeObject:
+		minKey, maxKey = "minProperties", "maxProperties"
+	case FieldTypeNumeric:
+		minKey, maxKey = "minimum", "maximum"
+		isLength = false
+	default:
+		node[JSONSchemaExtensionPrefix+v.Name()] = jsonSchemaExtensionValue(v)
+		return
+	}
+	if min != nil {
+		node[minKey] = lo.Ternary[any](isLength, int(math.Ceil(*min)), *min)
+	}
+	if max != nil {
+		node[maxKey] = lo.Ternary[any](isLength, int(math.Floor(*max)), *max)
+	}
+}
+
+// enumValues returns the values of `InValidator` and `NotInValidator`.
+func enumValues(v Validator) ([]any, bool) {
+	if v.Name() != "in" && v.Name() != "not_in" {
+		return nil, false
+	}
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Pointer || val.Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+	field := val.Elem().FieldByName("Values")
+	if !field.IsValid() || field.Kind() != reflect.Slice {
+		return nil, false
+	}
+	values := make([]any, 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		values = append(values, field.Index(i).Interface())
+	}
+	return values, true
+}
+
+func jsonSchemaExtensionName(v Validator) string {
+	switch v.(type) {
+	case *AfterFieldValidator, *AfterEqualFieldValidator, *BeforeFieldValidator, *BeforeEqualFieldValidator, *DateEqualsFieldValidator:
+		return v.Name() + "_field"
+	case *RequiredIfValidator:
+		return "required_if"
+	case *ProhibitedIfValidator:
+		return "prohibited_if"
+	}
+	return v.Name()
+}
+
+// jsonSchemaExtensionValue returns the parameters of the given validator,
+// in the same format as the parameters of validation tags.
+func jsonSchemaExtensionValue(validator Validator) any {
+	var params []string
+	switch v := validator.(type) {
+	case *StartsWithValidator:
+		params = v.Prefix
+	case *EndsWithValidator:
+		params = v.Suffix
+	case *DoesntStartWithValidator:
+		params = v.Prefix
+	case *DoesntEndWithValidator:
+		params = v.Suffix
+	case *ExtensionValidator:
+		params = v.Extensions
+	case *ImageValidator:
+	case *MIMEValidator:
+		params = v.MIMETypes
+	case *FileCountValidator:
+		params = []string{strconv.FormatUint(uint64(v.Count), 10)}
+	case *MinFileCountValidator:
+		params = []string{strconv.FormatUint(uint64(v.Min), 10)}
+	case *MaxFileCountValidator:
+		params = []string{strconv.FormatUint(uint64(v.Max), 10)}
+	case *FileCountBetweenValidator:
+		params = []string{strconv.FormatUint(uint64(v.Min), 10), strconv.FormatUint(uint64(v.Max), 10)}
+	case *MinValidator:
+		params = []string{strconv.FormatFloat(v.Min, 'f', -1, 64)}
+	case *MaxValidator:
+		params = []string{strconv.FormatFloat(v.Max, 'f', -1, 64)}
+	case *BetweenValidator:
+		params = []string{strconv.FormatFloat(v.Min, 'f', -1, 64), strconv.FormatFloat(v.Max, 'f', -1, 64)}
+	case *SizeValidator:
+		params = []string{strconv.Itoa(v.Size)}
+	case *GreaterThanValidator:
+		params = []string{v.Path.String()}
+	case *GreaterThanEqualValidator:
+		params = []string{v.Path.String()}
+	case *LowerThanValidator:
+		params = []string{v.Path.String()}
+	case *LowerThanEqualValidator:
+		params = []string{v.Path.String()}
+	case *SameValidator:
+		params = []string{v.Path.String()}
+	case *DifferentValidator:
+		params = []string{v.Path.String()}
+	case *AfterValidator:
+		params = []string{v.Date.Format(time.RFC3339Nano)}
+	case *AfterEqualValidator:
+		params = []string{v.Date.Format(time.RFC3339Nano)}
+	case *BeforeValidator:
+		params = []string{v.Date.Format(time.RFC3339Nano)}
+	case *BeforeEqualValidator:
+		params = []string{v.Date.Format(time.RFC3339Nano)}
+	case *DateEqualsValidator:
+		params = []string{v.Date.Format(time.RFC3339Nano)}
+	case *AfterFieldValidator:
+		params = []string{v.Path.String()}
+	case *AfterEqualFieldValidator:
+		params = []string{v.Path.String()}
+	case *BeforeFieldValidator:
+		params = []string{v.Path.String()}
+	case *BeforeEqualFieldValidator:
+		params = []string{v.Path.String()}
+	case *DateEqualsFieldValidator:
+		params = []string{v.Path.String()}
+	case *RequiredWithValidator:
+		params = lo.Map(v.Paths, func(p *walk.Path, _ int) string { return p.String() })
+	case *RequiredWithAllValidator:
+		params = lo.Map(v.Paths, func(p *walk.Path, _ int) string { return p.String() })
+	case *RequiredWithoutValidator:
+		params = lo.Map(v.Paths, func(p *walk.Path, _ int) string { return p.String() })
+	case *ExistsBatchValidator:
+		params = []string{v.Table, v.Column}
+	case *UniqueBatchValidator:
+		params = []string{v.Table, v.Column}
+	}
+	if len(params) == 0 {
+		return true
+	}
+	return lo.ToAnySlice(params)
+}
+
+//------------------------------
+
+// FromJSONSchema builds a new `RuleSet` from the given JSON Schema document.
+// This is the reverse operation of `ToJSONSchema()`. Because `RuleSet`s are not
+// meant to be re-used, this function should be called for every validation.
+//
+// The following keywords are supported: `type`, `properties`, `required`,
+// `additionalProperties`, `items`, `enum`, `const`, `not` (with `enum` only), `pattern`,
+// `format`, `contentMediaType`, `default`, `minLength`, `maxLength`, `minimum`, `maximum`, `minItems`,
+// `maxItems`, `minProperties`, `maxProperties`, `uniqueItems` and `propertyNames` (with `enum` only).
+// Annotations are ignored, as well as unknown formats. The `x-goyave-*` extension keywords
+// are converted using the rules registered for validation tags (see `RegisterTag()`).
+//
+// Extension keywords of rules querying the database (`exists` and `unique`) and of rules
+// registered or replaced with `RegisterTag()`, which may have side effects, are rejected
+// unless their name is given in `allowedRules`. Schemas are often received from outside the
+// application: only allow these rules if the schema is trusted.
+//
+// As the keywords of a schema are not ordered, the transformers (`default`, `x-goyave-trim`,
+// `x-goyave-lowercase`, etc) are placed before the other rules of the field, right after
+// `Required`, `Nullable` and the type rule, so the constraints apply to the transformed value.
+//
+// Returns an error if the schema contains an unsupported keyword (such as `$ref` or `oneOf`),
+// so constraints are never silently ignored.
+func FromJSONSchema(schema map[string]any, allowedRules ...string) (RuleSet, error) {
+	imp := &jsonSchemaImport{allowedRules: allowedRules}
+	if err := imp.importSchema(CurrentElement, schema, false); err != nil {
+		return nil, fmt.Errorf("validation.FromJSONSchema: %w", err)
+	}
+	return imp.set, nil
+}
+
+type jsonSchemaImport struct {
+	set          RuleSet
+	allowedRules []string
+}
+
+func (imp *jsonSchemaImport) importSchema(path string, schema map[string]any, required bool) error {
+	types, nullable, err := jsonSchemaTypes(schema["type"])
+	if err != nil {
+		return jsonSchemaError(path, err)
+	}
+	schemaType := ""
+	if len(types) == 1 {
+		schemaType = types[0]
+	}
+
+	rules := List{}
+	fieldIndex := len(imp.set)
+	imp.set = append(imp.set, &FieldRules{Path: path})
+
+	keys := lo.Keys(schema)
+	slices.Sort(keys)
+	for _, key := range keys {
+		validators, err := imp.importKeyword(path, schema, key, schemaType)
+		if err != nil {
+			return jsonSchemaError(path, err)
+		}
+		rules = append(rules, validators...)
+	}
+	// Keywords are unordered: transformers are applied first so the constraints
+	// are checked against the transformed value.
+	slices.SortStableFunc(rules, func(a, b Validator) int {
+		return jsonSchemaTransformerOrder(a) - jsonSchemaTransformerOrder(b)
+	})
+
+	hasType := lo.ContainsBy(rules, func(v Validator) bool { return v.IsType() })
+	presence := List{}
+	if required {
+		presence = append(presence, Required())
+	}
+	if nullable {
+		presence = append(presence, Nullable())
+	}
+	if !hasType {
+		if typeValidator := jsonSchemaTypeValidator(schemaType); typeValidator != nil {
+			presence = append(presence, typeValidator)
+		}
+	}
+	imp.set[fieldIndex].Rules = append(presence, rules...)
+
+	if path == CurrentElement && len(imp.set[fieldIndex].Rules.(List)) == 0 {
+		imp.set = append(imp.set[:fieldIndex], imp.set[fieldIndex+1:]...)
+	}
+	return nil
+}
+
+// jsonSchemaTransformers the transformers imported from a JSON Schema, in the
+// order they are applied. They are applied before the other validators.
+var jsonSchemaTransformers = []string{
+	"default", "strip_html", "nfc", "trim", "collapse_whitespace", "lowercase", "uppercase", "to_slug",
+}
+
+// jsonSchemaTransformerOrder returns the position of the given validator in `jsonSchemaTransformers`,
+// or the length of `jsonSchemaTransformers` if it is not a transformer.
+func jsonSchemaTransformerOrder(v Validator) int {
+	switch v.(type) {
+	case *DefaultValidator, *StripHTMLValidator, *NFCValidator, *TrimValidator,
+		*CollapseWhitespaceValidator, *LowercaseValidator, *UppercaseValidator, *SlugValidator:
+		return slices.Index(jsonSchemaTransformers, v.Name())
+	}
+	return len(jsonSchemaTransformers)
+}
+
+func jsonSchemaError(path string, err error) error {
+	if path == CurrentElement {
+		return err
+	}
+	return fmt.Errorf("%q: %w", path, err)
+}
+
+func (imp *jsonSchemaImport) importKeyword(path string, schema map[string]any, key, schemaType string) (List, error) {
+	value := schema[key]
+	switch key {
+	case "type", "required":
+		return nil, nil
+	case "properties":
+		properties, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%q must be an object", key)
+		}
+		required, err := jsonSchemaStrings(schema["required"])
+		if err != nil {
+			return nil, fmt.Errorf("\"required\": %w", err)
+		}
+		names := lo.Keys(properties)
+		slices.Sort(names)
+		for _, name := range names {
+			if strings.ContainsAny(name, ".[]*") {
+				return nil, fmt.Errorf("unsupported property name %q", name)
+			}
+			propertySchema, ok := properties[name].(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("property %q must be a schema object", name)
+			}
+			if err := imp.importSchema(joinJSONSchemaPath(path, name), propertySchema, lo.Contains(required, name)); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case "additionalProperties":
+		switch v := value.(type) {
+		case bool:
+			if v {
+				return nil, nil
+			}
+			properties, _ := schema["properties"].(map[string]any)
+			keys := lo.Keys(properties)
+			slices.Sort(keys)
+			return List{KeysIn(keys...)}, nil
+		case map[string]any:
+			return nil, imp.importSchema(joinJSONSchemaPath(path, "*"), v, false)
+		}
+		return nil, fmt.Errorf("%q must be a boolean or a schema object", key)
+	case "items":
+		items, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%q must be a schema object", key)
+		}
+		return nil, imp.importSchema(path+"[]", items, false)
+	case "enum", "const":
+		values, ok := value.([]any)
+		if key == "const" {
+			values, ok = []any{value}, true
+		}
+		if !ok {
+			return nil, fmt.Errorf("%q must be an array", key)
+		}
+		v, err := jsonSchemaEnum(values, schemaType, false)
+		return List{v}, err
+	case "not":
+		not, ok := value.(map[string]any)
+		values, okEnum := not["enum"].([]any)
+		if !ok || !okEnum || len(not) != 1 {
+			return nil, fmt.Errorf("%q is only supported with \"enum\"", key)
+		}
+		v, err := jsonSchemaEnum(values, schemaType, true)
+		return List{v}, err
+	case "default":
+		return List{Default(value)}, nil
+	case "pattern":
+		pattern, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%q must be a string", key)
+		}
+		r, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return List{Regex(r)}, nil
+	case "format":
+		return jsonSchemaFormat(value), nil
+	case "contentMediaType":
+		if value == "application/json" {
+			return List{JSON()}, nil
+		}
+		return nil, nil
+	case "minLength", "minimum", "minItems", "minProperties":
+		n, err := jsonSchemaNumber(key, value)
+		return List{Min(n)}, err
+	case "maxLength", "maximum", "maxItems", "maxProperties":
+		n, err := jsonSchemaNumber(key, value)
+		return List{Max(n)}, err
+	case "uniqueItems":
+		if value != true {
+			return nil, nil
+		}
+		items, _ := schema["items"].(map[string]any)
+		itemTypes, _, _ := jsonSchemaTypes(items["type"])
+		switch {
+		case slices.Equal(itemTypes, []string{"string"}):
+			return List{Distinct[string]()}, nil
+		case slices.Equal(itemTypes, []string{"integer"}):
+			return List{Distinct[int]()}, nil
+		case slices.Equal(itemTypes, []string{"number"}):
+			return List{Distinct[float64]()}, nil
+		case slices.Equal(itemTypes, []string{"boolean"}):
+			return List{Distinct[bool]()}, nil
+		}
+		return nil, fmt.Errorf("%q requires \"items\" with a scalar type", key)
+	case "propertyNames":
+		names, ok := value.(map[string]any)
+		keys, err := jsonSchemaStrings(names["enum"])
+		if !ok || err != nil || len(names) != 1 {
+			return nil, fmt.Errorf("%q is only supported with \"enum\"", key)
+		}
+		return List{KeysIn(keys...)}, nil
+	}
+
+	if name, ok := strings.CutPrefix(key, JSONSchemaExtensionPrefix); ok {
+		v, err := imp.importExtension(path, name, value, schemaType)
+		return List{v}, err
+	}
+	if lo.Contains(jsonSchemaAnnotations, key) {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unsupported keyword %q", key)
+}
+
+func joinJSONSchemaPath(path, name string) string {
+	if path == CurrentElement {
+		return name
+	}
+	return path + "." + name
+}
+
+func jsonSchemaTypes(value any) (types []string, nullable bool, err error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, false, nil
+	case string:
+		types = []string{v}
+	case []any:
+		types, err = jsonSchemaStrings(v)
+		if err != nil {
+			return nil, false, fmt.Errorf("\"type\": %w", err)
+		}
+	default:
+		return nil, false, fmt.Errorf("\"type\" must be a string or an array of strings")
+	}
+	nullable = lo.Contains(types, "null")
+	return lo.Without(types, "null"), nullable, nil
+}
+
+func jsonSchemaTypeValidator(schemaType string) Validator {
+	switch schemaType {
+	case "string":
+		return String()
+	case "integer":
+		return Int()
+	case "number":
+		return Float64()
+	case "boolean":
+		return Bool()
+	case "array":
+		return Array()
+	case "object":
+		return Object()
+	}
+	return nil
+}
+
+func jsonSchemaReflectType(schemaType string) reflect.Type {
+	switch schemaType {
+	case "string":
+		return reflect.TypeOf("")
+	case "integer":
+		return reflect.TypeOf(0)
+	case "number":
+		return reflect.TypeOf(0.0)
+	case "boolean":
+		return reflect.TypeOf(false)
+	case "array":
+		return reflect.TypeOf([]any{})
+	case "object":
+		return reflect.TypeOf(map[string]any{})
+	}
+	return reflect.TypeOf((*any)(nil)).Elem()
+}
+
+func jsonSchemaFormat(format any) List {
+	switch format {
+	case "email":
+		return List{Email()}
+	case "uri":
+		return List{URL()}
+	case "uuid":
+		return List{UUID()}
+	case "date":
+		return List{Date(time.DateOnly)}
+	case "date-time":
+		return List{Date(time.RFC3339)}
+	case "ipv4":
+		return List{IPv4()}
+	case "ipv6":
+		return List{IPv6()}
+	case "binary":
+		return List{File()}
+	}
+	// Unknown formats are annotations
+	return nil
+}
+
+func jsonSchemaNumber(key string, value any) (float64, error) {
+	n, ok, err := numberAsFloat64(value)
+	if !ok || err != nil {
+		return 0, fmt.Errorf("%q must be a number", key)
+	}
+	return n, nil
+}
+
+func jsonSchemaStrings(value any) ([]string, error) {
+	if value == nil {
+		return []string{}, nil
+	}
+	list, ok := value.([]any)
+	if !ok {
+		if strs, ok := value.([]string); ok {
+			return strs, nil
+		}
+		return nil, fmt.Errorf("must be an array of strings")
+	}
+	strs := make([]string, 0, len(list))
+	for _, v := range list {
+		str, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be an array of strings")
+		}
+		strs = append(strs, str)
+	}
+	return strs, nil
+}
+
+func jsonSchemaEnum(values []any, schemaType string, not bool) (Validator, error) {
+	if schemaType == "" && lo.EveryBy(values, func(v any) bool { _, ok := v.(string); return ok }) {
+		schemaType = "string"
+	}
+	switch schemaType {
+	case "string":
+		return newJSONSchemaEnum(values, not, func(v any) (string, bool) {
+			s, ok := v.(string)
+			return s, ok
+		})
+	case "integer":
+		return newJSONSchemaEnum(values, not, func(v any) (int, bool) {
+			f, ok, err := numberAsFloat64(v)
+			return int(f), ok && err == nil && f == math.Trunc(f)
+		})
+	case "number":
+		return newJSONSchemaEnum(values, not, func(v any) (float64, bool) {
+			f, ok, err := numberAsFloat64(v)
+			return f, ok && err == nil
+		})
+	case "boolean":
+		return newJSONSchemaEnum(values, not, func(v any) (bool, bool) {
+			b, ok := v.(bool)
+			return b, ok
+		})
+	}
+	return nil, fmt.Errorf("enumerations require a scalar \"type\"")
+}
+
+func newJSONSchemaEnum[T comparable](values []any, not bool, convert func(any) (T, bool)) (Validator, error) {
+	typed := make([]T, 0, len(values))
+	for _, v := range values {
+		t, ok := convert(v)
+		if !ok {
+			return nil, fmt.Errorf("invalid enumeration value %v", v)
+		}
+		typed = append(typed, t)
+	}
+	if not {
+		return NotIn(typed), nil
+	}
+	return In(typed), nil
+}
+
+func (imp *jsonSchemaImport) importExtension(path, name string, value any, schemaType string) (Validator, error) {
+	factory, ok := lookupTag(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown validation rule %q", name)
+	}
+	if isRestrictedTag(name) && !lo.Contains(imp.allowedRules, name) {
+		return nil, fmt.Errorf("validation rule %q is not allowed", name)
+	}
+	params := []string{}
+	switch v := value.(type) {
+	case bool:
+		if !v {
+			return nil, fmt.Errorf("%q must be true or an array of parameters", JSONSchemaExtensionPrefix+name)
+		}
+	case []any:
+		for _, p := range v {
+			params = append(params, fmt.Sprintf("%v", p))
+		}
+	default:
+		return nil, fmt.Errorf("%q must be true or an array of parameters", JSONSchemaExtensionPrefix+name)
+	}
+	return factory(&TagContext{Type: jsonSchemaReflectType(schemaType), Path: path, Params: params})
+}
@@ -0,0 +1,296 @@
+package validation
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/lang"
+)
+
+func TestToJSONSchema(t *testing.T) {
+	t.Run("schema", func(t *testing.T) {
+		rules := RuleSet{
+			{Path: "name", Rules: List{Required(), String(), Between(2, 50)}},
+			{Path: "email", Rules: List{Required(), Email(), Max(255)}},
+			{Path: "age", Rules: List{Nullable(), Uint8(), Max(150)}},
+			{Path: "score", Rules: List{Float64(), Min(0.5)}},
+			{Path: "code", Rules: List{String(), Regex(regexp.MustCompile(`^[A-Z]+$`)), StartsWith("A", "B")}},
+			{Path: "role", Rules: List{String(), In([]string{"admin", "user"})}},
+			{Path: "level", Rules: List{Int(), NotIn([]int{0})}},
+			{Path: "birthday", Rules: List{Date("2006-01-02")}},
+			{Path: "created_at", Rules: List{Date(time.RFC3339), After(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))}},
+			{Path: "tags", Rules: List{Required(), Array(), Min(1), Distinct[string]()}},
+			{Path: "tags[]", Rules: List{String(), Max(10)}},
+			{Path: "address", Rules: List{Required(), Object(), KeysIn("city")}},
+			{Path: "address.city", Rules: List{Required(), String(), Same("name")}},
+			{Path: "meta", Rules: List{Object()}},
+			{Path: "meta.*", Rules: List{String()}},
+		}
+
+		schema := ToJSONSchema(rules)
+		raw, err := json.Marshal(schema)
+		require.NoError(t, err)
+
+		expected := `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"required": ["name", "email", "tags", "address"],
+			"properties": {
+				"name": {"type": "string", "minLength": 2, "maxLength": 50},
+				"email": {"type": "string", "format": "email", "maxLength": 255},
+				"age": {"type": ["integer", "null"], "minimum": 0, "maximum": 150},
+				"score": {"type": "number", "minimum": 0.5},
+				"code": {"type": "string", "pattern": "^[A-Z]+$", "x-goyave-starts_with": ["A", "B"]},
+				"role": {"type": "string", "enum": ["admin", "user"]},
+				"level": {"type": "integer", "not": {"enum": [0]}},
+				"birthday": {"type": "string", "format": "date"},
+				"created_at": {"type": "string", "format": "date-time", "x-goyave-after": ["2020-01-01T00:00:00Z"]},
+				"tags": {"type": "array", "minItems": 1, "uniqueItems": true, "items": {"type": "string", "maxLength": 10}},
+				"address": {
+					"type": "object",
+					"propertyNames": {"enum": ["city"]},
+					"required": ["city"],
+					"properties": {
+						"city": {"type": "string", "x-goyave-same": ["name"]}
+					}
+				},
+				"meta": {"type": "object", "additionalProperties": {"type": "string"}}
+			}
+		}`
+		assert.JSONEq(t, expected, string(raw))
+	})
+
+	t.Run("root_element", func(t *testing.T) {
+		schema := ToJSONSchema(RuleSet{
+			{Path: CurrentElement, Rules: List{Array(), Max(3)}},
+			{Path: "[]", Rules: List{Int()}},
+		})
+		assert.Equal(t, map[string]any{
+			"$schema":  JSONSchemaDialect,
+			"type":     "array",
+			"maxItems": 3,
+			"items":    map[string]any{"type": "integer"},
+		}, schema)
+	})
+
+	t.Run("transformers", func(t *testing.T) {
+		schema := ToJSONSchema(RuleSet{
+			{Path: "status", Rules: List{Default("draft"), Lowercase(), Enum(transformTestStatusPublished, transformTestStatusDraft)}},
+		})
+		properties := schema["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{
+			"type":               "string",
+			"default":            "draft",
+			"enum":               []any{"draft", "published"},
+			"x-goyave-lowercase": true,
+		}, properties["status"])
+
+		set, err := FromJSONSchema(schema)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"string", "default", "lowercase", "in"}, ruleNames(set[1].Rules))
+		assert.Equal(t, Default("draft"), set[1].Rules.(List)[1])
+	})
+
+	t.Run("function_validators", func(t *testing.T) {
+		schema := ToJSONSchema(RuleSet{
+			{Path: "a", Rules: List{RequiredIf(func(_ *Context) bool { return true }), String()}},
+		})
+		properties := schema["properties"].(map[string]any)
+		assert.Equal(t, map[string]any{"type": "string", "x-goyave-required_if": true}, properties["a"])
+		assert.NotContains(t, schema, "required")
+	})
+}
+
+func TestFromJSONSchema(t *testing.T) {
+	t.Run("rules", func(t *testing.T) {
+		schema := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"title": "User",
+			"type": "object",
+			"required": ["name", "tags"],
+			"additionalProperties": false,
+			"properties": {
+				"name": {"type": "string", "minLength": 2, "maxLength": 50, "description": "The name"},
+				"email": {"type": "string", "format": "email"},
+				"age": {"type": ["integer", "null"], "minimum": 0},
+				"role": {"type": "string", "enum": ["admin", "user"]},
+				"level": {"type": "integer", "not": {"enum": [0]}},
+				"code": {"type": "string", "pattern": "^[A-Z]+$", "x-goyave-starts_with": ["A"]},
+				"created_at": {"type": "string", "format": "date-time"},
+				"unknown_format": {"type": "string", "format": "hostname"},
+				"tags": {"type": "array", "uniqueItems": true, "items": {"type": "string"}},
+				"meta": {"type": "object", "additionalProperties": {"type": "number"}}
+			}
+		}`), &schema))
+
+		set, err := FromJSONSchema(schema)
+		require.NoError(t, err)
+
+		got := make(map[string][]string, len(set))
+		paths := make([]string, 0, len(set))
+		for _, f := range set {
+			got[f.Path] = ruleNames(f.Rules)
+			paths = append(paths, f.Path)
+		}
+		assert.Equal(t, map[string][]string{
+			CurrentElement:   {"object", "keys_in"},
+			"name":           {"required", "string", "max", "min"},
+			"email":          {"email"},
+			"age":            {"nullable", "int", "min"},
+			"role":           {"string", "in"},
+			"level":          {"int", "not_in"},
+			"code":           {"string", "regex", "starts_with"},
+			"created_at":     {"date"},
+			"unknown_format": {"string"},
+			"tags":           {"required", "array", "distinct"},
+			"tags[]":         {"string"},
+			"meta":           {"object"},
+			"meta.*":         {"float64"},
+		}, got)
+		assert.Equal(t, CurrentElement, paths[0])
+
+		for _, f := range set {
+			switch f.Path {
+			case CurrentElement:
+				assert.Equal(t, []string{"age", "code", "created_at", "email", "level", "meta", "name", "role", "tags", "unknown_format"}, f.Rules.(List)[1].(*KeysInValidator).Keys)
+			case "role":
+				assert.Equal(t, []string{"admin", "user"}, f.Rules.(List)[1].(*InValidator[string]).Values)
+			case "level":
+				assert.Equal(t, []int{0}, f.Rules.(List)[1].(*NotInValidator[int]).Values)
+			case "tags":
+				assert.IsType(t, &DistinctValidator[string]{}, f.Rules.(List)[2])
+			case "created_at":
+				assert.Equal(t, []string{time.RFC3339}, f.Rules.(List)[0].(*DateValidator).Formats)
+			}
+		}
+	})
+
+	t.Run("round_trip", func(t *testing.T) {
+		rules := func() RuleSet {
+			return RuleSet{
+				{Path: "name", Rules: List{Required(), String(), Between(2, 5)}},
+				{Path: "starts_at", Rules: List{Required(), Date(time.RFC3339)}},
+				{Path: "ends_at", Rules: List{Required(), Date(time.RFC3339), AfterField("starts_at")}},
+				{Path: "items", Rules: List{Required(), Array(), Min(1)}},
+				{Path: "items[]", Rules: List{Object()}},
+				{Path: "items[].id", Rules: List{Required(), Int(), In([]int{1, 2, 3})}},
+			}
+		}
+
+		schema := ToJSONSchema(rules())
+		raw, err := json.Marshal(schema)
+		require.NoError(t, err)
+		decoded := map[string]any{}
+		require.NoError(t, json.Unmarshal(raw, &decoded))
+
+		imported, err := FromJSONSchema(decoded)
+		require.NoError(t, err)
+		exported := ToJSONSchema(imported)
+		assert.ElementsMatch(t, schema["required"], exported["required"])
+		delete(schema, "required")
+		delete(exported, "required")
+		assert.Equal(t, schema, exported)
+
+		data := map[string]any{
+			"name":      "abcdef",
+			"starts_at": "2024-01-02T00:00:00Z",
+			"ends_at":   "2024-01-01T00:00:00Z",
+			"items":     []any{map[string]any{"id": 4.0}},
+		}
+		errs, errsBag := Validate(&Options{Data: data, Rules: imported, Language: lang.New().GetDefault()})
+		assert.Nil(t, errsBag)
+		require.NotNil(t, errs)
+		assert.Contains(t, errs.Fields, "name")
+		assert.Contains(t, errs.Fields, "ends_at")
+		assert.Contains(t, errs.Fields["items"].Elements[0].Fields, "id")
+		assert.NotContains(t, errs.Fields, "starts_at")
+	})
+
+	t.Run("transformers_order", func(t *testing.T) {
+		original := RuleSet{
+			{Path: CurrentElement, Rules: List{Object()}},
+			{Path: "name", Rules: List{Required(), String(), Trim(), Lowercase(), Max(3)}},
+		}
+		imported, err := FromJSONSchema(ToJSONSchema(original))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"required", "string", "trim", "lowercase", "max"}, ruleNames(imported[1].Rules))
+
+		for _, name := range []string{"  AB ", "  ABCD "} {
+			validate := func(rules RuleSet) (*Errors, map[string]any) {
+				data := map[string]any{"name": name}
+				errs, errsBag := Validate(&Options{
+					Data:     data,
+					Rules:    rules,
+					Language: lang.New().GetDefault(),
+				})
+				require.Empty(t, errsBag)
+				return errs, data
+			}
+			originalErrs, originalData := validate(original)
+			importedErrs, importedData := validate(imported)
+			assert.Equal(t, originalErrs, importedErrs, name)
+			assert.Equal(t, originalData, importedData, name)
+		}
+	})
+
+	t.Run("allowed_rules", func(t *testing.T) {
+		schema := map[string]any{
+			"properties": map[string]any{
+				"a": map[string]any{"type": "integer", "x-goyave-exists": []any{"models", "id"}},
+			},
+		}
+		set, err := FromJSONSchema(schema, "exists")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"int", "exists"}, ruleNames(set[0].Rules))
+
+		schema["properties"].(map[string]any)["a"].(map[string]any)["x-goyave-exists"] = []any{"models WHERE 1=1; --", "id"}
+		_, err = FromJSONSchema(schema, "exists")
+		require.Error(t, err)
+		assert.Equal(t, `validation.FromJSONSchema: "a": invalid table name "models WHERE 1=1; --"`, err.Error())
+
+		RegisterTag("test_json_schema_custom", func(_ *TagContext) (Validator, error) {
+			return Trim(), nil
+		})
+		t.Cleanup(func() { RegisterTag("test_json_schema_custom", nil) })
+		schema = map[string]any{"x-goyave-test_json_schema_custom": true}
+		_, err = FromJSONSchema(schema)
+		require.Error(t, err)
+		assert.Equal(t, `validation.FromJSONSchema: validation rule "test_json_schema_custom" is not allowed`, err.Error())
+		set, err = FromJSONSchema(schema, "test_json_schema_custom")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"trim"}, ruleNames(set[0].Rules))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		cases := []struct {
+			schema map[string]any
+			desc   string
+			want   string
+		}{
+			{desc: "ref", schema: map[string]any{"$ref": "#/$defs/a"}, want: `validation.FromJSONSchema: unsupported keyword "$ref"`},
+			{desc: "nested_keyword", schema: map[string]any{"properties": map[string]any{"a": map[string]any{"oneOf": []any{}}}}, want: `validation.FromJSONSchema: "a": unsupported keyword "oneOf"`},
+			{desc: "property_name", schema: map[string]any{"properties": map[string]any{"a.b": map[string]any{}}}, want: `validation.FromJSONSchema: unsupported property name "a.b"`},
+			{desc: "unknown_extension", schema: map[string]any{"x-goyave-required_if": true}, want: `validation.FromJSONSchema: unknown validation rule "required_if"`},
+			{desc: "invalid_extension_value", schema: map[string]any{"x-goyave-trim": "yes"}, want: `validation.FromJSONSchema: "x-goyave-trim" must be true or an array of parameters`},
+			{desc: "enum_without_type", schema: map[string]any{"enum": []any{1.0, "a"}}, want: `validation.FromJSONSchema: enumerations require a scalar "type"`},
+			{desc: "not", schema: map[string]any{"not": map[string]any{"type": "string"}}, want: `validation.FromJSONSchema: "not" is only supported with "enum"`},
+			{desc: "invalid_type", schema: map[string]any{"type": 1}, want: `validation.FromJSONSchema: "type" must be a string or an array of strings`},
+			{desc: "exists_not_allowed", schema: map[string]any{"x-goyave-exists": []any{"models", "id"}}, want: `validation.FromJSONSchema: validation rule "exists" is not allowed`},
+			{desc: "unique_not_allowed", schema: map[string]any{"properties": map[string]any{"a": map[string]any{"x-goyave-unique": []any{"models", "id"}}}}, want: `validation.FromJSONSchema: "a": validation rule "unique" is not allowed`},
+		}
+
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				set, err := FromJSONSchema(c.schema)
+				assert.Nil(t, set)
+				require.Error(t, err)
+				assert.Equal(t, c.want, err.Error())
+			})
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package validation
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"goyave.dev/goyave/v5/lang"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// Message a custom validation error message overriding the default language entry
+// (`validation.rules.<name>`) of a validator.
+//
+// If `Key` is set, the message is the language entry identified by this key, otherwise `Text`
+// is used as-is. In both cases, the placeholders (`:field` and the placeholders of the validator,
+// see `Validator.MessagePlaceholders()`) are replaced.
+type Message struct {
+	// Text literal message, for example "Choose a shorter username".
+	Text string
+
+	// Key language entry, for example "custom.username.max".
+	Key string
+}
+
+func (m Message) translate(language *lang.Language, placeholders []string) string {
+	if m.Key != "" {
+		return language.Get(m.Key, placeholders...)
+	}
+	message := m.Text
+	for i := 0; i < len(placeholders)-1; i += 2 {
+		message = strings.ReplaceAll(message, placeholders[i], placeholders[i+1])
+	}
+	return message
+}
+
+// Messages associates validator names (see `Validator.Name()`) with custom messages.
+// The message identified by the name of the validator followed by ".element"
+// (e.g. "max.element") is used for the errors on array elements reported by
+// validators such as `Distinct`. It falls back to the message identified by the name
+// of the validator if there is none.
+//
+//	validation.Messages{
+//		"max": {Text: "Choose a shorter username"},
+//		"regex": {Key: "custom.username.regex"},
+//	}
+type Messages map[string]Message
+
+func (m Messages) find(name string, element bool) (Message, bool) {
+	if element {
+		if message, ok := m[name+".element"]; ok {
+			return message, true
+		}
+	}
+	message, ok := m[name]
+	return message, ok
+}
+
+// MessageBag associates the paths of a `RuleSet` with custom messages.
+// See `RuleSet.WithMessages()`.
+type MessageBag map[string]Messages
+
+// WithMessages applies the given message bag to the fields of this `RuleSet` and returns it.
+// The messages defined in the `FieldRules` directly take precedence over the messages of the bag.
+//
+// The paths of the bag are relative to this `RuleSet`, so the messages are kept
+// when the `RuleSet` is composed into another one.
+//
+// Panics if a path of the bag doesn't match any field of the `RuleSet`.
+func (r RuleSet) WithMessages(bag MessageBag) RuleSet {
+	for path, messages := range bag {
+		found := false
+		for _, field := range r {
+			if field.Path != path {
+				continue
+			}
+			found = true
+			merged := maps.Clone(messages)
+			maps.Copy(merged, field.Messages)
+			field.Messages = merged
+		}
+		if !found {
+			panic(errors.NewSkip(fmt.Errorf("validation.RuleSet.WithMessages: no field matching path %q", path), 3))
+		}
+	}
+	return r
+}
@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v5/lang"
+)
+
+func TestMessages(t *testing.T) {
+	t.Run("field_messages", func(t *testing.T) {
+		errs, errsBag := Validate(&Options{
+			Data:     map[string]any{"username": "abcdef", "age": "a"},
+			Language: lang.New().GetDefault(),
+			Rules: RuleSet{
+				{Path: "username", Rules: List{Required(), String(), Max(5)}, Messages: Messages{
+					"max": {Text: "Choose a shorter username (:field, :max)"},
+				}},
+				{Path: "email", Rules: List{Required(), Email()}, Messages: Messages{
+					"required": {Key: "validation.rules.required", Text: "ignored"},
+				}},
+				{Path: "age", Rules: List{Int()}, Messages: Messages{
+					"max": {Text: "unused"},
+				}},
+			},
+		})
+		assert.Nil(t, errsBag)
+		assert.Equal(t, []string{"Choose a shorter username (username, 5)"}, errs.Fields["username"].Errors)
+		assert.Equal(t, []string{"The email address is required.", "The email address must be a valid email address."}, errs.Fields["email"].Errors)
+		assert.Equal(t, []string{"The age must be an integer."}, errs.Fields["age"].Errors)
+	})
+
+	t.Run("array_elements", func(t *testing.T) {
+		validator := func() *testValidator {
+			return &testValidator{
+				validateFunc: func(_ component, ctx *Context) bool {
+					ctx.AddArrayElementValidationErrors(1)
+					return true
+				},
+			}
+		}
+		errs, _ := Validate(&Options{
+			Data:     map[string]any{"a": []any{"x", "y"}, "b": []any{"x", "y"}, "c": []any{"x", 2}},
+			Language: lang.New().GetDefault(),
+			Rules: RuleSet{
+				{Path: "a", Rules: List{Array(), validator()}, Messages: Messages{
+					"test_validator": {Text: "a: :field"},
+				}},
+				{Path: "b", Rules: List{Array(), validator()}, Messages: Messages{
+					"test_validator":         {Text: "b: :field"},
+					"test_validator.element": {Text: "b element: :field"},
+				}},
+				{Path: "c", Rules: List{Array()}},
+				{Path: "c[]", Rules: List{String()}, Messages: Messages{
+					"string": {Text: "Each tag must be text"},
+				}},
+			},
+		})
+		assert.Equal(t, []string{"a: a"}, errs.Fields["a"].Elements[1].Errors)
+		assert.Equal(t, []string{"b element: b"}, errs.Fields["b"].Elements[1].Errors)
+		assert.Equal(t, []string{"Each tag must be text"}, errs.Fields["c"].Elements[1].Errors)
+	})
+
+	t.Run("message_bag", func(t *testing.T) {
+		address := RuleSet{
+			{Path: CurrentElement, Rules: List{Object()}},
+			{Path: "city", Rules: List{Required(), String()}},
+			{Path: "zip", Rules: List{Required(), String()}, Messages: Messages{
+				"required": {Text: "field level"},
+			}},
+		}.WithMessages(MessageBag{
+			"city": {"required": {Text: "Tell us where you live"}},
+			"zip":  {"required": {Text: "bag level"}, "string": {Text: "The zip code must be a string"}},
+		})
+
+		errs, _ := Validate(&Options{
+			Data:     map[string]any{"address": map[string]any{}},
+			Language: lang.New().GetDefault(),
+			Rules: RuleSet{
+				{Path: "address", Rules: address},
+			},
+		})
+		assert.Equal(t, []string{"Tell us where you live", "The city must be a string."}, errs.Fields["address"].Fields["city"].Errors)
+		assert.Equal(t, []string{"field level", "The zip code must be a string"}, errs.Fields["address"].Fields["zip"].Errors)
+	})
+
+	t.Run("message_bag_unknown_path", func(t *testing.T) {
+		assert.Panics(t, func() {
+			RuleSet{{Path: "a", Rules: List{String()}}}.WithMessages(MessageBag{"b": {}})
+		})
+	})
+}
@@ -0,0 +1,63 @@
+package validation
+
+// ProhibitedValidator the field under validation must be absent or `nil`.
+type ProhibitedValidator struct{ BaseValidator }
+
+// Validate checks the field under validation satisfies this validator's criteria.
+func (v *ProhibitedValidator) Validate(ctx *Context) bool {
+	return ctx.Value == nil
+}
+
+// Name returns the string name of the validator.
+func (v *ProhibitedValidator) Name() string { return "prohibited" }
+
+// Prohibited the field under validation must be absent or `nil`.
+// Because non-nullable fields are removed if they have a `nil` value, only
+// nullable fields can be `nil`.
+func Prohibited() *ProhibitedValidator {
+	return &ProhibitedValidator{}
+}
+
+//------------------------------
+
+// ProhibitedIfValidator is the same as `ProhibitedValidator` but only applies the behavior
+// described if the specified `Condition` function returns true.
+type ProhibitedIfValidator struct {
+	ProhibitedValidator
+	Condition func(*Context) bool
+}
+
+// Validate checks the field under validation satisfies this validator's criteria.
+func (v *ProhibitedIfValidator) Validate(ctx *Context) bool {
+	if !v.Condition(ctx) {
+		return true
+	}
+	return v.ProhibitedValidator.Validate(ctx)
+}
+
+// ProhibitedIf is the same as `Prohibited` but only applies the behavior
+// described if the specified condition function returns true.
+func ProhibitedIf(condition func(*Context) bool) *ProhibitedIfValidator {
+	return &ProhibitedIfValidator{Condition: condition}
+}
+
+//------------------------------
+
+// MissingValidator the field under validation must not be present in the input data,
+// not even with a `nil` value.
+type MissingValidator struct{ BaseValidator }
+
+// Validate always returns false because this validator is only executed if the field
+// is present in the input data.
+func (v *MissingValidator) Validate(_ *Context) bool {
+	return false
+}
+
+// Name returns the string name of the validator.
+func (v *MissingValidator) Name() string { return "missing" }
+
+// Missing the field under validation must not be present in the input data,
+// not even with a `nil` value.
+func Missing() *MissingValidator {
+	return &MissingValidator{}
+}
@@ -0,0 +1,104 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v5/lang"
+)
+
+func TestProhibitedValidator(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		v := Prohibited()
+		assert.NotNil(t, v)
+		assert.Equal(t, "prohibited", v.Name())
+		assert.False(t, v.IsType())
+		assert.False(t, v.IsTypeDependent())
+		assert.Empty(t, v.MessagePlaceholders(&Context{}))
+	})
+
+	cases := []struct {
+		value any
+		want  bool
+	}{
+		{value: "string", want: false},
+		{value: "", want: false},
+		{value: 0, want: false},
+		{value: nil, want: true},
+	}
+
+	for _, c := range cases {
+		v := Prohibited()
+		assert.Equal(t, c.want, v.Validate(&Context{Value: c.value}))
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		errs, errsBag := Validate(&Options{
+			Data:     map[string]any{"field": "value", "nullable": nil},
+			Language: lang.New().GetDefault(),
+			Rules: RuleSet{
+				{Path: "field", Rules: List{Prohibited()}},
+				{Path: "nullable", Rules: List{Prohibited(), Nullable()}},
+				{Path: "absent", Rules: List{Prohibited()}},
+			},
+		})
+		assert.Nil(t, errsBag)
+		assert.Equal(t, &Errors{Fields: FieldsErrors{"field": &Errors{Errors: []string{"The field is prohibited."}}}}, errs)
+	})
+}
+
+func TestProhibitedIfValidator(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		v := ProhibitedIf(func(_ *Context) bool { return true })
+		assert.NotNil(t, v)
+		assert.Equal(t, "prohibited", v.Name())
+		assert.False(t, v.IsType())
+		assert.False(t, v.IsTypeDependent())
+		assert.Empty(t, v.MessagePlaceholders(&Context{}))
+	})
+
+	assert.False(t, ProhibitedIf(func(_ *Context) bool { return true }).Validate(&Context{Value: "a"}))
+	assert.True(t, ProhibitedIf(func(_ *Context) bool { return false }).Validate(&Context{Value: "a"}))
+	assert.True(t, ProhibitedIf(func(_ *Context) bool { return true }).Validate(&Context{Value: nil}))
+}
+
+func TestMissingValidator(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		v := Missing()
+		assert.NotNil(t, v)
+		assert.Equal(t, "missing", v.Name())
+		assert.False(t, v.IsType())
+		assert.False(t, v.IsTypeDependent())
+		assert.Empty(t, v.MessagePlaceholders(&Context{}))
+		assert.False(t, v.Validate(&Context{}))
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		data := map[string]any{"field": "value", "nil": nil, "items": []any{map[string]any{"a": 1}, map[string]any{}}}
+		errs, errsBag := Validate(&Options{
+			Data:     data,
+			Language: lang.New().GetDefault(),
+			Rules: RuleSet{
+				{Path: "field", Rules: List{Missing()}},
+				{Path: "nil", Rules: List{Missing()}},
+				{Path: "absent", Rules: List{Missing()}},
+				{Path: "items", Rules: List{Array()}},
+				{Path: "items[]", Rules: List{Object()}},
+				{Path: "items[].a", Rules: List{Missing()}},
+			},
+		})
+		assert.Nil(t, errsBag)
+		want := &Errors{
+			Fields: FieldsErrors{
+				"field": &Errors{Errors: []string{"The field must not be present."}},
+				"nil":   &Errors{Errors: []string{"The nil must not be present."}},
+				"items": &Errors{
+					Elements: ArrayErrors{
+						0: &Errors{Fields: FieldsErrors{"a": &Errors{Errors: []string{"The a must not be present."}}}},
+					},
+				},
+			},
+		}
+		assert.Equal(t, want, errs)
+	})
+}
@@ -1,5 +1,23 @@
 package validation
 
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/samber/lo"
+	"goyave.dev/goyave/v5/lang"
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/walk"
+)
+
+// conditionalRequiredValidator is implemented by validators making the
+// field under validation required only if a condition is met.
+type conditionalRequiredValidator interface {
+	Validator
+	isRequired(ctx *Context) bool
+}
+
 // RequiredValidator the field under validation is required.
 // If a field is absent from the input data, subsequent validators
 // will not be executed.
@@ -48,8 +66,271 @@ func (v *RequiredIfValidator) Validate(ctx *Context) bool {
 	return v.RequiredValidator.Validate(ctx)
 }
 
+func (v *RequiredIfValidator) isRequired(ctx *Context) bool {
+	return v.Condition(ctx)
+}
+
 // RequiredIf is the same as `Required` but only applies the behavior
 // described if the specified condition function returns true.
+//
+// When checking if the field is absent, before the field's validators are executed,
+// `ctx.Data` is the whole input data, even if the field belongs to a composed rule set.
+// When executed as a validator, `ctx.Data` is the root element of the composed rule set,
+// like for any other validator.
 func RequiredIf(condition func(*Context) bool) *RequiredIfValidator {
 	return &RequiredIfValidator{Condition: condition}
 }
+
+//------------------------------
+
+// RequiredWithValidator is the same as `RequiredValidator` but only applies the behavior
+// described if at least one of the fields identified by the given paths is present.
+type RequiredWithValidator struct {
+	RequiredValidator
+	Paths []*walk.Path
+}
+
+// Validate checks the field under validation satisfies this validator's criteria.
+func (v *RequiredWithValidator) Validate(ctx *Context) bool {
+	if !v.isRequired(ctx) {
+		return true
+	}
+	return v.RequiredValidator.Validate(ctx)
+}
+
+func (v *RequiredWithValidator) isRequired(ctx *Context) bool {
+	return lo.SomeBy(v.Paths, func(p *walk.Path) bool {
+		return isPresent(ctx, p)
+	})
+}
+
+// Name returns the string name of the validator.
+func (v *RequiredWithValidator) Name() string { return "required_with" }
+
+// MessagePlaceholders returns the ":values" placeholder.
+func (v *RequiredWithValidator) MessagePlaceholders(_ *Context) []string {
+	return []string{
+		":values", fieldNames(v.Lang(), v.Paths),
+	}
+}
+
+// RequiredWith is the same as `Required` but only applies the behavior
+// described if at least one of the fields identified by the given paths is present.
+// A field is considered present if it exists in the input and is not `nil`.
+//
+// If the field under validation is inside an array, the array indexes shared with
+// the given paths are resolved. For example for the field "array[].a", the path
+// "array[].b" only matches the "b" field of the same array element.
+func RequiredWith(paths ...string) *RequiredWithValidator {
+	return &RequiredWithValidator{Paths: mustParsePaths("RequiredWith", paths)}
+}
+
+//------------------------------
+
+// RequiredWithAllValidator is the same as `RequiredValidator` but only applies the behavior
+// described if all the fields identified by the given paths are present.
+type RequiredWithAllValidator struct {
+	RequiredValidator
+	Paths []*walk.Path
+}
+
+// Validate checks the field under validation satisfies this validator's criteria.
+func (v *RequiredWithAllValidator) Validate(ctx *Context) bool {
+	if !v.isRequired(ctx) {
+		return true
+	}
+	return v.RequiredValidator.Validate(ctx)
+}
+
+func (v *RequiredWithAllValidator) isRequired(ctx *Context) bool {
+	return lo.EveryBy(v.Paths, func(p *walk.Path) bool {
+		return isPresent(ctx, p)
+	})
+}
+
+// Name returns the string name of the validator.
+func (v *RequiredWithAllValidator) Name() string { return "required_with_all" }
+
+// MessagePlaceholders returns the ":values" placeholder.
+func (v *RequiredWithAllValidator) MessagePlaceholders(_ *Context) []string {
+	return []string{
+		":values", fieldNames(v.Lang(), v.Paths),
+	}
+}
+
+// RequiredWithAll is the same as `Required` but only applies the behavior
+// described if all the fields identified by the given paths are present.
+// See `RequiredWith` for more details.
+func RequiredWithAll(paths ...string) *RequiredWithAllValidator {
+	return &RequiredWithAllValidator{Paths: mustParsePaths("RequiredWithAll", paths)}
+}
+
+//------------------------------
+
+// RequiredWithoutValidator is the same as `RequiredValidator` but only applies the behavior
+// described if at least one of the fields identified by the given paths is absent.
+type RequiredWithoutValidator struct {
+	RequiredValidator
+	Paths []*walk.Path
+}
+
+// Validate checks the field under validation satisfies this validator's criteria.
+func (v *RequiredWithoutValidator) Validate(ctx *Context) bool {
+	if !v.isRequired(ctx) {
+		return true
+	}
+	return v.RequiredValidator.Validate(ctx)
+}
+
+func (v *RequiredWithoutValidator) isRequired(ctx *Context) bool {
+	return lo.SomeBy(v.Paths, func(p *walk.Path) bool {
+		return !isPresent(ctx, p)
+	})
+}
+
+// Name returns the string name of the validator.
+func (v *RequiredWithoutValidator) Name() string { return "required_without" }
+
+// MessagePlaceholders returns the ":values" placeholder.
+func (v *RequiredWithoutValidator) MessagePlaceholders(_ *Context) []string {
+	return []string{
+		":values", fieldNames(v.Lang(), v.Paths),
+	}
+}
+
+// RequiredWithout is the same as `Required` but only applies the behavior
+// described if at least one of the fields identified by the given paths is absent.
+// See `RequiredWith` for more details.
+func RequiredWithout(paths ...string) *RequiredWithoutValidator {
+	return &RequiredWithoutValidator{Paths: mustParsePaths("RequiredWithout", paths)}
+}
+
+//------------------------------
+
+// RequiredUnlessValidator is the same as `RequiredValidator` but only applies the behavior
+// described if the field identified by the given path is not equal to any of the given values.
+type RequiredUnlessValidator struct {
+	RequiredValidator
+	Path   *walk.Path
+	Values []any
+}
+
+// Validate checks the field under validation satisfies this validator's criteria.
+func (v *RequiredUnlessValidator) Validate(ctx *Context) bool {
+	if !v.isRequired(ctx) {
+		return true
+	}
+	return v.RequiredValidator.Validate(ctx)
+}
+
+func (v *RequiredUnlessValidator) isRequired(ctx *Context) bool {
+	return !hasValue(ctx, v.Path, v.Values)
+}
+
+// Name returns the string name of the validator.
+func (v *RequiredUnlessValidator) Name() string { return "required_unless" }
+
+// MessagePlaceholders returns the ":other" and ":values" placeholders.
+func (v *RequiredUnlessValidator) MessagePlaceholders(_ *Context) []string {
+	return []string{
+		":other", GetFieldName(v.Lang(), v.Path),
+		":values", strings.Join(lo.Map(v.Values, func(v any, _ int) string { return fmt.Sprintf("%v", v) }), ", "),
+	}
+}
+
+// RequiredUnless is the same as `Required` but only applies the behavior
+// described if the field identified by the given path is absent or not equal to
+// any of the given values. Numbers are compared by value regardless of their type.
+// Other values are compared using `reflect.DeepEqual()`.
+// See `RequiredWith` for more details about the path resolution.
+func RequiredUnless(path string, values ...any) *RequiredUnlessValidator {
+	p, err := walk.Parse(path)
+	if err != nil {
+		panic(errors.NewSkip(fmt.Errorf("validation.RequiredUnless: path parse error: %w", err), 3))
+	}
+	return &RequiredUnlessValidator{Path: p, Values: values}
+}
+
+//------------------------------
+
+func mustParsePaths(validatorName string, paths []string) []*walk.Path {
+	parsed := make([]*walk.Path, 0, len(paths))
+	for _, path := range paths {
+		p, err := walk.Parse(path)
+		if err != nil {
+			panic(errors.NewSkip(fmt.Errorf("validation.%s: path parse error: %w", validatorName, err), 4))
+		}
+		parsed = append(parsed, p)
+	}
+	return parsed
+}
+
+func fieldNames(language *lang.Language, paths []*walk.Path) string {
+	return strings.Join(lo.Map(paths, func(p *walk.Path, _ int) string {
+		return translateFieldName(language, p.String())
+	}), ", ")
+}
+
+// resolvePath returns a clone of the given path, relative to `ctx.Data`. The array
+// indexes shared with the path of the field under validation are resolved so only
+// the elements at the same position in the arrays are matched.
+// For example, if the field under validation is "array[2].a", the path "array[].b"
+// is resolved to "array[2].b".
+func resolvePath(ctx *Context, path *walk.Path) *walk.Path {
+	resolved := path.Clone()
+	current := ctx.Path()
+	if ctx.Field != nil {
+		for i := uint(0); i < ctx.Field.prefixDepth && current != nil; i++ {
+			current = current.Next
+		}
+	}
+
+	for step := resolved; step != nil && current != nil; step, current = step.Next, current.Next {
+		if (step.Name == nil) != (current.Name == nil) || (step.Name != nil && *step.Name != *current.Name) || step.Type != current.Type {
+			break
+		}
+		if step.Type == walk.PathTypeArray && step.Index == nil && current.Index != nil && *current.Index >= 0 {
+			i := *current.Index
+			step.Index = &i
+		}
+	}
+	return resolved
+}
+
+// isPresent returns true if at least one element matching the given path exists
+// and is not `nil`.
+func isPresent(ctx *Context, path *walk.Path) bool {
+	present := false
+	resolvePath(ctx, path).Walk(ctx.Data, func(c *walk.Context) {
+		if c.Found == walk.Found && c.Value != nil {
+			present = true
+			c.Break()
+		}
+	})
+	return present
+}
+
+// hasValue returns true if at least one element matching the given path
+// is equal to one of the given values.
+func hasValue(ctx *Context, path *walk.Path, values []any) bool {
+	found := false
+	resolvePath(ctx, path).Walk(ctx.Data, func(c *walk.Context) {
+		if c.Found != walk.Found {
+			return
+		}
+		if lo.SomeBy(values, func(v any) bool { return valueEquals(c.Value, v) }) {
+			found = true
+			c.Break()
+		}
+	})
+	return found
+}
+
+func valueEquals(a, b any) bool {
+	fa, isNumberA, errA := numberAsFloat64(a)
+	fb, isNumberB, errB := numberAsFloat64(b)
+	if isNumberA && isNumberB && errA == nil && errB == nil {
+		return fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
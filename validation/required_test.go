@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/lang"
+	"goyave.dev/goyave/v5/util/walk"
 )
 
 func TestRequiredValidator(t *testing.T) {
@@ -94,4 +97,174 @@ func TestRequiredIfValidator(t *testing.T) {
 			assert.Equal(t, c.want, v.Validate(ctx))
 		})
 	}
+
+	t.Run("composition", func(t *testing.T) {
+		data := map[string]any{
+			"required": true,
+			"object":   map[string]any{},
+		}
+		var conditionData []any
+		composed := RuleSet{
+			{Path: "b", Rules: List{RequiredIf(func(ctx *Context) bool {
+				conditionData = append(conditionData, ctx.Data)
+				return ctx.Data.(map[string]any)["required"] == true
+			}), Int()}},
+		}
+		errs, errsBag := Validate(&Options{
+			Data:     data,
+			Language: lang.New().GetDefault(),
+			Rules: RuleSet{
+				{Path: "object", Rules: composed},
+			},
+		})
+		assert.Nil(t, errsBag)
+		// The condition receives the whole input data when checking if the field
+		// is absent, then the root of the composed rule set when executed as a validator.
+		require.Len(t, conditionData, 2)
+		assert.Equal(t, data, conditionData[0])
+		assert.Equal(t, data["object"], conditionData[1])
+		want := &Errors{
+			Fields: FieldsErrors{
+				"object": &Errors{
+					Fields: FieldsErrors{
+						"b": &Errors{Errors: []string{"The b must be an integer."}},
+					},
+				},
+			},
+		}
+		assert.Equal(t, want, errs)
+	})
+}
+
+func TestRequiredWithValidators(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		lang := lang.New().GetDefault()
+
+		v := RequiredWith("a", "b.c")
+		assert.Equal(t, "required_with", v.Name())
+		assert.False(t, v.IsType())
+		assert.False(t, v.IsTypeDependent())
+		assert.Equal(t, []*walk.Path{walk.MustParse("a"), walk.MustParse("b.c")}, v.Paths)
+		v.init(&Options{Language: lang})
+		assert.Equal(t, []string{":values", "a, c"}, v.MessagePlaceholders(&Context{}))
+
+		v2 := RequiredWithAll("a", "b")
+		assert.Equal(t, "required_with_all", v2.Name())
+		v2.init(&Options{Language: lang})
+		assert.Equal(t, []string{":values", "a, b"}, v2.MessagePlaceholders(&Context{}))
+
+		v3 := RequiredWithout("a")
+		assert.Equal(t, "required_without", v3.Name())
+		v3.init(&Options{Language: lang})
+		assert.Equal(t, []string{":values", "a"}, v3.MessagePlaceholders(&Context{}))
+
+		v4 := RequiredUnless("a", "x", 2)
+		assert.Equal(t, "required_unless", v4.Name())
+		assert.Equal(t, walk.MustParse("a"), v4.Path)
+		v4.init(&Options{Language: lang})
+		assert.Equal(t, []string{":other", "a", ":values", "x, 2"}, v4.MessagePlaceholders(&Context{}))
+
+		assert.Panics(t, func() { RequiredWith("invalid[]path") })
+		assert.Panics(t, func() { RequiredWithAll("invalid[]path") })
+		assert.Panics(t, func() { RequiredWithout("invalid[]path") })
+		assert.Panics(t, func() { RequiredUnless("invalid[]path") })
+	})
+
+	cases := []struct {
+		data      map[string]any
+		validator Validator
+		desc      string
+		want      bool
+	}{
+		{desc: "with_present", validator: RequiredWith("a", "b"), data: map[string]any{"b": 1}, want: false},
+		{desc: "with_absent", validator: RequiredWith("a", "b"), data: map[string]any{}, want: true},
+		{desc: "with_field_present", validator: RequiredWith("a"), data: map[string]any{"a": 1, "field": "x"}, want: true},
+		{desc: "with_nil", validator: RequiredWith("a"), data: map[string]any{"a": nil}, want: true},
+		{desc: "with_all_present", validator: RequiredWithAll("a", "b"), data: map[string]any{"a": 1, "b": 1}, want: false},
+		{desc: "with_all_partial", validator: RequiredWithAll("a", "b"), data: map[string]any{"a": 1}, want: true},
+		{desc: "without_absent", validator: RequiredWithout("a", "b"), data: map[string]any{"a": 1}, want: false},
+		{desc: "without_present", validator: RequiredWithout("a", "b"), data: map[string]any{"a": 1, "b": 1}, want: true},
+		{desc: "unless_other_value", validator: RequiredUnless("a", "x", 2), data: map[string]any{"a": "y"}, want: false},
+		{desc: "unless_absent", validator: RequiredUnless("a", "x"), data: map[string]any{}, want: false},
+		{desc: "unless_matching_string", validator: RequiredUnless("a", "x", 2), data: map[string]any{"a": "x"}, want: true},
+		{desc: "unless_matching_number", validator: RequiredUnless("a", "x", 2), data: map[string]any{"a": 2.0}, want: true},
+		{desc: "unless_matching_deep", validator: RequiredUnless("a", []any{"x"}), data: map[string]any{"a": []any{"x"}}, want: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			errs, errsBag := Validate(&Options{
+				Data:     c.data,
+				Language: lang.New().GetDefault(),
+				Rules: RuleSet{
+					{Path: "field", Rules: List{c.validator, String()}},
+				},
+			})
+			assert.Nil(t, errsBag)
+			assert.Equal(t, c.want, errs == nil)
+		})
+	}
+
+	t.Run("array_elements", func(t *testing.T) {
+		data := map[string]any{
+			"items": []any{
+				map[string]any{"a": 1, "b": 1},
+				map[string]any{"b": 1},
+				map[string]any{"a": 1},
+			},
+		}
+		errs, errsBag := Validate(&Options{
+			Data:     data,
+			Language: lang.New().GetDefault(),
+			Rules: RuleSet{
+				{Path: "items", Rules: List{Array()}},
+				{Path: "items[]", Rules: List{Object()}},
+				{Path: "items[].b", Rules: List{RequiredWith("items[].a"), Int()}},
+			},
+		})
+		assert.Nil(t, errsBag)
+		want := &Errors{
+			Fields: FieldsErrors{
+				"items": &Errors{
+					Elements: ArrayErrors{
+						2: &Errors{
+							Fields: FieldsErrors{
+								"b": &Errors{Errors: []string{"The b is required when a is present.", "The b must be an integer."}},
+							},
+						},
+					},
+				},
+			},
+		}
+		assert.Equal(t, want, errs)
+	})
+
+	t.Run("composition", func(t *testing.T) {
+		data := map[string]any{
+			"a":      1,
+			"object": map[string]any{"c": 1},
+		}
+		composed := RuleSet{
+			{Path: "b", Rules: List{RequiredWith("c"), Int()}},
+			{Path: "c", Rules: List{Int()}},
+		}
+		errs, errsBag := Validate(&Options{
+			Data:     data,
+			Language: lang.New().GetDefault(),
+			Rules: RuleSet{
+				{Path: "object", Rules: composed},
+			},
+		})
+		assert.Nil(t, errsBag)
+		want := &Errors{
+			Fields: FieldsErrors{
+				"object": &Errors{
+					Fields: FieldsErrors{
+						"b": &Errors{Errors: []string{"The b is required when c is present.", "The b must be an integer."}},
+					},
+				},
+			},
+		}
+		assert.Equal(t, want, errs)
+	})
 }
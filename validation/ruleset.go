@@ -88,6 +88,7 @@ type List []Validator
 
 func (l List) convert(path string, field *FieldRules, prefixDepth uint) Rules {
 	f := newField(path, field.Rules.(List), prefixDepth)
+	f.messages = field.Messages
 	return Rules{f}
 }
 
@@ -96,7 +97,11 @@ func (l List) convert(path string, field *FieldRules, prefixDepth uint) Rules {
 type FieldRules struct {
 	// TODO what behavior if there are duplicates? If it ever becomes a problem, can probably merge the Lists. But it's unnecessary for now.
 	Rules FieldRulesConverter
-	Path  string
+
+	// Messages custom messages overriding the default messages of the validators
+	// of this field. Ignored if `Rules` is a `RuleSet`.
+	Messages Messages
+	Path     string
 }
 
 // RuleSet definition of the validation rules applied on each field in the request.
@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"reflect"
+)
+
+// StaticRules pre-compiled `Rules` that can be re-used across validations and
+// used concurrently. Parsing the paths, sorting the fields and resolving the
+// array elements and compositions is done only once, in `Static()`. Each call
+// of `AsRules()` only creates fresh validator instances.
+//
+// Only use static rules for rule sets that don't depend on the request or on
+// any other per-validation data. The validators are shallowly copied: they
+// must not hold mutable state other than what the validation engine sets up for them.
+// Maps and slices used as `Default()` values are deep copied each time they are applied.
+type StaticRules struct {
+	rules Rules
+}
+
+// Static compiles the given rules so they can be re-used for multiple validations.
+// The returned `StaticRules` can be used directly as `Options.Rules`.
+//
+//	var userRules = validation.Static(validation.RuleSet{
+//		{Path: "name", Rules: validation.List{validation.Required(), validation.String()}},
+//	})
+func Static(rules Ruler) *StaticRules {
+	return &StaticRules{rules: rules.AsRules()}
+}
+
+// AsRules returns a copy of the compiled rules with fresh validator instances.
+func (s *StaticRules) AsRules() Rules {
+	rules := make(Rules, 0, len(s.rules))
+	for _, f := range s.rules {
+		rules = append(rules, f.clone())
+	}
+	return rules
+}
+
+// clone returns a deep copy of this field with fresh validator instances.
+func (f *Field) clone() *Field {
+	clone := &Field{
+		Path:        f.Path.Clone(),
+		Validators:  make([]Validator, 0, len(f.Validators)),
+		prefixDepth: f.prefixDepth,
+		messages:    f.messages,
+	}
+	for _, v := range f.Validators {
+		clone.Validators = append(clone.Validators, cloneValidator(v))
+	}
+	clone.bindValidators()
+	if f.Elements != nil {
+		clone.Elements = f.Elements.clone()
+	}
+	return clone
+}
+
+// cloneValidator returns a shallow copy of the given validator.
+func cloneValidator(v Validator) Validator {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Pointer || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return v
+	}
+	clone := reflect.New(val.Elem().Type())
+	clone.Elem().Set(val.Elem())
+	return clone.Interface().(Validator)
+}
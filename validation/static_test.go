@@ -0,0 +1,132 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/lang"
+)
+
+func staticTestRuleSet() RuleSet {
+	return RuleSet{
+		{Path: CurrentElement, Rules: List{Object()}},
+		{Path: "name", Rules: List{Required(), String(), Trim(), Max(5)}},
+		{Path: "nickname", Rules: List{RequiredIf(requiredIfTestFunction), Nullable(), String()}},
+		{Path: "role", Rules: List{String(), Default("user")}},
+		{Path: "tags", Rules: List{Array()}},
+		{Path: "tags[]", Rules: List{String(), Lowercase()}},
+		{Path: "composition", Rules: RuleSet{
+			{Path: CurrentElement, Rules: List{Object()}},
+			{Path: "prop", Rules: List{Required(), Int()}},
+		}},
+	}
+}
+
+func BenchmarkStaticRuleSet(b *testing.B) {
+	b.ReportAllocs()
+	static := Static(staticTestRuleSet())
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		static.AsRules()
+	}
+}
+
+func TestStaticRules(t *testing.T) {
+	t.Run("AsRules", func(t *testing.T) {
+		expected := staticTestRuleSet().AsRules()
+		static := Static(staticTestRuleSet())
+
+		rules := static.AsRules()
+		other := static.AsRules()
+		require.Len(t, rules, len(expected))
+
+		var checkField func(t *testing.T, expected, f, other *Field)
+		checkField = func(t *testing.T, expected, f, other *Field) {
+			assert.Equal(t, expected.Path, f.Path)
+			assert.NotSame(t, f.Path, other.Path)
+			assert.Equal(t, expected.prefixDepth, f.prefixDepth)
+			assert.Equal(t, expected.isArray, f.isArray)
+			assert.Equal(t, expected.isObject, f.isObject)
+			assert.Equal(t, expected.isNullable, f.isNullable)
+			assert.Equal(t, expected.isMissing, f.isMissing)
+			assert.Equal(t, expected.isRequired == nil, f.isRequired == nil)
+			assert.Equal(t, expected.isExcluded == nil, f.isExcluded == nil)
+			assert.Equal(t, expected.defaultValue == nil, f.defaultValue == nil)
+			require.Len(t, f.Validators, len(expected.Validators))
+			for i, v := range f.Validators {
+				assert.IsType(t, expected.Validators[i], v)
+				assert.Equal(t, expected.Validators[i].Name(), v.Name())
+				assert.NotSame(t, v, other.Validators[i])
+			}
+			if f.defaultValue != nil {
+				assert.Same(t, f.Validators[len(f.Validators)-1], f.defaultValue)
+			}
+			if expected.Elements == nil {
+				assert.Nil(t, f.Elements)
+				return
+			}
+			require.NotNil(t, f.Elements)
+			checkField(t, expected.Elements, f.Elements, other.Elements)
+		}
+
+		for i, f := range rules {
+			checkField(t, expected[i], f, other[i])
+		}
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		static := Static(staticTestRuleSet())
+		newData := func() map[string]any {
+			return map[string]any{
+				"name":        "  abcdefg ",
+				"tags":        []any{"A", "b"},
+				"composition": map[string]any{"prop": "a"},
+			}
+		}
+
+		for i := 0; i < 2; i++ {
+			data := newData()
+			errs, errsBag := Validate(&Options{
+				Data:     data,
+				Language: lang.New().GetDefault(),
+				Rules:    static,
+			})
+			assert.Nil(t, errsBag)
+			require.NotNil(t, errs)
+
+			expectedData := newData()
+			expectedErrs, _ := Validate(&Options{
+				Data:     expectedData,
+				Language: lang.New().GetDefault(),
+				Rules:    staticTestRuleSet(),
+			})
+			assert.Equal(t, expectedErrs, errs)
+			assert.Equal(t, expectedData, data)
+			assert.Equal(t, "user", data["role"])
+			assert.Equal(t, []string{"a", "b"}, data["tags"])
+		}
+	})
+
+	t.Run("default_value_copied", func(t *testing.T) {
+		defaultValue := []any{"z"}
+		static := Static(RuleSet{
+			{Path: CurrentElement, Rules: List{Object()}},
+			{Path: "l", Rules: List{Array(), Default(defaultValue)}},
+			{Path: "l[]", Rules: List{String(), Uppercase()}},
+		})
+
+		for i := 0; i < 2; i++ {
+			data := map[string]any{}
+			errs, errsBag := Validate(&Options{
+				Data:     data,
+				Language: lang.New().GetDefault(),
+				Rules:    static,
+			})
+			assert.Nil(t, errsBag)
+			assert.Nil(t, errs)
+			assert.Equal(t, []string{"Z"}, data["l"])
+			assert.Equal(t, []any{"z"}, defaultValue)
+		}
+	})
+}
@@ -0,0 +1,748 @@
+package validation
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+const (
+	// TagName the name of the struct tag read by `FromStruct()`.
+	TagName = "validate"
+
+	// TagDive special rule name used in validation tags. The rules following
+	// it are applied to the elements of the slice or array field instead of
+	// the field itself. It can be repeated for nested slices.
+	TagDive = "dive"
+)
+
+// TagContext information given to a `TagValidatorFactory` when creating a
+// new validator from a validation tag.
+type TagContext struct {
+	// Type the type of the field (or element if after `dive`). Pointers and
+	// `typeutil.Undefined` are unwrapped.
+	Type reflect.Type
+
+	// Path the path of the field in the generated `RuleSet`.
+	Path string
+
+	// Params the parameters of the rule. In a tag, parameters are written after
+	// an equal sign and separated by pipes: `between=1|10`.
+	Params []string
+}
+
+// TagValidatorFactory function creating a new validator from the parameters
+// of a rule written in a validation tag. A factory is called every time a `RuleSet`
+// is generated by `FromStruct()` and must return a new instance of validator each time.
+type TagValidatorFactory func(ctx *TagContext) (Validator, error)
+
+var (
+	tagRegistry = map[string]TagValidatorFactory{
+		"required":            noParams(func() Validator { return Required() }),
+		"nullable":            noParams(func() Validator { return Nullable() }),
+		"prohibited":          noParams(func() Validator { return Prohibited() }),
+		"missing":             noParams(func() Validator { return Missing() }),
+		"exclude":             noParams(func() Validator { return Exclude() }),
+		"accepted":            noParams(func() Validator { return Accepted() }),
+		"declined":            noParams(func() Validator { return Declined() }),
+		"required_with":       strings1(func(p ...string) Validator { return RequiredWith(p...) }),
+		"required_with_all":   strings1(func(p ...string) Validator { return RequiredWithAll(p...) }),
+		"required_without":    strings1(func(p ...string) Validator { return RequiredWithout(p...) }),
+		"string":              noParams(func() Validator { return String() }),
+		"bool":                noParams(func() Validator { return Bool() }),
+		"int":                 noParams(func() Validator { return Int() }),
+		"int8":                noParams(func() Validator { return Int8() }),
+		"int16":               noParams(func() Validator { return Int16() }),
+		"int32":               noParams(func() Validator { return Int32() }),
+		"int64":               noParams(func() Validator { return Int64() }),
+		"uint":                noParams(func() Validator { return Uint() }),
+		"uint8":               noParams(func() Validator { return Uint8() }),
+		"uint16":              noParams(func() Validator { return Uint16() }),
+		"uint32":              noParams(func() Validator { return Uint32() }),
+		"uint64":              noParams(func() Validator { return Uint64() }),
+		"float32":             noParams(func() Validator { return Float32() }),
+		"float64":             noParams(func() Validator { return Float64() }),
+		"array":               noParams(func() Validator { return Array() }),
+		"object":              noParams(func() Validator { return Object() }),
+		"email":               noParams(func() Validator { return Email() }),
+		"url":                 noParams(func() Validator { return URL() }),
+		"ip":                  noParams(func() Validator { return IP() }),
+		"ipv4":                noParams(func() Validator { return IPv4() }),
+		"ipv6":                noParams(func() Validator { return IPv6() }),
+		"json":                noParams(func() Validator { return JSON() }),
+		"alpha":               noParams(func() Validator { return Alpha() }),
+		"alpha_dash":          noParams(func() Validator { return AlphaDash() }),
+		"alpha_num":           noParams(func() Validator { return AlphaNum() }),
+		"digits":              noParams(func() Validator { return Digits() }),
+		"timezone":            noParams(func() Validator { return Timezone() }),
+		"trim":                noParams(func() Validator { return Trim() }),
+		"lowercase":           noParams(func() Validator { return Lowercase() }),
+		"uppercase":           noParams(func() Validator { return Uppercase() }),
+		"nfc":                 noParams(func() Validator { return NFC() }),
+		"strip_html":          noParams(func() Validator { return StripHTML() }),
+		"collapse_whitespace": noParams(func() Validator { return CollapseWhitespace() }),
+		"to_slug":             noParams(func() Validator { return ToSlug() }),
+		"default":             defaultTag,
+		"file":                noParams(func() Validator { return File() }),
+		"image":               noParams(func() Validator { return Image() }),
+		"date":                func(ctx *TagContext) (Validator, error) { return Date(ctx.Params...), nil },
+		"uuid":                uuidTag,
+		"after":               time1(func(t time.Time) Validator { return After(t) }),
+		"after_equal":         time1(func(t time.Time) Validator { return AfterEqual(t) }),
+		"before":              time1(func(t time.Time) Validator { return Before(t) }),
+		"before_equal":        time1(func(t time.Time) Validator { return BeforeEqual(t) }),
+		"date_equals":         time1(func(t time.Time) Validator { return DateEquals(t) }),
+		"after_field":         path1(func(p string) Validator { return AfterField(p) }),
+		"after_equal_field":   path1(func(p string) Validator { return AfterEqualField(p) }),
+		"before_field":        path1(func(p string) Validator { return BeforeField(p) }),
+		"before_equal_field":  path1(func(p string) Validator { return BeforeEqualField(p) }),
+		"date_equals_field":   path1(func(p string) Validator { return DateEqualsField(p) }),
+		"min":                 float1(func(p float64) Validator { return Min(p) }),
+		"max":                 float1(func(p float64) Validator { return Max(p) }),
+		"between":             betweenTag,
+		"size":                sizeTag,
+		"file_count":          uint1(func(p uint) Validator { return FileCount(p) }),
+		"min_file_count":      uint1(func(p uint) Validator { return MinFileCount(p) }),
+		"max_file_count":      uint1(func(p uint) Validator { return MaxFileCount(p) }),
+		"file_count_between":  fileCountBetweenTag,
+		"greater_than":        path1(func(p string) Validator { return GreaterThan(p) }),
+		"greater_than_equal":  path1(func(p string) Validator { return GreaterThanEqual(p) }),
+		"lower_than":          path1(func(p string) Validator { return LowerThan(p) }),
+		"lower_than_equal":    path1(func(p string) Validator { return LowerThanEqual(p) }),
+		"same":                path1(func(p string) Validator { return Same(p) }),
+		"different":           path1(func(p string) Validator { return Different(p) }),
+		"starts_with":         strings1(func(p ...string) Validator { return StartsWith(p...) }),
+		"ends_with":           strings1(func(p ...string) Validator { return EndsWith(p...) }),
+		"doesnt_start_with":   strings1(func(p ...string) Validator { return DoesntStartWith(p...) }),
+		"doesnt_end_with":     strings1(func(p ...string) Validator { return DoesntEndWith(p...) }),
+		"keys_in":             strings1(func(p ...string) Validator { return KeysIn(p...) }),
+		"extension":           strings1(func(p ...string) Validator { return Extension(p...) }),
+		"mime":                strings1(func(p ...string) Validator { return MIME(p...) }),
+		"regex":               regexTag,
+		"in":                  inTag(false),
+		"not_in":              inTag(true),
+		"distinct":            distinctTag,
+		"exists":              tableColumn(func(table, column string) Validator { return ExistsBatch(table, column) }),
+		"unique":              tableColumn(func(table, column string) Validator { return UniqueBatch(table, column) }),
+	}
+	tagRegistryMu sync.RWMutex
+
+	// restrictedTags the rules that are not imported by `FromJSONSchema()` unless allowed
+	// explicitly: rules querying the database and rules registered with `RegisterTag()`.
+	restrictedTags = map[string]struct{}{
+		"exists": {},
+		"unique": {},
+	}
+
+	structPlans sync.Map // reflect.Type -> []*structFieldPlan
+	tagRegexes  sync.Map // string -> *regexp.Regexp
+
+	typeTime         = reflect.TypeOf(time.Time{})
+	typeUUID         = reflect.TypeOf(uuid.UUID{})
+	typeFile         = reflect.TypeOf(fsutil.File{})
+	typeRawMessage   = reflect.TypeOf(json.RawMessage{})
+	typeUnmarshaler  = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	typeTextUnmarsh  = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	undefinedPkgPath = "goyave.dev/goyave/v5/util/typeutil"
+)
+
+// RegisterTag registers a new rule name usable in validation tags, or replaces
+// an existing one. If the given factory is `nil`, the rule is removed from the registry.
+// Rules should be registered at startup, before the first call to `FromStruct()`
+// because the parsed tags are cached per type.
+func RegisterTag(name string, factory TagValidatorFactory) {
+	tagRegistryMu.Lock()
+	defer tagRegistryMu.Unlock()
+	if factory == nil {
+		delete(tagRegistry, name)
+		return
+	}
+	tagRegistry[name] = factory
+	restrictedTags[name] = struct{}{}
+}
+
+func isRestrictedTag(name string) bool {
+	tagRegistryMu.RLock()
+	defer tagRegistryMu.RUnlock()
+	_, ok := restrictedTags[name]
+	return ok
+}
+
+func lookupTag(name string) (TagValidatorFactory, bool) {
+	tagRegistryMu.RLock()
+	defer tagRegistryMu.RUnlock()
+	f, ok := tagRegistry[name]
+	return f, ok
+}
+
+func registeredTag(name string) TagValidatorFactory {
+	f, _ := lookupTag(name)
+	return f
+}
+
+type structRulePlan struct {
+	factory TagValidatorFactory
+	ctx     *TagContext
+}
+
+type structFieldPlan struct {
+	path  string
+	rules []structRulePlan
+}
+
+// FromStruct generates a `RuleSet` from the fields of the struct `T`, using
+// the same paths as its JSON representation (the `json` tag is respected).
+//
+// The type rules are inferred from the Go types of the fields:
+//   - `string`, `bool`, integers and floats get their matching type validator (`Int64()`, `Float32()`, etc)
+//   - Structs and maps get `Object()`. The fields of nested structs are added to the `RuleSet` as well.
+//   - Slices and arrays get `Array()` and their elements are validated at the path `field[]`.
+//   - `[]fsutil.File` gets `File()`, `time.Time` gets `Date(time.RFC3339)` and `uuid.UUID` gets `UUID()`.
+//   - Pointers get `Nullable()`.
+//   - `typeutil.Undefined[T]` is unwrapped.
+//
+// Additional rules are read from the `validate` tag. Rules are separated by commas.
+// Parameters are written after an equal sign and separated by pipes. Escaping is not supported.
+//
+//	type UserRequest struct {
+//		Email string   `json:"email" validate:"required,email,max=255"`
+//		Roles []string `json:"roles" validate:"min=1,dive,in=admin|user"`
+//		Age   typeutil.Undefined[int] `json:"age" validate:"between=18|150"`
+//	}
+//
+// The rules following `dive` apply to the elements of the field. If the tag contains a type rule,
+// the type inferred from the Go type is not added. Use `validate:"-"` to ignore a field.
+// Custom rules can be added using `RegisterTag()`.
+//
+// The tags are parsed once per type but a new `RuleSet` with new validators is returned
+// on every call. Panics if `T` is not a struct or if a tag is invalid.
+func FromStruct[T any]() RuleSet {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	var plan []*structFieldPlan
+	if p, ok := structPlans.Load(t); ok {
+		plan = p.([]*structFieldPlan)
+	} else {
+		if t.Kind() != reflect.Struct {
+			panic(errors.NewSkip(fmt.Errorf("validation.FromStruct: %s is not a struct", t), 3))
+		}
+		p := &structPlanner{plan: []*structFieldPlan{}, visiting: map[reflect.Type]struct{}{}}
+		if err := p.addStruct(t, ""); err != nil {
+			panic(errors.NewSkip(fmt.Errorf("validation.FromStruct: %w", err), 3))
+		}
+		plan = p.plan
+		structPlans.Store(t, plan)
+	}
+
+	set := make(RuleSet, 0, len(plan))
+	for _, field := range plan {
+		list := make(List, 0, len(field.rules))
+		for _, r := range field.rules {
+			v, err := r.factory(r.ctx)
+			if err != nil {
+				panic(errors.NewSkip(fmt.Errorf("validation.FromStruct: %w", err), 3))
+			}
+			list = append(list, v)
+		}
+		set = append(set, &FieldRules{Path: field.path, Rules: list})
+	}
+	return set
+}
+
+type structPlanner struct {
+	visiting map[reflect.Type]struct{}
+	plan     []*structFieldPlan
+}
+
+func (p *structPlanner) addStruct(t reflect.Type, prefix string) error {
+	p.visiting[t] = struct{}{}
+	defer delete(p.visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup(TagName)
+		if tag == "-" {
+			continue
+		}
+		name, ok := jsonFieldName(f)
+		if !ok {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && !hasTag {
+				if _, ok := p.visiting[embedded]; !ok {
+					if err := p.addStruct(embedded, prefix); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		if err := p.addField(f.Type, prefix+name, splitTag(tag)); err != nil {
+			return fmt.Errorf("field %q: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func (p *structPlanner) addField(t reflect.Type, path string, rules []string) error {
+	nullable := false
+	for {
+		if t.Kind() == reflect.Pointer {
+			nullable = true
+			t = t.Elem()
+			continue
+		}
+		if isUndefined(t) {
+			t = t.Field(0).Type
+			continue
+		}
+		break
+	}
+
+	fieldRules, elemRules := rules, []string(nil)
+	for i, r := range rules {
+		if r == TagDive {
+			fieldRules, elemRules = rules[:i], rules[i+1:]
+			break
+		}
+	}
+
+	plans := make([]structRulePlan, 0, len(fieldRules)+2)
+	hasType := false
+	presenceCount := 0
+	for _, r := range fieldRules {
+		name, params := parseTagRule(r)
+		factory, ok := lookupTag(name)
+		if !ok {
+			return fmt.Errorf("unknown validation rule %q", name)
+		}
+		ctx := &TagContext{Type: t, Path: path, Params: params}
+		v, err := factory(ctx)
+		if err != nil {
+			return fmt.Errorf("rule %q: %w", name, err)
+		}
+		if v.IsType() {
+			hasType = true
+		}
+		plan := structRulePlan{factory: factory, ctx: ctx}
+		switch v.(type) {
+		case *RequiredValidator, *NullableValidator:
+			plans = append(plans, structRulePlan{})
+			copy(plans[presenceCount+1:], plans[presenceCount:])
+			plans[presenceCount] = plan
+			presenceCount++
+			if _, isNullable := v.(*NullableValidator); isNullable {
+				nullable = false
+			}
+		default:
+			plans = append(plans, plan)
+		}
+	}
+
+	inferred := make([]structRulePlan, 0, 2)
+	if nullable {
+		if factory := registeredTag("nullable"); factory != nil {
+			inferred = append(inferred, structRulePlan{factory: factory, ctx: &TagContext{Type: t, Path: path, Params: []string{}}})
+		}
+	}
+	if !hasType {
+		if factory := inferTypeRule(t); factory != nil {
+			inferred = append(inferred, structRulePlan{factory: factory, ctx: &TagContext{Type: t, Path: path, Params: []string{}}})
+		}
+	}
+	plans = append(plans[:presenceCount], append(inferred, plans[presenceCount:]...)...)
+	p.plan = append(p.plan, &structFieldPlan{path: path, rules: plans})
+
+	switch {
+	case t == typeFile || t == typeRawMessage || isOpaque(t):
+	case t.Kind() == reflect.Struct:
+		if len(elemRules) > 0 {
+			return fmt.Errorf("%q can only be used on slices and arrays", TagDive)
+		}
+		if _, ok := p.visiting[t]; !ok {
+			return p.addStruct(t, path+".")
+		}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if t.Elem() == typeFile {
+			if len(elemRules) > 0 {
+				return fmt.Errorf("%q cannot be used on files", TagDive)
+			}
+			return nil
+		}
+		return p.addField(t.Elem(), path+"[]", elemRules)
+	default:
+		if len(elemRules) > 0 {
+			return fmt.Errorf("%q can only be used on slices and arrays", TagDive)
+		}
+	}
+	return nil
+}
+
+func inferTypeRule(t reflect.Type) TagValidatorFactory {
+	switch t {
+	case typeTime:
+		return func(_ *TagContext) (Validator, error) { return Date(time.RFC3339), nil }
+	case typeUUID:
+		return registeredTag("uuid")
+	case typeFile:
+		return registeredTag("file")
+	case typeRawMessage:
+		return nil
+	}
+	if isOpaque(t) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return registeredTag("string")
+	case reflect.Bool:
+		return registeredTag("bool")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return registeredTag(t.Kind().String())
+	case reflect.Struct, reflect.Map:
+		return registeredTag("object")
+	case reflect.Slice, reflect.Array:
+		if t.Elem() == typeFile {
+			return registeredTag("file")
+		}
+		return registeredTag("array")
+	}
+	return nil
+}
+
+// isOpaque returns true if the given type implements its own unmarshaling.
+// The type of the expected value cannot be inferred from the structure of these types.
+func isOpaque(t reflect.Type) bool {
+	ptr := reflect.PointerTo(t)
+	return ptr.Implements(typeUnmarshaler) || ptr.Implements(typeTextUnmarsh)
+}
+
+func isUndefined(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == undefinedPkgPath && strings.HasPrefix(t.Name(), "Undefined[")
+}
+
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	return name, true
+}
+
+func splitTag(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+	rules := strings.Split(tag, ",")
+	for i, r := range rules {
+		rules[i] = strings.TrimSpace(r)
+	}
+	return rules
+}
+
+func parseTagRule(rule string) (string, []string) {
+	name, params, ok := strings.Cut(rule, "=")
+	if !ok {
+		return name, []string{}
+	}
+	return name, strings.Split(params, "|")
+}
+
+//------------------------------
+
+func noParams(f func() Validator) TagValidatorFactory {
+	return func(ctx *TagContext) (Validator, error) {
+		if len(ctx.Params) > 0 {
+			return nil, fmt.Errorf("expected no parameter, got %d", len(ctx.Params))
+		}
+		return f(), nil
+	}
+}
+
+func expectParams(ctx *TagContext, n int) error {
+	if len(ctx.Params) != n {
+		return fmt.Errorf("expected %d parameter(s), got %d", n, len(ctx.Params))
+	}
+	return nil
+}
+
+func float1(f func(p float64) Validator) TagValidatorFactory {
+	return func(ctx *TagContext) (Validator, error) {
+		if err := expectParams(ctx, 1); err != nil {
+			return nil, err
+		}
+		p, err := strconv.ParseFloat(ctx.Params[0], 64)
+		if err != nil {
+			return nil, err
+		}
+		return f(p), nil
+	}
+}
+
+func uint1(f func(p uint) Validator) TagValidatorFactory {
+	return func(ctx *TagContext) (Validator, error) {
+		if err := expectParams(ctx, 1); err != nil {
+			return nil, err
+		}
+		p, err := strconv.ParseUint(ctx.Params[0], 10, 0)
+		if err != nil {
+			return nil, err
+		}
+		return f(uint(p)), nil
+	}
+}
+
+// time1 parses the single parameter of the rule as an RFC3339 date.
+func time1(f func(t time.Time) Validator) TagValidatorFactory {
+	return func(ctx *TagContext) (Validator, error) {
+		if err := expectParams(ctx, 1); err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(time.RFC3339Nano, ctx.Params[0])
+		if err != nil {
+			return nil, err
+		}
+		return f(t), nil
+	}
+}
+
+func path1(f func(p string) Validator) TagValidatorFactory {
+	return func(ctx *TagContext) (Validator, error) {
+		if err := expectParams(ctx, 1); err != nil {
+			return nil, err
+		}
+		return f(ctx.Params[0]), nil
+	}
+}
+
+func strings1(f func(p ...string) Validator) TagValidatorFactory {
+	return func(ctx *TagContext) (Validator, error) {
+		if len(ctx.Params) == 0 {
+			return nil, fmt.Errorf("expected at least 1 parameter")
+		}
+		return f(ctx.Params...), nil
+	}
+}
+
+func tableColumn(f func(table, column string) Validator) TagValidatorFactory {
+	return func(ctx *TagContext) (Validator, error) {
+		if err := expectParams(ctx, 2); err != nil {
+			return nil, err
+		}
+		if err := checkBatchIdentifiers(ctx.Params[0], ctx.Params[1]); err != nil {
+			return nil, err
+		}
+		return f(ctx.Params[0], ctx.Params[1]), nil
+	}
+}
+
+func fileCountBetweenTag(ctx *TagContext) (Validator, error) {
+	if err := expectParams(ctx, 2); err != nil {
+		return nil, err
+	}
+	min, err := strconv.ParseUint(ctx.Params[0], 10, 0)
+	if err != nil {
+		return nil, err
+	}
+	max, err := strconv.ParseUint(ctx.Params[1], 10, 0)
+	if err != nil {
+		return nil, err
+	}
+	return FileCountBetween(uint(min), uint(max)), nil
+}
+
+func betweenTag(ctx *TagContext) (Validator, error) {
+	if err := expectParams(ctx, 2); err != nil {
+		return nil, err
+	}
+	min, err := strconv.ParseFloat(ctx.Params[0], 64)
+	if err != nil {
+		return nil, err
+	}
+	max, err := strconv.ParseFloat(ctx.Params[1], 64)
+	if err != nil {
+		return nil, err
+	}
+	return Between(min, max), nil
+}
+
+func sizeTag(ctx *TagContext) (Validator, error) {
+	if err := expectParams(ctx, 1); err != nil {
+		return nil, err
+	}
+	size, err := strconv.Atoi(ctx.Params[0])
+	if err != nil {
+		return nil, err
+	}
+	return Size(size), nil
+}
+
+func uuidTag(ctx *TagContext) (Validator, error) {
+	versions := make([]uuid.Version, 0, len(ctx.Params))
+	for _, p := range ctx.Params {
+		v, err := strconv.ParseUint(p, 10, 8)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, uuid.Version(v))
+	}
+	return UUID(versions...), nil
+}
+
+func defaultTag(ctx *TagContext) (Validator, error) {
+	if len(ctx.Params) == 0 {
+		return nil, fmt.Errorf("expected a default value")
+	}
+	// The raw value is converted by the type rule of the field, like input values.
+	return Default(strings.Join(ctx.Params, "|")), nil
+}
+
+func regexTag(ctx *TagContext) (Validator, error) {
+	if len(ctx.Params) == 0 {
+		return nil, fmt.Errorf("expected a regular expression")
+	}
+	// Pipes are the parameter separator but are also valid in regular expressions.
+	pattern := strings.Join(ctx.Params, "|")
+	if r, ok := tagRegexes.Load(pattern); ok {
+		return Regex(r.(*regexp.Regexp)), nil
+	}
+	r, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	tagRegexes.Store(pattern, r)
+	return Regex(r), nil
+}
+
+func inTag(not bool) TagValidatorFactory {
+	return func(ctx *TagContext) (Validator, error) {
+		if len(ctx.Params) == 0 {
+			return nil, fmt.Errorf("expected at least 1 parameter")
+		}
+		// The values must have the exact type of the value after conversion by the type rule.
+		switch ctx.Type.Kind() {
+		case reflect.Int:
+			return newInValidator(ctx.Params, not, parseSigned[int])
+		case reflect.Int8:
+			return newInValidator(ctx.Params, not, parseSigned[int8])
+		case reflect.Int16:
+			return newInValidator(ctx.Params, not, parseSigned[int16])
+		case reflect.Int32:
+			return newInValidator(ctx.Params, not, parseSigned[int32])
+		case reflect.Int64:
+			return newInValidator(ctx.Params, not, parseSigned[int64])
+		case reflect.Uint:
+			return newInValidator(ctx.Params, not, parseUnsigned[uint])
+		case reflect.Uint8:
+			return newInValidator(ctx.Params, not, parseUnsigned[uint8])
+		case reflect.Uint16:
+			return newInValidator(ctx.Params, not, parseUnsigned[uint16])
+		case reflect.Uint32:
+			return newInValidator(ctx.Params, not, parseUnsigned[uint32])
+		case reflect.Uint64:
+			return newInValidator(ctx.Params, not, parseUnsigned[uint64])
+		case reflect.Float32:
+			return newInValidator(ctx.Params, not, parseFloat[float32])
+		case reflect.Float64:
+			return newInValidator(ctx.Params, not, parseFloat[float64])
+		default:
+			return newInValidator(ctx.Params, not, func(s string) (string, error) { return s, nil })
+		}
+	}
+}
+
+func newInValidator[T comparable](params []string, not bool, parse func(string) (T, error)) (Validator, error) {
+	values := make([]T, 0, len(params))
+	for _, p := range params {
+		v, err := parse(p)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	if not {
+		return NotIn(values), nil
+	}
+	return In(values), nil
+}
+
+func parseSigned[T int | int8 | int16 | int32 | int64](s string) (T, error) {
+	v, err := strconv.ParseInt(s, 10, reflect.TypeOf(T(0)).Bits())
+	return T(v), err
+}
+
+func parseUnsigned[T uint | uint8 | uint16 | uint32 | uint64](s string) (T, error) {
+	v, err := strconv.ParseUint(s, 10, reflect.TypeOf(T(0)).Bits())
+	return T(v), err
+}
+
+func parseFloat[T float32 | float64](s string) (T, error) {
+	v, err := strconv.ParseFloat(s, reflect.TypeOf(T(0)).Bits())
+	return T(v), err
+}
+
+func distinctTag(ctx *TagContext) (Validator, error) {
+	if len(ctx.Params) > 0 {
+		return nil, fmt.Errorf("expected no parameter, got %d", len(ctx.Params))
+	}
+	elem := ctx.Type
+	if elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array {
+		elem = elem.Elem()
+	}
+	switch elem.Kind() {
+	case reflect.Int:
+		return Distinct[int](), nil
+	case reflect.Int8:
+		return Distinct[int8](), nil
+	case reflect.Int16:
+		return Distinct[int16](), nil
+	case reflect.Int32:
+		return Distinct[int32](), nil
+	case reflect.Int64:
+		return Distinct[int64](), nil
+	case reflect.Uint:
+		return Distinct[uint](), nil
+	case reflect.Uint8:
+		return Distinct[uint8](), nil
+	case reflect.Uint16:
+		return Distinct[uint16](), nil
+	case reflect.Uint32:
+		return Distinct[uint32](), nil
+	case reflect.Uint64:
+		return Distinct[uint64](), nil
+	case reflect.Float32:
+		return Distinct[float32](), nil
+	case reflect.Float64:
+		return Distinct[float64](), nil
+	case reflect.Bool:
+		return Distinct[bool](), nil
+	default:
+		return Distinct[string](), nil
+	}
+}
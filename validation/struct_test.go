@@ -0,0 +1,298 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/lang"
+	"goyave.dev/goyave/v5/util/fsutil"
+	"goyave.dev/goyave/v5/util/typeutil"
+)
+
+type structTestEmbedded struct {
+	Embedded string `json:"embedded"`
+}
+
+type structTestAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type structTestNode struct {
+	Name     string           `json:"name"`
+	Children []structTestNode `json:"children"`
+}
+
+type structTestRequest struct {
+	structTestEmbedded
+	Email      string                    `json:"email" validate:"required,email,max=255"`
+	Age        typeutil.Undefined[uint8] `json:"age" validate:"between=18|150"`
+	Score      *float64                  `json:"score"`
+	Note       *string                   `json:"note" validate:"required,nullable"`
+	Roles      []string                  `json:"roles" validate:"min=1,distinct,dive,in=admin|user"`
+	Levels     []int16                   `json:"levels" validate:"dive,not_in=0"`
+	Matrix     [][]int                   `json:"matrix"`
+	Address    structTestAddress         `json:"address" validate:"required"`
+	Addresses  []structTestAddress       `json:"addresses"`
+	Meta       map[string]any            `json:"meta"`
+	Any        any                       `json:"any"`
+	Birthday   time.Time                 `json:"birthday"`
+	ID         uuid.UUID                 `json:"id"`
+	Files      []fsutil.File             `json:"files" validate:"max_file_count=2"`
+	Code       string                    `json:"code" validate:"digits,size=4"`
+	Numeric    string                    `json:"numeric" validate:"int"`
+	NoTag      bool
+	Ignored    string `json:"-"`
+	Skipped    string `json:"skipped" validate:"-"`
+	unexported string //nolint:unused
+}
+
+func ruleNames(r FieldRulesConverter) []string {
+	list := r.(List)
+	names := make([]string, 0, len(list))
+	for _, v := range list {
+		names = append(names, v.Name())
+	}
+	return names
+}
+
+func TestFromStruct(t *testing.T) {
+	t.Run("rules", func(t *testing.T) {
+		set := FromStruct[structTestRequest]()
+
+		got := make(map[string][]string, len(set))
+		paths := make([]string, 0, len(set))
+		for _, f := range set {
+			got[f.Path] = ruleNames(f.Rules)
+			paths = append(paths, f.Path)
+		}
+
+		want := map[string][]string{
+			"embedded":         {"string"},
+			"email":            {"required", "email", "max"},
+			"age":              {"uint8", "between"},
+			"score":            {"nullable", "float64"},
+			"note":             {"required", "nullable", "string"},
+			"roles":            {"array", "min", "distinct"},
+			"roles[]":          {"string", "in"},
+			"levels":           {"array"},
+			"levels[]":         {"int16", "not_in"},
+			"matrix":           {"array"},
+			"matrix[]":         {"array"},
+			"matrix[][]":       {"int"},
+			"address":          {"required", "object"},
+			"address.city":     {"required", "string"},
+			"addresses":        {"array"},
+			"addresses[]":      {"object"},
+			"addresses[].city": {"required", "string"},
+			"meta":             {"object"},
+			"any":              {},
+			"birthday":         {"date"},
+			"id":               {"uuid"},
+			"files":            {"file", "max_file_count"},
+			"code":             {"string", "digits", "size"},
+			"numeric":          {"int"},
+			"NoTag":            {"bool"},
+		}
+		assert.Equal(t, want, got)
+		assert.Equal(t, "embedded", paths[0])
+
+		// Typed validators
+		for _, f := range set {
+			switch f.Path {
+			case "roles[]":
+				assert.Equal(t, []string{"admin", "user"}, f.Rules.(List)[1].(*InValidator[string]).Values)
+			case "levels[]":
+				assert.Equal(t, []int16{0}, f.Rules.(List)[1].(*NotInValidator[int16]).Values)
+			case "roles":
+				assert.IsType(t, &DistinctValidator[string]{}, f.Rules.(List)[2])
+			case "birthday":
+				assert.Equal(t, []string{time.RFC3339}, f.Rules.(List)[0].(*DateValidator).Formats)
+			}
+		}
+	})
+
+	t.Run("new_instances", func(t *testing.T) {
+		a := FromStruct[structTestRequest]()
+		b := FromStruct[structTestRequest]()
+		require.Len(t, b, len(a))
+		for i := range a {
+			for j, v := range a[i].Rules.(List) {
+				assert.NotSame(t, v, b[i].Rules.(List)[j])
+			}
+		}
+	})
+
+	t.Run("recursive_type", func(t *testing.T) {
+		set := FromStruct[structTestNode]()
+		paths := make([]string, 0, len(set))
+		for _, f := range set {
+			paths = append(paths, f.Path)
+		}
+		assert.Equal(t, []string{"name", "children", "children[]"}, paths)
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		type request struct {
+			Name  string                  `json:"name" validate:"required,max=5"`
+			Count typeutil.Undefined[int] `json:"count" validate:"min=2"`
+			Tags  []string                `json:"tags" validate:"dive,in=a|b"`
+		}
+
+		data := map[string]any{"name": "abcdef", "tags": []any{"a", "c"}}
+		errs, errsBag := Validate(&Options{
+			Data:     data,
+			Rules:    FromStruct[request](),
+			Language: lang.New().GetDefault(),
+		})
+		assert.Nil(t, errsBag)
+		require.NotNil(t, errs)
+		assert.Contains(t, errs.Fields, "name")
+		assert.Contains(t, errs.Fields, "tags")
+		assert.NotContains(t, errs.Fields, "count")
+		assert.Contains(t, errs.Fields["tags"].Elements, 1)
+
+		data = map[string]any{"name": "abc", "count": 3.0, "tags": []any{"a", "b"}}
+		errs, errsBag = Validate(&Options{
+			Data:     data,
+			Rules:    FromStruct[request](),
+			Language: lang.New().GetDefault(),
+		})
+		assert.Nil(t, errsBag)
+		assert.Nil(t, errs)
+		assert.Equal(t, 3, data["count"])
+		assert.Equal(t, []string{"a", "b"}, data["tags"])
+	})
+
+	t.Run("custom_tag", func(t *testing.T) {
+		var gotCtx *TagContext
+		RegisterTag("test_custom", func(ctx *TagContext) (Validator, error) {
+			gotCtx = ctx
+			return &testValidator{}, nil
+		})
+		t.Cleanup(func() { RegisterTag("test_custom", nil) })
+
+		type request struct {
+			Field int64 `json:"field" validate:"test_custom=a|b"`
+		}
+		set := FromStruct[request]()
+		require.Len(t, set, 1)
+		require.Len(t, set[0].Rules.(List), 2)
+		assert.IsType(t, &testValidator{}, set[0].Rules.(List)[1])
+		assert.Equal(t, reflect.TypeOf(int64(0)), gotCtx.Type)
+		assert.Equal(t, "field", gotCtx.Path)
+		assert.Equal(t, []string{"a", "b"}, gotCtx.Params)
+
+		_, ok := lookupTag("test_custom")
+		assert.True(t, ok)
+		RegisterTag("test_custom", nil)
+		_, ok = lookupTag("test_custom")
+		assert.False(t, ok)
+	})
+
+	t.Run("panics", func(t *testing.T) {
+		cases := []struct {
+			f    func()
+			desc string
+		}{
+			{desc: "not_struct", f: func() { FromStruct[string]() }},
+			{desc: "unknown_rule", f: func() {
+				FromStruct[struct {
+					A string `validate:"unknown"`
+				}]()
+			}},
+			{desc: "invalid_param", f: func() {
+				FromStruct[struct {
+					A int `validate:"min=abc"`
+				}]()
+			}},
+			{desc: "params_count", f: func() {
+				FromStruct[struct {
+					A int `validate:"between=1"`
+				}]()
+			}},
+			{desc: "unexpected_param", f: func() {
+				FromStruct[struct {
+					A int `validate:"required=1"`
+				}]()
+			}},
+			{desc: "invalid_regex", f: func() {
+				FromStruct[struct {
+					A string `validate:"regex=["`
+				}]()
+			}},
+			{desc: "dive_not_slice", f: func() {
+				FromStruct[struct {
+					A string `validate:"dive,required"`
+				}]()
+			}},
+			{desc: "exists_params", f: func() {
+				FromStruct[struct {
+					A int `validate:"exists=models"`
+				}]()
+			}},
+			{desc: "invalid_in", f: func() {
+				FromStruct[struct {
+					A int8 `validate:"in=1000"`
+				}]()
+			}},
+		}
+
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				assert.Panics(t, c.f)
+			})
+		}
+	})
+
+	t.Run("database", func(t *testing.T) {
+		type request struct {
+			A int    `validate:"exists=models|id"`
+			B string `validate:"unique=models|name"`
+		}
+		set := FromStruct[request]()
+		assert.Equal(t, &ExistsBatchValidator{Table: "models", Column: "id"}, set[0].Rules.(List)[1])
+		assert.Equal(t, UniqueBatch("models", "name"), set[1].Rules.(List)[1])
+	})
+
+	t.Run("transformers", func(t *testing.T) {
+		type request struct {
+			Email string `validate:"trim,lowercase,nfc,collapse_whitespace,strip_html,uppercase,to_slug"`
+			Page  int    `validate:"default=1|2"`
+		}
+		set := FromStruct[request]()
+		assert.Equal(t, []string{"string", "trim", "lowercase", "nfc", "collapse_whitespace", "strip_html", "uppercase", "to_slug"}, ruleNames(set[0].Rules))
+		assert.Equal(t, Default("1|2"), set[1].Rules.(List)[1])
+	})
+
+	t.Run("regex", func(t *testing.T) {
+		type request struct {
+			A string `validate:"regex=^(a|b)$"`
+		}
+		set := FromStruct[request]()
+		assert.Equal(t, "^(a|b)$", set[0].Rules.(List)[1].(*RegexValidator).Regexp.String())
+		set2 := FromStruct[request]()
+		assert.Same(t, set[0].Rules.(List)[1].(*RegexValidator).Regexp, set2[0].Rules.(List)[1].(*RegexValidator).Regexp)
+	})
+}
+
+func ExampleFromStruct() {
+	type UserRequest struct {
+		Email string                  `json:"email" validate:"required,email,max=255"`
+		Age   typeutil.Undefined[int] `json:"age" validate:"between=18|150"`
+		Roles []string                `json:"roles" validate:"min=1,dive,in=admin|user"`
+	}
+
+	for _, f := range FromStruct[UserRequest]() {
+		fmt.Println(f.Path, ruleNames(f.Rules))
+	}
+	// Output:
+	// email [required email max]
+	// age [int between]
+	// roles [array min]
+	// roles[] [string in]
+}
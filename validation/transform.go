@@ -0,0 +1,385 @@
+package validation
+
+import (
+	"html"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/samber/lo"
+	"golang.org/x/text/unicode/norm"
+)
+
+// LowercaseValidator if the field under validation is a string, converts it to lower case.
+type LowercaseValidator struct{ BaseValidator }
+
+// Validate always returns true. If the field under validation is a string,
+// converts it to lower case.
+func (v *LowercaseValidator) Validate(ctx *Context) bool {
+	if str, ok := ctx.Value.(string); ok {
+		ctx.Value = strings.ToLower(str)
+	}
+	return true
+}
+
+// Name returns the string name of the validator.
+func (v *LowercaseValidator) Name() string { return "lowercase" }
+
+// Lowercase if the field under validation is a string, converts it to lower case.
+// Use it before `Unique` or `Exists` to make the lookups case-insensitive, for example
+// for email addresses.
+func Lowercase() *LowercaseValidator {
+	return &LowercaseValidator{}
+}
+
+//------------------------------
+
+// UppercaseValidator if the field under validation is a string, converts it to upper case.
+type UppercaseValidator struct{ BaseValidator }
+
+// Validate always returns true. If the field under validation is a string,
+// converts it to upper case.
+func (v *UppercaseValidator) Validate(ctx *Context) bool {
+	if str, ok := ctx.Value.(string); ok {
+		ctx.Value = strings.ToUpper(str)
+	}
+	return true
+}
+
+// Name returns the string name of the validator.
+func (v *UppercaseValidator) Name() string { return "uppercase" }
+
+// Uppercase if the field under validation is a string, converts it to upper case.
+func Uppercase() *UppercaseValidator {
+	return &UppercaseValidator{}
+}
+
+//------------------------------
+
+// NFCValidator if the field under validation is a string, applies the Unicode
+// Normalization Form C to it.
+type NFCValidator struct{ BaseValidator }
+
+// Validate always returns true. If the field under validation is a string,
+// applies the Unicode Normalization Form C to it.
+func (v *NFCValidator) Validate(ctx *Context) bool {
+	if str, ok := ctx.Value.(string); ok {
+		ctx.Value = norm.NFC.String(str)
+	}
+	return true
+}
+
+// Name returns the string name of the validator.
+func (v *NFCValidator) Name() string { return "nfc" }
+
+// NFC if the field under validation is a string, applies the Unicode Normalization Form C
+// (canonical composition) to it. This ensures visually identical strings using
+// different code points sequences (e.g. "é" and "e" followed by a combining acute accent)
+// are equal once validated.
+func NFC() *NFCValidator {
+	return &NFCValidator{}
+}
+
+//------------------------------
+
+// StripHTMLValidator if the field under validation is a string, removes the HTML tags from it.
+type StripHTMLValidator struct{ BaseValidator }
+
+// Validate always returns true. If the field under validation is a string,
+// unescapes the HTML entities and removes the HTML tags and comments from it.
+func (v *StripHTMLValidator) Validate(ctx *Context) bool {
+	if str, ok := ctx.Value.(string); ok {
+		ctx.Value = stripHTML(str)
+	}
+	return true
+}
+
+// Name returns the string name of the validator.
+func (v *StripHTMLValidator) Name() string { return "strip_html" }
+
+// StripHTML if the field under validation is a string, unescapes the HTML entities
+// (e.g. "&amp;" becomes "&") and removes the HTML tags and comments from it.
+// Only the tags are removed: the content of elements such as `<script>` is kept as text.
+// Entities are unescaped first so escaped markup is removed as well: the result never
+// contains tags.
+//
+// This is not a replacement for escaping user input when rendering HTML.
+func StripHTML() *StripHTMLValidator {
+	return &StripHTMLValidator{}
+}
+
+func stripHTML(str string) string {
+	str = html.UnescapeString(str)
+	// Removing a tag can form a new one (e.g. "<<b>script>"), repeat until nothing is removed.
+	for {
+		stripped := stripHTMLTags(str)
+		if stripped == str {
+			return stripped
+		}
+		str = stripped
+	}
+}
+
+func stripHTMLTags(str string) string {
+	var b strings.Builder
+	b.Grow(len(str))
+	for i := 0; i < len(str); i++ {
+		if str[i] != '<' || i+1 >= len(str) || !isTagStart(str[i+1]) {
+			b.WriteByte(str[i])
+			continue
+		}
+		if strings.HasPrefix(str[i:], "<!--") {
+			end := strings.Index(str[i+4:], "-->")
+			if end == -1 {
+				break
+			}
+			i += 4 + end + 2
+			continue
+		}
+		// Skip until the end of the tag, ignoring ">" in quoted attribute values.
+		var quote byte
+		j := i + 1
+		for ; j < len(str); j++ {
+			c := str[j]
+			if quote != 0 {
+				if c == quote {
+					quote = 0
+				}
+				continue
+			}
+			if c == '"' || c == '\'' {
+				quote = c
+			} else if c == '>' {
+				break
+			}
+		}
+		i = j
+	}
+	return b.String()
+}
+
+func isTagStart(c byte) bool {
+	return c == '/' || c == '!' || c == '?' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+//------------------------------
+
+// CollapseWhitespaceValidator if the field under validation is a string, trims it and
+// replaces all sequences of whitespace characters with a single space.
+type CollapseWhitespaceValidator struct{ BaseValidator }
+
+// Validate always returns true. If the field under validation is a string, trims it and
+// replaces all sequences of whitespace characters with a single space.
+func (v *CollapseWhitespaceValidator) Validate(ctx *Context) bool {
+	if str, ok := ctx.Value.(string); ok {
+		ctx.Value = strings.Join(strings.Fields(str), " ")
+	}
+	return true
+}
+
+// Name returns the string name of the validator.
+func (v *CollapseWhitespaceValidator) Name() string { return "collapse_whitespace" }
+
+// CollapseWhitespace if the field under validation is a string, trims it and replaces all
+// sequences of whitespace characters (as defined by `unicode.IsSpace`) with a single space.
+func CollapseWhitespace() *CollapseWhitespaceValidator {
+	return &CollapseWhitespaceValidator{}
+}
+
+//------------------------------
+
+// SlugValidator if the field under validation is a string, converts it to a slug.
+type SlugValidator struct{ BaseValidator }
+
+// Validate always returns true. If the field under validation is a string,
+// converts it to a slug.
+func (v *SlugValidator) Validate(ctx *Context) bool {
+	if str, ok := ctx.Value.(string); ok {
+		ctx.Value = toSlug(str)
+	}
+	return true
+}
+
+// Name returns the string name of the validator.
+func (v *SlugValidator) Name() string { return "to_slug" }
+
+// ToSlug if the field under validation is a string, converts it to a slug: the string is
+// converted to lower case, diacritics are removed and all sequences of characters
+// that are not letters or digits are replaced with a single hyphen.
+// For example, "  Héllo, World! " becomes "hello-world".
+func ToSlug() *SlugValidator {
+	return &SlugValidator{}
+}
+
+func toSlug(str string) string {
+	var b strings.Builder
+	b.Grow(len(str))
+	hyphen := false
+	for _, r := range norm.NFD.String(str) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Diacritic
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			hyphen = true
+		}
+	}
+	return norm.NFC.String(b.String())
+}
+
+//------------------------------
+
+// DefaultValidator sets the field under validation to the given value
+// if it is missing or `nil`. See `Default()`.
+type DefaultValidator struct {
+	BaseValidator
+	Value any
+}
+
+// Validate always returns true. The default value is set by `validation.Validate()`
+// before executing the validators of the field.
+func (v *DefaultValidator) Validate(_ *Context) bool {
+	return true
+}
+
+// Name returns the string name of the validator.
+func (v *DefaultValidator) Name() string { return "default" }
+
+// Default if the field under validation is missing or `nil`, it is set to the given value.
+// The default value is set before executing the validators of the field, regardless of
+// the position of this rule in the list, so the default value is validated and converted
+// by the other rules like any other value. A field having a default value is therefore
+// never missing. Only fields of objects and array elements can receive a default value.
+//
+// Maps and slices are deep copied each time the default value is applied, so they
+// can be safely modified by the other rules and by the handler.
+func Default(value any) *DefaultValidator {
+	return &DefaultValidator{Value: value}
+}
+
+// copyDefaultValue returns a deep copy of the given value if it is a map or a slice.
+// Other values are returned as is.
+func copyDefaultValue(value any) any {
+	switch val := value.(type) {
+	case map[string]any:
+		clone := make(map[string]any, len(val))
+		for k, v := range val {
+			clone[k] = copyDefaultValue(v)
+		}
+		return clone
+	case []any:
+		clone := make([]any, len(val))
+		for i, v := range val {
+			clone[i] = copyDefaultValue(v)
+		}
+		return clone
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return value
+		}
+		clone := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			clone.SetMapIndex(iter.Key(), copyDefaultReflectValue(iter.Value()))
+		}
+		return clone.Interface()
+	case reflect.Slice:
+		if v.IsNil() {
+			return value
+		}
+		clone := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			clone.Index(i).Set(copyDefaultReflectValue(v.Index(i)))
+		}
+		return clone.Interface()
+	}
+	return value
+}
+
+func copyDefaultReflectValue(v reflect.Value) reflect.Value {
+	if !v.CanInterface() {
+		return v
+	}
+	clone := copyDefaultValue(v.Interface())
+	if clone == nil {
+		return v
+	}
+	return reflect.ValueOf(clone).Convert(v.Type())
+}
+
+//------------------------------
+
+// EnumValidator validates the field under validation is a string matching one of the
+// names of the enumeration and converts it to the associated value.
+type EnumValidator[T any] struct {
+	BaseValidator
+	Values map[string]T
+}
+
+// Validate checks the field under validation satisfies this validator's criteria.
+// If the validation passes, the value is replaced with the enum value.
+func (v *EnumValidator[T]) Validate(ctx *Context) bool {
+	str, ok := ctx.Value.(string)
+	if !ok {
+		return false
+	}
+	value, ok := v.Values[str]
+	if ok {
+		ctx.Value = value
+	}
+	return ok
+}
+
+// Name returns the string name of the validator.
+func (v *EnumValidator[T]) Name() string { return "enum" }
+
+// IsType returns true.
+func (v *EnumValidator[T]) IsType() bool { return true }
+
+// MessagePlaceholders returns the ":values" placeholder.
+func (v *EnumValidator[T]) MessagePlaceholders(_ *Context) []string {
+	names := lo.Keys(v.Values)
+	slices.Sort(names)
+	return []string{
+		":values", strings.Join(names, ", "),
+	}
+}
+
+// Enum the field under validation must be a string equal to one of the given values,
+// which are typically the constants of a string-based enum type.
+// On success, the value is converted to `T`.
+//
+//	type Status string
+//	const (
+//		StatusDraft     Status = "draft"
+//		StatusPublished Status = "published"
+//	)
+//
+//	validation.Enum(StatusDraft, StatusPublished)
+func Enum[T ~string](values ...T) *EnumValidator[T] {
+	m := make(map[string]T, len(values))
+	for _, v := range values {
+		m[string(v)] = v
+	}
+	return &EnumValidator[T]{Values: m}
+}
+
+// EnumMap the field under validation must be a string equal to one of the keys of the given
+// map. On success, the value is converted to the associated value. This can be used for
+// enum types that are not string-based.
+//
+//	type Level int
+//	validation.EnumMap(map[string]Level{"low": LevelLow, "high": LevelHigh})
+func EnumMap[T any](values map[string]T) *EnumValidator[T] {
+	return &EnumValidator[T]{Values: values}
+}
@@ -0,0 +1,198 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/lang"
+)
+
+type transformTestStatus string
+
+const (
+	transformTestStatusDraft     transformTestStatus = "draft"
+	transformTestStatusPublished transformTestStatus = "published"
+)
+
+func TestTransformers(t *testing.T) {
+	t.Run("Constructors", func(t *testing.T) {
+		cases := []struct {
+			v    Validator
+			name string
+		}{
+			{v: Lowercase(), name: "lowercase"},
+			{v: Uppercase(), name: "uppercase"},
+			{v: NFC(), name: "nfc"},
+			{v: StripHTML(), name: "strip_html"},
+			{v: CollapseWhitespace(), name: "collapse_whitespace"},
+			{v: ToSlug(), name: "to_slug"},
+			{v: Default(1), name: "default"},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				assert.Equal(t, c.name, c.v.Name())
+				assert.False(t, c.v.IsType())
+				assert.False(t, c.v.IsTypeDependent())
+				assert.Empty(t, c.v.MessagePlaceholders(&Context{}))
+			})
+		}
+	})
+
+	cases := []struct {
+		v     func() Validator
+		value any
+		want  any
+		desc  string
+	}{
+		{desc: "lowercase", v: func() Validator { return Lowercase() }, value: "John.DOE@Example.com", want: "john.doe@example.com"},
+		{desc: "lowercase_not_string", v: func() Validator { return Lowercase() }, value: 1, want: 1},
+		{desc: "uppercase", v: func() Validator { return Uppercase() }, value: "fr-fr", want: "FR-FR"},
+		{desc: "uppercase_not_string", v: func() Validator { return Uppercase() }, value: []string{"a"}, want: []string{"a"}},
+		{desc: "nfc", v: func() Validator { return NFC() }, value: "été", want: "été"},
+		{desc: "nfc_not_string", v: func() Validator { return NFC() }, value: nil, want: nil},
+		{desc: "strip_html", v: func() Validator { return StripHTML() }, value: `<p class="a>b">Hello <b>world</b></p><!-- comment -->&amp; 1 < 2<br/>`, want: "Hello world& 1 < 2"},
+		{desc: "strip_html_escaped_tags", v: func() Validator { return StripHTML() }, value: "&lt;script&gt;alert(1)&lt;/script&gt;", want: "alert(1)"},
+		{desc: "strip_html_nested_tags", v: func() Validator { return StripHTML() }, value: "<<b>script>alert(1)<</b>/script>", want: "alert(1)"},
+		{desc: "strip_html_unclosed_comment", v: func() Validator { return StripHTML() }, value: "a<!-- b", want: "a"},
+		{desc: "strip_html_not_string", v: func() Validator { return StripHTML() }, value: 2.5, want: 2.5},
+		{desc: "collapse_whitespace", v: func() Validator { return CollapseWhitespace() }, value: " \t hello \n\n  world  ", want: "hello world"},
+		{desc: "collapse_whitespace_not_string", v: func() Validator { return CollapseWhitespace() }, value: true, want: true},
+		{desc: "to_slug", v: func() Validator { return ToSlug() }, value: "  Héllo, World! 2024 ", want: "hello-world-2024"},
+		{desc: "to_slug_unicode", v: func() Validator { return ToSlug() }, value: "Ærø -- Über_Straße", want: "ærø-uber-straße"},
+		{desc: "to_slug_not_string", v: func() Validator { return ToSlug() }, value: 1, want: 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			ctx := &Context{Value: c.value}
+			assert.True(t, c.v().Validate(ctx))
+			assert.Equal(t, c.want, ctx.Value)
+		})
+	}
+}
+
+func TestEnumValidator(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		v := Enum(transformTestStatusPublished, transformTestStatusDraft)
+		assert.NotNil(t, v)
+		assert.Equal(t, "enum", v.Name())
+		assert.True(t, v.IsType())
+		assert.False(t, v.IsTypeDependent())
+		assert.Equal(t, []string{":values", "draft, published"}, v.MessagePlaceholders(&Context{}))
+		assert.Equal(t, map[string]transformTestStatus{"draft": transformTestStatusDraft, "published": transformTestStatusPublished}, v.Values)
+
+		v2 := EnumMap(map[string]int{"low": 1, "high": 2})
+		assert.Equal(t, map[string]int{"low": 1, "high": 2}, v2.Values)
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		v := Enum(transformTestStatusDraft, transformTestStatusPublished)
+		ctx := &Context{Value: "draft"}
+		assert.True(t, v.Validate(ctx))
+		assert.Equal(t, transformTestStatusDraft, ctx.Value)
+
+		assert.False(t, v.Validate(&Context{Value: "archived"}))
+		assert.False(t, v.Validate(&Context{Value: transformTestStatusDraft}))
+		assert.False(t, v.Validate(&Context{Value: 1}))
+
+		v2 := EnumMap(map[string]int{"low": 1, "high": 2})
+		ctx = &Context{Value: "high"}
+		assert.True(t, v2.Validate(ctx))
+		assert.Equal(t, 2, ctx.Value)
+	})
+
+	t.Run("message", func(t *testing.T) {
+		errs, _ := Validate(&Options{
+			Data:     map[string]any{"status": "archived"},
+			Language: lang.New().GetDefault(),
+			Rules:    RuleSet{{Path: "status", Rules: List{Required(), Enum(transformTestStatusDraft, transformTestStatusPublished)}}},
+		})
+		require.NotNil(t, errs)
+		assert.Equal(t, []string{"The status must be one of the following values: draft, published."}, errs.Fields["status"].Errors)
+	})
+}
+
+func TestDefault(t *testing.T) {
+	t.Run("Validate", func(t *testing.T) {
+		data := map[string]any{
+			"nil":      nil,
+			"present":  "value",
+			"items":    []any{map[string]any{"qty": nil}, map[string]any{}},
+			"elements": []any{"a", nil},
+		}
+		errs, errsBag := Validate(&Options{
+			Data:     data,
+			Language: lang.New().GetDefault(),
+			Rules: RuleSet{
+				{Path: "missing", Rules: List{Required(), Int(), Default("5")}},
+				{Path: "nil", Rules: List{String(), Default("default")}},
+				{Path: "present", Rules: List{String(), Default("default")}},
+				{Path: "invalid", Rules: List{Default("abc"), Int()}},
+				{Path: "items", Rules: List{Array()}},
+				{Path: "items[]", Rules: List{Object()}},
+				{Path: "items[].qty", Rules: List{Default(1), Int(), Min(1)}},
+				{Path: "elements", Rules: List{Array()}},
+				{Path: "elements[]", Rules: List{Default("z"), String()}},
+				{Path: "object.nested", Rules: List{Default("unused")}},
+			},
+		})
+		assert.Nil(t, errsBag)
+		require.NotNil(t, errs)
+		assert.Equal(t, FieldsErrors{"invalid": &Errors{Errors: []string{"The invalid must be an integer."}}}, errs.Fields)
+		assert.Equal(t, 5, data["missing"])
+		assert.Equal(t, "default", data["nil"])
+		assert.Equal(t, "value", data["present"])
+		assert.Equal(t, []map[string]any{{"qty": 1}, {"qty": 1}}, data["items"])
+		assert.Equal(t, []string{"a", "z"}, data["elements"])
+		assert.NotContains(t, data, "object")
+	})
+
+	t.Run("nullable", func(t *testing.T) {
+		data := map[string]any{"a": nil}
+		errs, errsBag := Validate(&Options{
+			Data:     data,
+			Language: lang.New().GetDefault(),
+			Rules:    RuleSet{{Path: "a", Rules: List{Nullable(), String(), Default("x")}}},
+		})
+		assert.Nil(t, errsBag)
+		assert.Nil(t, errs)
+		assert.Equal(t, "x", data["a"])
+	})
+
+	t.Run("copyDefaultValue", func(t *testing.T) {
+		value := map[string]any{
+			"array":   []any{map[string]any{"a": 1}, nil},
+			"strings": []string{"a"},
+			"typed":   map[string][]int{"b": {1}},
+			"nil":     []string(nil),
+		}
+		clone := copyDefaultValue(value).(map[string]any)
+		assert.Equal(t, value, clone)
+
+		clone["array"].([]any)[0].(map[string]any)["a"] = 2
+		clone["strings"].([]string)[0] = "b"
+		clone["typed"].(map[string][]int)["b"][0] = 2
+		assert.Equal(t, map[string]any{"a": 1}, value["array"].([]any)[0])
+		assert.Equal(t, []string{"a"}, value["strings"])
+		assert.Equal(t, map[string][]int{"b": {1}}, value["typed"])
+		assert.Equal(t, "value", copyDefaultValue("value"))
+	})
+}
+
+func TestTransformBeforeUniqueBatch(t *testing.T) {
+	opts := prepareUniqueTest(t)
+	require.NoError(t, opts.DB.Create([]uniqueTestModel{{ID: 1, Name: "john@example.com"}}).Error)
+
+	data := map[string]any{"email": "  John@Example.COM "}
+	opts.Data = data
+	opts.Language = lang.New().GetDefault()
+	opts.Rules = RuleSet{
+		{Path: "email", Rules: List{Required(), String(), Trim(), Lowercase(), UniqueBatch("models", "name")}},
+	}
+	errs, errsBag := Validate(opts)
+	assert.Nil(t, errsBag)
+	require.NotNil(t, errs)
+	assert.Equal(t, []string{"The email address has already been taken."}, errs.Fields["email"].Errors)
+	assert.Equal(t, "john@example.com", data["email"])
+}
@@ -65,6 +65,9 @@ func (v *UniqueValidator) Name() string { return "unique" }
 // Unique validates the field under validation must have a unique value in database
 // according to the provided database scope. Uniqueness is checked using a COUNT query.
 //
+// This executes one query per validated value. When validating many values, for example
+// array elements, prefer `UniqueBatch`, which executes a single query for all of them.
+//
 //	 v.Unique(func(db *gorm.DB, val any) *gorm.DB {
 //		return db.Model(&model.User{}).Where(clause.PrimaryKey, val)
 //	 })
@@ -96,6 +99,9 @@ func (v *ExistsValidator) Name() string { return "exists" }
 // Exists validates the field under validation must have exist database
 // according to the provided database scope. Existence is checked using a COUNT query.
 //
+// This executes one query per validated value. When validating many values, for example
+// array elements, prefer `ExistsBatch`, which executes a single query for all of them.
+//
 //	 v.Exists(func(db *gorm.DB, val any) *gorm.DB {
 //		return db.Model(&model.User{}).Where(clause.PrimaryKey, val)
 //	 })
@@ -251,6 +257,9 @@ func (v *ExistsArrayValidator[T]) Name() string { return "exists" }
 // If provided, the `Transform` function is called on every array element to transform
 // them into a raw expression. For example to transform a number into `(123::int)` for
 // Postgres to prevent some type errors.
+//
+// `ExistsBatch` on the array elements (path `array[]`) also executes a single query,
+// batched with the lookups of the other fields, and supports scopes.
 func ExistsArray[T any](table, column string, transform func(val T) clause.Expr) *ExistsArrayValidator[T] {
 	return &ExistsArrayValidator[T]{
 		Table:     table,
@@ -295,6 +304,9 @@ func (v *UniqueArrayValidator[T]) Name() string { return "unique" }
 // If provided, the `Transform` function is called on every array element to transform
 // them into a raw expression. For example to transform a number into `(123::int)` for
 // Postgres to prevent some type errors.
+//
+// `UniqueBatch` on the array elements (path `array[]`) also executes a single query,
+// batched with the lookups of the other fields, and supports scopes.
 func UniqueArray[T any](table, column string, transform func(val T) clause.Expr) *UniqueArrayValidator[T] {
 	return &UniqueArrayValidator[T]{
 		ExistsArrayValidator: ExistsArrayValidator[T]{
@@ -134,6 +134,11 @@ type Options struct {
 	//  field=A         --> map[string]any{"field": []string{"A"}}
 	//  field=A&field=B --> map[string]any{"field": []string{"A", "B"}}
 	ConvertSingleValueArrays bool
+
+	// RecordViolations set to true to record the name of the rule and the message
+	// placeholders of each error in `Errors.Violations`, in addition to the message.
+	// This is required to get complete results from `Errors.ViolationList()`.
+	RecordViolations bool
 }
 
 type addedValidationErrorConstraint interface {
@@ -255,6 +260,7 @@ type validator struct {
 	options          *Options
 	now              time.Time
 	errors           []error
+	batch            batch
 }
 
 // Validate the given data using the given `Options`.
@@ -293,6 +299,7 @@ func Validate(options *Options) (*Errors, []error) {
 			validator.validateField(*field.Path.Tail().Name, field, options.Data, nil)
 		}
 	}
+	validator.executeBatch()
 
 	if len(validator.errors) != 0 {
 		return nil, validator.errors
@@ -305,6 +312,7 @@ func Validate(options *Options) (*Errors, []error) {
 
 func (v *validator) validateField(fieldName string, field *Field, walkData any, parentPath *walk.Path) {
 	field.Path.Walk(walkData, func(c *walk.Context) {
+		applyDefault(field, c)
 		parentObject, parentIsObject := c.Parent.(map[string]any)
 		shouldDeleteFromParent := v.shouldDeleteFromParent(field, parentIsObject, c.Value)
 		if c.Found == walk.Found {
@@ -318,7 +326,14 @@ func (v *validator) validateField(fieldName string, field *Field, walkData any,
 			}
 		}
 
-		if v.isAbsent(field, c, v.options.Data) {
+		if v.isExcluded(field, c, parentPath) {
+			if c.Found == walk.Found && parentIsObject {
+				delete(parentObject, c.Name)
+			}
+			return
+		}
+
+		if v.isAbsent(field, c, parentPath) {
 			return
 		}
 
@@ -341,16 +356,7 @@ func (v *validator) validateField(fieldName string, field *Field, walkData any,
 			v.validateField(fieldName+"[]", field.Elements, c.Value, path)
 		}
 
-		data := v.options.Data
-
-		if field.prefixDepth > 0 {
-			fullPath := appendPath(parentPath, c.Path, c.Index)
-			if rootPath := fullPath.Truncate(field.prefixDepth); rootPath != nil {
-				// We can use `First` here because the path contains array indexes
-				// so we are sure there will be only one match.
-				data = rootPath.First(data).Value
-			}
-		}
+		data := v.getData(field, c, parentPath)
 
 		value := c.Value
 		valid := true
@@ -376,6 +382,12 @@ func (v *validator) validateField(fieldName string, field *Field, walkData any,
 				Invalid:   !valid,
 			}
 			validator.init(v.options)
+			if batchValidator, isBatch := validator.(BatchValidator); isBatch {
+				if lookup, ok := batchValidator.Lookup(ctx); ok {
+					v.batch.add(lookup, &pendingLookup{ctx: ctx, validator: batchValidator, errorPath: errorPath, fieldName: fieldName})
+					continue
+				}
+			}
 			ok := validator.Validate(ctx)
 			if len(ctx.errors) > 0 {
 				valid = false
@@ -384,12 +396,7 @@ func (v *validator) validateField(fieldName string, field *Field, walkData any,
 			}
 			if !ok {
 				valid = false
-				message := v.getMessage(ctx, validator)
-				if v.isRootElement(fieldName, errorPath) {
-					v.validationErrors.Add(errorPath, message)
-				} else {
-					v.validationErrors.Add(&walk.Path{Type: walk.PathTypeObject, Next: errorPath}, message)
-				}
+				v.addValidationError(fieldName, errorPath, v.newViolation(ctx, validator, v.getMessage(ctx, validator)))
 				continue
 			}
 
@@ -404,10 +411,68 @@ func (v *validator) validateField(fieldName string, field *Field, walkData any,
 	})
 }
 
+func (v *validator) addValidationError(fieldName string, errorPath *walk.Path, violation *Violation) {
+	if !v.isRootElement(fieldName, errorPath) {
+		errorPath = &walk.Path{Type: walk.PathTypeObject, Next: errorPath}
+	}
+	v.addViolation(errorPath, violation)
+}
+
+// addViolation adds the message of the given violation to the validation errors.
+// The violation itself is only recorded if `Options.RecordViolations` is enabled.
+func (v *validator) addViolation(errorPath *walk.Path, violation *Violation) {
+	if v.options.RecordViolations {
+		v.validationErrors.AddViolation(errorPath, violation)
+	} else {
+		v.validationErrors.Add(errorPath, violation.Message)
+	}
+}
+
+// newViolation returns the details of the error reported by the given validator.
+func (v *validator) newViolation(ctx *Context, validator Validator, message string) *Violation {
+	violation := &Violation{Rule: validator.Name(), Message: message}
+	if !v.options.RecordViolations {
+		return violation
+	}
+	placeholders := validator.MessagePlaceholders(ctx)
+	if len(placeholders) > 1 {
+		violation.Params = make(map[string]string, len(placeholders)/2)
+		for i := 0; i < len(placeholders)-1; i += 2 {
+			violation.Params[strings.TrimPrefix(placeholders[i], ":")] = placeholders[i+1]
+		}
+	}
+	return violation
+}
+
 func (v *validator) isRootElement(fieldName string, errorPath *walk.Path) bool {
 	return fieldName == CurrentElement || (errorPath.Type == walk.PathTypeArray && (errorPath.Name == nil || *errorPath.Name == CurrentElement))
 }
 
+// applyDefault sets the field to its default value (see `Default()`) in its parent
+// if it is missing or `nil`.
+func applyDefault(field *Field, c *walk.Context) {
+	if field.defaultValue == nil || (c.Found == walk.Found && c.Value != nil) {
+		return
+	}
+	value := copyDefaultValue(field.defaultValue.Value)
+	switch parent := c.Parent.(type) {
+	case map[string]any:
+		if c.Found == walk.ParentNotFound {
+			return
+		}
+		parent[c.Name] = value
+	case []any:
+		if c.Found != walk.Found {
+			return
+		}
+		parent[c.Index] = value
+	default:
+		return
+	}
+	c.Value = value
+	c.Found = walk.Found
+}
+
 func (v *validator) shouldDeleteFromParent(field *Field, parentIsObject bool, value any) bool {
 	return parentIsObject && !field.IsNullable() && value == nil
 }
@@ -428,17 +493,51 @@ func (v *validator) convertSingleValueArray(field *Field, value any) any {
 	return value
 }
 
-func (v *validator) isAbsent(field *Field, c *walk.Context, data any) bool {
-	if c.Found == walk.ParentNotFound {
-		return true
+// getData returns the data the validators of the given field are executed with.
+// When using composition, this is the root element of the composed rule set.
+func (v *validator) getData(field *Field, c *walk.Context, parentPath *walk.Path) any {
+	data := v.options.Data
+	if field.prefixDepth > 0 {
+		fullPath := appendPath(parentPath, c.Path, c.Index)
+		if rootPath := fullPath.Truncate(field.prefixDepth); rootPath != nil {
+			// We can use `First` here because the path contains array indexes
+			// so we are sure there will be only one match.
+			data = rootPath.First(data).Value
+		}
 	}
-	requiredCtx := &Context{
-		Data:   data,
+	return data
+}
+
+func (v *validator) presenceContext(field *Field, c *walk.Context, parentPath *walk.Path) *Context {
+	return &Context{
+		Data:   v.getData(field, c, parentPath),
 		Extra:  v.options.Extra,
 		Value:  c.Value,
 		Parent: c.Parent,
 		Field:  field,
+		Now:    v.now,
 		Name:   c.Name,
+		path:   field.getErrorPath(parentPath, c),
+	}
+}
+
+func (v *validator) isExcluded(field *Field, c *walk.Context, parentPath *walk.Path) bool {
+	if field.isExcluded == nil || c.Found == walk.ParentNotFound {
+		return false
+	}
+	return field.isExcluded(v.presenceContext(field, c, parentPath))
+}
+
+func (v *validator) isAbsent(field *Field, c *walk.Context, parentPath *walk.Path) bool {
+	if c.Found == walk.ParentNotFound {
+		return true
+	}
+	if field.isMissing && c.Found == walk.Found {
+		return false
+	}
+	requiredCtx := v.presenceContext(field, c, parentPath)
+	if field.requiredIf {
+		requiredCtx.Data = v.options.Data
 	}
 	return !field.IsRequired(requiredCtx) && !(&RequiredValidator{}).Validate(requiredCtx)
 }
@@ -452,7 +551,7 @@ func (v *validator) processAddedErrors(ctx *Context, parentPath *walk.Path, c *w
 	}
 	if len(ctx.arrayElementErrors) > 0 {
 		errorPath := ctx.Field.getErrorPath(parentPath, c)
-		message := v.options.Language.Get(v.getLangEntry(ctx, validator)+".element", v.processPlaceholders(ctx, validator)...)
+		violation := v.newViolation(ctx, validator, v.getElementMessage(ctx, validator))
 		for _, index := range ctx.arrayElementErrors {
 			i := index
 			elementPath := errorPath.Clone()
@@ -460,9 +559,9 @@ func (v *validator) processAddedErrors(ctx *Context, parentPath *walk.Path, c *w
 			elementPath.Index = &i
 			elementPath.Next = &walk.Path{Type: walk.PathTypeElement}
 			if ctx.fieldName == CurrentElement {
-				v.validationErrors.Add(elementPath, message)
+				v.addViolation(elementPath, violation)
 			} else {
-				v.validationErrors.Add(&walk.Path{Type: walk.PathTypeObject, Next: elementPath}, message)
+				v.addViolation(&walk.Path{Type: walk.PathTypeObject, Next: elementPath}, violation)
 			}
 		}
 	}
@@ -498,8 +597,22 @@ func (v *validator) processPlaceholders(ctx *Context, validator Validator) []str
 }
 
 func (v *validator) getMessage(ctx *Context, validator Validator) string {
+	placeholders := v.processPlaceholders(ctx, validator)
+	if message, ok := ctx.Field.messages.find(validator.Name(), false); ok {
+		return message.translate(v.options.Language, placeholders)
+	}
 	langEntry := v.getLangEntry(ctx, validator)
-	return v.options.Language.Get(langEntry, v.processPlaceholders(ctx, validator)...)
+	return v.options.Language.Get(langEntry, placeholders...)
+}
+
+// getElementMessage returns the message used for the array element errors
+// reported by the given validator (see `Context.AddArrayElementValidationErrors()`).
+func (v *validator) getElementMessage(ctx *Context, validator Validator) string {
+	placeholders := v.processPlaceholders(ctx, validator)
+	if message, ok := ctx.Field.messages.find(validator.Name(), true); ok {
+		return message.translate(v.options.Language, placeholders)
+	}
+	return v.options.Language.Get(v.getLangEntry(ctx, validator)+".element", placeholders...)
 }
 
 // findTypeValidator find the expected type of a field for a given array dimension.